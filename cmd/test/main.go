@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 
 	"gitlab.com/uniget-org/cli/pkg/metadata"
@@ -32,7 +33,7 @@ func main() {
 		panic(err)
 	}
 
-	err = unigetMetadataSource.Download(tui.NewProgressReader(nil, nil))
+	err = unigetMetadataSource.Download(context.Background(), tui.NewProgressReader(nil, nil))
 	if err != nil {
 		panic(err)
 	}
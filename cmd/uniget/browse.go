@@ -0,0 +1,604 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/glamour"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"gitlab.com/uniget-org/cli/internal/constants"
+	"gitlab.com/uniget-org/cli/pkg/query"
+	"gitlab.com/uniget-org/cli/pkg/tool"
+)
+
+func initBrowseCmd() {
+	rootCmd.AddCommand(browseCmd)
+}
+
+var browseCmd = &cobra.Command{
+	Use:     "browse",
+	Aliases: []string{},
+	Short:   "Browse tools interactively",
+	Long: constants.Header + "\nBrowse tools interactively" + `
+
+Keys:
+  up/down, pgup/pgdown  Move through the list
+  /                     Filter by search terms or a query like tag:kubernetes
+  enter                 Show details, messages and release notes (r)
+  space                 Select the default action for the tool
+  i, d                  Select to install/upgrade or uninstall the tool
+  U                     Select all outdated tools for upgrade
+  p                     Review the plan and confirm (y)
+  q                     Quit without changes`,
+	GroupID: "tool",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		pbar, _ := pterm.DefaultProgressbar.
+			WithTotal(len(tools.Tools)).
+			WithTitle("Checking").
+			WithRemoveWhenDone().
+			Start()
+		for index := range tools.Tools {
+			err = tools.Tools[index].UpdateStatus(
+				configuration.Prefix,
+				configuration.Target,
+				configuration.GetCacheDirectory(),
+				configuration.Arch,
+				configuration.AltArch,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to update status for tool %s: %s", tools.Tools[index].Name, err)
+			}
+			pbar.Increment()
+		}
+
+		result, err := tea.NewProgram(newBrowseModel(cmd.Context(), tools), tea.WithContext(cmd.Context())).Run()
+		if err != nil {
+			return fmt.Errorf("failed to run browser: %s", err)
+		}
+		model, ok := result.(browseModel)
+		if !ok || !model.confirmed {
+			return nil
+		}
+
+		plan := model.plan
+		if len(plan.Uninstall) > 0 {
+			err = uninstallTools(cmd.Context(), plan.Uninstall, false)
+			if err != nil {
+				return fmt.Errorf("failed to uninstall tools: %s", err)
+			}
+		}
+		requested := append(slices.Clone(plan.Install), plan.Upgrade...)
+		if len(requested) > 0 {
+			err = installTools(cmd.Context(), textOutput(cmd), tools.GetByNames(requested), false, false, false, false, false)
+			if err != nil {
+				return fmt.Errorf("failed to install tools: %w", err)
+			}
+		}
+
+		return nil
+	},
+}
+
+const (
+	browseInstall   = "install"
+	browseUpgrade   = "upgrade"
+	browseUninstall = "uninstall"
+)
+
+const (
+	browseModeList = iota
+	browseModeDetails
+	browseModePlan
+)
+
+// browsePlan lists the tools affected by the selected actions including
+// dependencies which are not installed yet
+type browsePlan struct {
+	Install      []string
+	Upgrade      []string
+	Uninstall    []string
+	Dependencies []string
+}
+
+func (p browsePlan) IsEmpty() bool {
+	return len(p.Install)+len(p.Upgrade)+len(p.Uninstall) == 0
+}
+
+func computeBrowsePlan(tools *tool.Tools, selected map[string]string) (browsePlan, error) {
+	var plan browsePlan
+
+	names := make([]string, 0, len(selected))
+	for name := range selected {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var queue tool.Tools
+	for _, name := range names {
+		switch selected[name] {
+		case browseInstall:
+			plan.Install = append(plan.Install, name)
+		case browseUpgrade:
+			plan.Upgrade = append(plan.Upgrade, name)
+		case browseUninstall:
+			plan.Uninstall = append(plan.Uninstall, name)
+			continue
+		}
+
+		err := tools.ResolveDependencies(&queue, name)
+		if err != nil {
+			return plan, fmt.Errorf("unable to resolve dependencies for %s: %s", name, err)
+		}
+	}
+
+	for _, t := range queue.Tools {
+		_, ok := selected[t.Name]
+		if !ok && !t.IsInstalled() {
+			plan.Dependencies = append(plan.Dependencies, t.Name)
+		}
+	}
+
+	return plan, nil
+}
+
+type releaseNotesMsg struct {
+	tool  string
+	notes string
+	err   error
+}
+
+var (
+	browseHeaderStyle    = lipgloss.NewStyle().Bold(true)
+	browseCursorStyle    = lipgloss.NewStyle().Reverse(true)
+	browseFaintStyle     = lipgloss.NewStyle().Faint(true)
+	browseOutdatedStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	browseInstalledStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	browseErrorStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+)
+
+type browseModel struct {
+	ctx       context.Context
+	tools     *tool.Tools
+	visible   []int
+	cursor    int
+	offset    int
+	filter    string
+	filtering bool
+	selected  map[string]string
+	mode      int
+	details   []string
+	scroll    int
+	plan      browsePlan
+	confirmed bool
+	message   string
+	width     int
+	height    int
+}
+
+func newBrowseModel(ctx context.Context, tools *tool.Tools) browseModel {
+	m := browseModel{
+		ctx:      ctx,
+		tools:    tools,
+		selected: make(map[string]string),
+		width:    80,
+		height:   24,
+	}
+	m.applyFilter()
+	return m
+}
+
+func (m *browseModel) applyFilter() {
+	m.message = ""
+	m.visible = m.visible[:0]
+
+	switch {
+	case len(strings.TrimSpace(m.filter)) == 0:
+		for index := range m.tools.Tools {
+			m.visible = append(m.visible, index)
+		}
+
+	case strings.Contains(m.filter, ":"):
+		q, err := query.Parse(m.filter)
+		if err != nil {
+			m.message = err.Error()
+			return
+		}
+		for index := range m.tools.Tools {
+			if q.Match(&m.tools.Tools[index]) {
+				m.visible = append(m.visible, index)
+			}
+		}
+
+	default:
+		positions := make(map[string]int, len(m.tools.Tools))
+		for index, t := range m.tools.Tools {
+			positions[t.Name] = index
+		}
+		for _, result := range m.tools.Search(strings.Fields(m.filter), tool.AllSearchFields) {
+			m.visible = append(m.visible, positions[result.Tool.Name])
+		}
+	}
+
+	m.cursor = 0
+	m.offset = 0
+}
+
+func (m browseModel) current() *tool.Tool {
+	if m.cursor < 0 || m.cursor >= len(m.visible) {
+		return nil
+	}
+	return &m.tools.Tools[m.visible[m.cursor]]
+}
+
+// defaultAction is install for missing, upgrade for outdated and nothing
+// for current tools
+func defaultAction(t *tool.Tool) string {
+	switch {
+	case t.IsUpgradable():
+		return browseUpgrade
+	case !t.IsInstalled():
+		return browseInstall
+	}
+	return ""
+}
+
+func (m *browseModel) toggle(t *tool.Tool, action string) {
+	if len(action) == 0 {
+		return
+	}
+	if m.selected[t.Name] == action {
+		delete(m.selected, t.Name)
+		return
+	}
+	m.selected[t.Name] = action
+}
+
+func (m browseModel) listHeight() int {
+	// Header, filter line, column titles and help line
+	return max(m.height-4, 1)
+}
+
+func (m *browseModel) move(delta int) {
+	m.cursor = min(max(m.cursor+delta, 0), max(len(m.visible)-1, 0))
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.listHeight() {
+		m.offset = m.cursor - m.listHeight() + 1
+	}
+}
+
+func (m browseModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m browseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.move(0)
+		return m, nil
+
+	case releaseNotesMsg:
+		// Ignore notes arriving after the details have been closed
+		if t := m.current(); m.mode != browseModeDetails || t == nil || t.Name != msg.tool {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.message = msg.err.Error()
+			return m, nil
+		}
+		m.details = append(m.details, "", "Release notes:")
+		m.details = append(m.details, strings.Split(msg.notes, "\n")...)
+		m.message = ""
+		return m, nil
+
+	case tea.KeyPressMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		switch m.mode {
+		case browseModeDetails:
+			return m.updateDetails(msg)
+		case browseModePlan:
+			return m.updatePlan(msg)
+		}
+		if m.filtering {
+			return m.updateFilter(msg)
+		}
+		return m.updateList(msg)
+	}
+
+	return m, nil
+}
+
+func (m browseModel) updateFilter(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		m.filtering = false
+	case "esc":
+		m.filtering = false
+		m.filter = ""
+		m.applyFilter()
+	case "backspace":
+		if len(m.filter) > 0 {
+			runes := []rune(m.filter)
+			m.filter = string(runes[:len(runes)-1])
+			m.applyFilter()
+		}
+	case "space":
+		m.filter += " "
+		m.applyFilter()
+	default:
+		if len(msg.Text) > 0 {
+			m.filter += msg.Text
+			m.applyFilter()
+		}
+	}
+	return m, nil
+}
+
+func (m browseModel) updateList(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "esc":
+		return m, tea.Quit
+	case "up", "k":
+		m.move(-1)
+	case "down", "j":
+		m.move(1)
+	case "pgup":
+		m.move(-m.listHeight())
+	case "pgdown":
+		m.move(m.listHeight())
+	case "home", "g":
+		m.move(-len(m.visible))
+	case "end", "G":
+		m.move(len(m.visible))
+	case "/":
+		m.filtering = true
+	case "space", "i":
+		if t := m.current(); t != nil {
+			m.toggle(t, defaultAction(t))
+		}
+	case "d":
+		if t := m.current(); t != nil && t.IsInstalled() {
+			m.toggle(t, browseUninstall)
+		}
+	case "U":
+		for index := range m.tools.Tools {
+			if m.tools.Tools[index].IsUpgradable() {
+				m.selected[m.tools.Tools[index].Name] = browseUpgrade
+			}
+		}
+	case "enter":
+		if t := m.current(); t != nil {
+			m.details = describeForBrowse(t)
+			m.scroll = 0
+			m.mode = browseModeDetails
+		}
+	case "p":
+		plan, err := computeBrowsePlan(m.tools, m.selected)
+		if err != nil {
+			m.message = err.Error()
+			break
+		}
+		if plan.IsEmpty() {
+			m.message = "Select tools using space, i or d first"
+			break
+		}
+		m.plan = plan
+		m.mode = browseModePlan
+	}
+	return m, nil
+}
+
+func (m browseModel) updateDetails(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "esc", "enter":
+		m.mode = browseModeList
+		m.message = ""
+	case "up", "k":
+		m.scroll = max(m.scroll-1, 0)
+	case "down", "j":
+		m.scroll = min(m.scroll+1, max(len(m.details)-1, 0))
+	case "pgup":
+		m.scroll = max(m.scroll-m.listHeight(), 0)
+	case "pgdown":
+		m.scroll = min(m.scroll+m.listHeight(), max(len(m.details)-1, 0))
+	case "r":
+		t := m.current()
+		if t == nil {
+			break
+		}
+		m.message = "Fetching release notes..."
+		width := m.width
+		return m, func() tea.Msg {
+			notes, err := fetchReleaseNotes(m.ctx, t, "")
+			if err != nil {
+				return releaseNotesMsg{tool: t.Name, err: err}
+			}
+			renderer, err := glamour.NewTermRenderer(glamour.WithStandardStyle("dark"), glamour.WithWordWrap(max(width-4, 20)))
+			if err == nil {
+				rendered, err := renderer.Render(notes)
+				if err == nil {
+					notes = rendered
+				}
+			}
+			return releaseNotesMsg{tool: t.Name, notes: notes}
+		}
+	}
+	return m, nil
+}
+
+func (m browseModel) updatePlan(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "enter":
+		m.confirmed = true
+		return m, tea.Quit
+	case "n", "esc", "q":
+		m.mode = browseModeList
+	}
+	return m, nil
+}
+
+func describeForBrowse(t *tool.Tool) []string {
+	var buffer bytes.Buffer
+	t.Print(&buffer)
+
+	status := "not installed"
+	switch {
+	case t.IsUpgradable():
+		status = fmt.Sprintf("outdated (%s installed)", t.Status.Version)
+	case t.IsInstalled():
+		status = "installed"
+	}
+	lines := []string{fmt.Sprintf("  Status: %s", status)}
+	lines = append(lines, strings.Split(strings.TrimRight(buffer.String(), "\n"), "\n")...)
+
+	for _, message := range []struct {
+		title string
+		text  string
+	}{
+		{title: "Usage", text: t.ShowUsage(4)},
+		{title: "Update", text: t.ShowUpdate(4)},
+		{title: "Internals", text: t.ShowInternals(4)},
+	} {
+		if len(message.text) == 0 {
+			continue
+		}
+		lines = append(lines, "  "+message.title+":")
+		lines = append(lines, strings.Split(strings.TrimRight(message.text, "\n"), "\n")...)
+	}
+
+	return lines
+}
+
+func truncate(text string, width int) string {
+	runes := []rune(text)
+	if width <= 0 || len(runes) <= width {
+		return text
+	}
+	if width == 1 {
+		return "…"
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+func (m browseModel) View() tea.View {
+	var b strings.Builder
+
+	switch m.mode {
+	case browseModeDetails:
+		t := m.current()
+		b.WriteString(browseHeaderStyle.Render(truncate(fmt.Sprintf("%s %s", t.Name, t.Version), m.width)) + "\n")
+		end := min(m.scroll+m.listHeight()+1, len(m.details))
+		for _, line := range m.details[m.scroll:end] {
+			b.WriteString(line + "\n")
+		}
+		for range m.listHeight() + 1 - (end - m.scroll) {
+			b.WriteString("\n")
+		}
+		m.writeFooter(&b, "up/down scroll • r release notes • esc back")
+
+	case browseModePlan:
+		b.WriteString(browseHeaderStyle.Render("Plan") + "\n\n")
+		for _, group := range []struct {
+			title string
+			names []string
+		}{
+			{title: "Install", names: m.plan.Install},
+			{title: "Upgrade", names: m.plan.Upgrade},
+			{title: "Uninstall", names: m.plan.Uninstall},
+			{title: "Install as dependency", names: m.plan.Dependencies},
+		} {
+			if len(group.names) == 0 {
+				continue
+			}
+			b.WriteString(fmt.Sprintf("%s (%d):\n", group.title, len(group.names)))
+			for _, name := range group.names {
+				b.WriteString("  " + name + "\n")
+			}
+			b.WriteString("\n")
+		}
+		m.writeFooter(&b, "y confirm • esc back")
+
+	default:
+		selected := fmt.Sprintf("%d selected", len(m.selected))
+		b.WriteString(browseHeaderStyle.Render(fmt.Sprintf("%s • %d of %d tools • %s", constants.ProjectName, len(m.visible), len(m.tools.Tools), selected)) + "\n")
+		filter := "/ to filter"
+		if m.filtering || len(m.filter) > 0 {
+			filter = "Filter: " + m.filter
+			if m.filtering {
+				filter += "█"
+			}
+		}
+		b.WriteString(browseFaintStyle.Render(truncate(filter, m.width)) + "\n")
+
+		nameWidth := 24
+		versionWidth := 22
+		descriptionWidth := max(m.width-nameWidth-versionWidth-6, 10)
+		b.WriteString(browseFaintStyle.Render(fmt.Sprintf("   %-*s %-*s %s", nameWidth, "NAME", versionWidth, "VERSION", "DESCRIPTION")) + "\n")
+
+		end := min(m.offset+m.listHeight(), len(m.visible))
+		for position := m.offset; position < end; position++ {
+			t := &m.tools.Tools[m.visible[position]]
+
+			marker := " "
+			switch m.selected[t.Name] {
+			case browseInstall:
+				marker = "+"
+			case browseUpgrade:
+				marker = "^"
+			case browseUninstall:
+				marker = "-"
+			}
+
+			versionText := t.Version
+			versionStyle := lipgloss.NewStyle()
+			switch {
+			case t.IsUpgradable():
+				versionText = fmt.Sprintf("%s → %s", t.Status.Version, t.Version)
+				versionStyle = browseOutdatedStyle
+			case t.IsInstalled():
+				versionText = fmt.Sprintf("%s ✓", t.Version)
+				versionStyle = browseInstalledStyle
+			}
+
+			line := fmt.Sprintf(" %s %-*s %s %s",
+				marker,
+				nameWidth, truncate(t.Name, nameWidth),
+				versionStyle.Render(fmt.Sprintf("%-*s", versionWidth, truncate(versionText, versionWidth))),
+				truncate(t.Description, descriptionWidth),
+			)
+			if position == m.cursor {
+				line = browseCursorStyle.Render(line)
+			}
+			b.WriteString(line + "\n")
+		}
+		for range m.listHeight() - (end - m.offset) {
+			b.WriteString("\n")
+		}
+		m.writeFooter(&b, "space select • d uninstall • U upgrade all • enter details • p plan • q quit")
+	}
+
+	v := tea.NewView(b.String())
+	v.AltScreen = true
+	return v
+}
+
+func (m browseModel) writeFooter(b *strings.Builder, help string) {
+	if len(m.message) > 0 {
+		b.WriteString(browseErrorStyle.Render(truncate(m.message, m.width)))
+		return
+	}
+	b.WriteString(browseFaintStyle.Render(truncate(help, m.width)))
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"gitlab.com/uniget-org/cli/pkg/tool"
+)
+
+func browseTestTools() *tool.Tools {
+	installed := tool.ToolStatus{BinaryPresent: true, MarkerFilePresent: true, VersionMatches: true}
+	outdated := tool.ToolStatus{BinaryPresent: true, MarkerFilePresent: true}
+
+	return &tool.Tools{
+		Tools: []tool.Tool{
+			{Name: "kubectl", Description: "Kubernetes CLI", Tags: []string{"kubernetes"}},
+			{Name: "helm", Description: "Package manager for Kubernetes", Tags: []string{"kubernetes"}, RuntimeDependencies: []string{"kubectl"}},
+			{Name: "jq", Description: "Command-line JSON processor", Status: installed},
+			{Name: "yq", Description: "Portable YAML processor", Status: outdated, RuntimeDependencies: []string{"jq"}},
+		},
+	}
+}
+
+func TestComputeBrowsePlan(t *testing.T) {
+	tools := browseTestTools()
+
+	plan, err := computeBrowsePlan(tools, map[string]string{
+		"helm": browseInstall,
+		"yq":   browseUpgrade,
+		"jq":   browseUninstall,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !slices.Equal(plan.Install, []string{"helm"}) {
+		t.Errorf("expected install of helm, got %v", plan.Install)
+	}
+	if !slices.Equal(plan.Upgrade, []string{"yq"}) {
+		t.Errorf("expected upgrade of yq, got %v", plan.Upgrade)
+	}
+	if !slices.Equal(plan.Uninstall, []string{"jq"}) {
+		t.Errorf("expected uninstall of jq, got %v", plan.Uninstall)
+	}
+	if !slices.Equal(plan.Dependencies, []string{"kubectl"}) {
+		t.Errorf("expected kubectl as dependency, got %v", plan.Dependencies)
+	}
+
+	plan, err = computeBrowsePlan(tools, map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !plan.IsEmpty() {
+		t.Errorf("expected empty plan, got %+v", plan)
+	}
+}
+
+func TestBrowseFilter(t *testing.T) {
+	tt := []struct {
+		filter   string
+		expected []string
+		message  bool
+	}{
+		{filter: "", expected: []string{"kubectl", "helm", "jq", "yq"}},
+		{filter: "helm", expected: []string{"helm"}},
+		{filter: "tag:kubernetes", expected: []string{"kubectl", "helm"}},
+		{filter: "upgradable:true", expected: []string{"yq"}},
+		{filter: "tag:", expected: []string{}, message: true},
+	}
+
+	for _, tc := range tt {
+		m := newBrowseModel(context.Background(), browseTestTools())
+		m.filter = tc.filter
+		m.applyFilter()
+
+		names := []string{}
+		for _, index := range m.visible {
+			names = append(names, m.tools.Tools[index].Name)
+		}
+		if !slices.Equal(names, tc.expected) {
+			t.Errorf("filter %q: expected %v, got %v", tc.filter, tc.expected, names)
+		}
+		if tc.message != (len(m.message) > 0) {
+			t.Errorf("filter %q: unexpected message %q", tc.filter, m.message)
+		}
+	}
+}
+
+func TestBrowseToggle(t *testing.T) {
+	m := newBrowseModel(context.Background(), browseTestTools())
+
+	for index := range m.tools.Tools {
+		current := &m.tools.Tools[index]
+		m.toggle(current, defaultAction(current))
+	}
+	expected := map[string]string{
+		"kubectl": browseInstall,
+		"helm":    browseInstall,
+		"yq":      browseUpgrade,
+	}
+	if len(m.selected) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, m.selected)
+	}
+	for name, action := range expected {
+		if m.selected[name] != action {
+			t.Errorf("expected %s for %s, got %s", action, name, m.selected[name])
+		}
+	}
+
+	m.toggle(&m.tools.Tools[0], browseInstall)
+	if _, ok := m.selected["kubectl"]; ok {
+		t.Errorf("expected kubectl to be deselected")
+	}
+}
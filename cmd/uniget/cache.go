@@ -91,7 +91,7 @@ var cacheStatsCmd = &cobra.Command{
 			if err != nil {
 				return fmt.Errorf("failed to create Docker client: %w", err)
 			}
-			images, err := containers.ListDockerImagesByPrefix(cli, "ghcr.io/uniget-org/tools/")
+			images, err := containers.ListDockerImagesByPrefix(cmd.Context(), cli, "ghcr.io/uniget-org/tools/")
 			if err != nil {
 				return fmt.Errorf("failed to list Docker images: %w", err)
 			}
@@ -142,7 +142,7 @@ var cacheListCmd = &cobra.Command{
 			if err != nil {
 				return fmt.Errorf("failed to create Docker client: %w", err)
 			}
-			images, err := containers.ListDockerImagesByPrefix(cli, "ghcr.io/uniget-org/tools/")
+			images, err := containers.ListDockerImagesByPrefix(cmd.Context(), cli, "ghcr.io/uniget-org/tools/")
 			if err != nil {
 				return fmt.Errorf("failed to list Docker images: %w", err)
 			}
@@ -192,7 +192,7 @@ var cachePruneCmd = &cobra.Command{
 			if err != nil {
 				return fmt.Errorf("failed to create Docker client: %w", err)
 			}
-			images, err := containers.ListDockerImagesByPrefix(cli, "ghcr.io/uniget-org/tools/")
+			images, err := containers.ListDockerImagesByPrefix(cmd.Context(), cli, "ghcr.io/uniget-org/tools/")
 			if err != nil {
 				return fmt.Errorf("failed to list Docker images: %w", err)
 			}
@@ -201,7 +201,7 @@ var cachePruneCmd = &cobra.Command{
 					continue
 				}
 				for _, tag := range img.RepoTags {
-					if err := containers.RemoveDockerImage(cli, tag); err != nil {
+					if err := containers.RemoveDockerImage(cmd.Context(), cli, tag); err != nil {
 						fmt.Fprintf(os.Stderr, "Failed to remove image %s: %v\n", tag, err)
 					} else {
 						count++
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -87,11 +88,11 @@ var describeCmd = &cobra.Command{
 
 		if describeVersions {
 			registries, repositories := tool.GetSourcesWithFallback(constants.Registry, constants.ImageRepository)
-			toolRef, err := containers.FindToolRef(registries, repositories, tool.Name, tool.Version)
+			toolRef, err := containers.FindToolRef(cmd.Context(), registries, repositories, tool.Name, tool.Version)
 			if err != nil {
 				return fmt.Errorf("unable to find tool ref: %s", err)
 			}
-			tags, err := containers.GetImageTags(toolRef)
+			tags, err := containers.GetImageTags(cmd.Context(), toolRef)
 			if err != nil {
 				return fmt.Errorf("failed to get image tags: %s", err)
 			}
@@ -109,31 +110,31 @@ var describeCmd = &cobra.Command{
 			var releaseTags []string
 			switch tool.Renovate.Datasource {
 			case "github-releases":
-				releaseTags, err = fetchGitHubReleases(tool.Renovate.Package)
+				releaseTags, err = fetchGitHubReleases(cmd.Context(), tool.Renovate.Package)
 				if err != nil {
 					return fmt.Errorf("failed to fetch GitHub releases: %s", err)
 				}
 
 			case "gitlab-releases":
-				releaseTags, err = fetchGitLabReleases(tool.Renovate.Package)
+				releaseTags, err = fetchGitLabReleases(cmd.Context(), tool.Renovate.Package)
 				if err != nil {
 					return fmt.Errorf("failed to fetch GitLab releases: %s", err)
 				}
 
 			case "gitea-releases":
-				releaseTags, err = fetchGiteaReleases(tool.Renovate.Package)
+				releaseTags, err = fetchGiteaReleases(cmd.Context(), tool.Renovate.Package)
 				if err != nil {
 					return fmt.Errorf("failed to fetch Gitea releases: %s", err)
 				}
 
 			case "npm":
-				releaseTags, err = fetchNpmReleases(tool.Renovate.Package)
+				releaseTags, err = fetchNpmReleases(cmd.Context(), tool.Renovate.Package)
 				if err != nil {
 					return fmt.Errorf("failed to fetch Gitea releases: %s", err)
 				}
 
 			case "pypi":
-				releaseTags, err = fetchPypiReleases(tool.Renovate.Package)
+				releaseTags, err = fetchPypiReleases(cmd.Context(), tool.Renovate.Package)
 				if err != nil {
 					return fmt.Errorf("failed to fetch Gitea releases: %s", err)
 				}
@@ -181,7 +182,7 @@ func extractVersionfromTag(tag string, regex string) (string, error) {
 	return tag, nil
 }
 
-func fetchGitHubReleases(project string) ([]string, error) {
+func fetchGitHubReleases(ctx context.Context, project string) ([]string, error) {
 	if len(os.Getenv("GITHUB_TOKEN")) == 0 {
 		logging.Warning.Printfln("GITHUB_TOKEN is not set. You may experience failed requests due to rate limiting.")
 	}
@@ -189,7 +190,7 @@ func fetchGitHubReleases(project string) ([]string, error) {
 	url := fmt.Sprintf("https://api.github.com/repos/%s/releases", project)
 	logging.Debugf("Fetching releases from %s", url)
 
-	bodyBytes, err := fetchUrl(url)
+	bodyBytes, err := fetchUrl(ctx, url)
 	if err != nil {
 		return []string{}, fmt.Errorf("failed to fetch body of GitHub release: %s", err)
 	}
@@ -209,12 +210,12 @@ func fetchGitHubReleases(project string) ([]string, error) {
 	return releaseTags, nil
 }
 
-func fetchGitLabReleases(project string) ([]string, error) {
+func fetchGitLabReleases(ctx context.Context, project string) ([]string, error) {
 	projectUrlEncoded := strings.ReplaceAll(project, "/", "%2f")
 	url := fmt.Sprintf("https://gitlab.com/api/v4/projects/%s/releases", projectUrlEncoded)
 	logging.Debugf("Fetching releases from %s", url)
 
-	bodyBytes, err := fetchUrl(url)
+	bodyBytes, err := fetchUrl(ctx, url)
 	if err != nil {
 		return []string{}, fmt.Errorf("failed to fetch body of GitLab release: %s", err)
 	}
@@ -234,11 +235,11 @@ func fetchGitLabReleases(project string) ([]string, error) {
 	return releaseTags, nil
 }
 
-func fetchGiteaReleases(project string) ([]string, error) {
+func fetchGiteaReleases(ctx context.Context, project string) ([]string, error) {
 	url := fmt.Sprintf("https://gitea.com/api/v1/repos/%s/releases", project)
 	logging.Debugf("Fetching releases from %s", url)
 
-	bodyBytes, err := fetchUrl(url)
+	bodyBytes, err := fetchUrl(ctx, url)
 	if err != nil {
 		return []string{}, fmt.Errorf("failed to fetch body of Gitea release: %s", err)
 	}
@@ -258,11 +259,11 @@ func fetchGiteaReleases(project string) ([]string, error) {
 	return releaseTags, nil
 }
 
-func fetchNpmReleases(project string) ([]string, error) {
+func fetchNpmReleases(ctx context.Context, project string) ([]string, error) {
 	url := fmt.Sprintf("https://registry.npmjs.com/%s", project)
 	logging.Debugf("Fetching releases from %s", url)
 
-	bodyBytes, err := fetchUrl(url)
+	bodyBytes, err := fetchUrl(ctx, url)
 	if err != nil {
 		return []string{}, fmt.Errorf("failed to fetch body of npm release: %s", err)
 	}
@@ -282,11 +283,11 @@ func fetchNpmReleases(project string) ([]string, error) {
 	return versionTags, nil
 }
 
-func fetchPypiReleases(project string) ([]string, error) {
+func fetchPypiReleases(ctx context.Context, project string) ([]string, error) {
 	url := fmt.Sprintf("https://pypi.org/pypi/%s/json", project)
 	logging.Debugf("Fetching releases from %s", url)
 
-	bodyBytes, err := fetchUrl(url)
+	bodyBytes, err := fetchUrl(ctx, url)
 	if err != nil {
 		return []string{}, fmt.Errorf("failed to fetch body of pypi release: %s", err)
 	}
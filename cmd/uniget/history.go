@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"gitlab.com/uniget-org/cli/internal/constants"
+	"gitlab.com/uniget-org/cli/pkg/events"
+	"gitlab.com/uniget-org/cli/pkg/history"
+	"gitlab.com/uniget-org/cli/pkg/logging"
+)
+
+var historyTool string
+var historySince string
+var historyOutput string
+
+func initHistoryCmd() {
+	historyCmd.Flags().StringVar(&historyTool, "tool", "", "Show only changes of this tool")
+	historyCmd.Flags().StringVar(&historySince, "since", "", "Show only changes since a date (2006-01-02), timestamp (RFC 3339) or duration (e.g. 36h, 7d)")
+	historyCmd.Flags().StringVarP(&historyOutput, "output", "o", "pretty", "Output options: pretty, json")
+
+	rootCmd.AddCommand(historyCmd)
+}
+
+var historyCmd = &cobra.Command{
+	Use:     "history",
+	Aliases: []string{},
+	Short:   "Show history of changes",
+	Long:    constants.Header + "\nShow history of installed, upgraded and uninstalled tools as well as metadata updates",
+	GroupID: "tool",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		filter := history.Filter{
+			Tool: historyTool,
+		}
+		if len(historySince) > 0 {
+			filter.Since, err = history.ParseSince(historySince, time.Now())
+			if err != nil {
+				return fmt.Errorf("invalid value for --since: %s", err)
+			}
+		}
+
+		entries, err := history.Read(configuration.GetHistoryFile(), filter)
+		if err != nil {
+			return fmt.Errorf("unable to read history: %s", err)
+		}
+
+		switch historyOutput {
+		case "pretty":
+			printHistory(cmd.OutOrStdout(), entries)
+		case "json":
+			data, err := json.Marshal(entries)
+			if err != nil {
+				return fmt.Errorf("failed to marshal to json: %s", err)
+			}
+			//nolint:errcheck
+			fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		default:
+			return fmt.Errorf("invalid output format: %s", historyOutput)
+		}
+
+		return nil
+	},
+}
+
+func printHistory(w io.Writer, entries []history.Entry) {
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+
+	t.AppendHeader(table.Row{"Time", "User", "Action", "Tool", "Old", "New", "Result"})
+	for _, entry := range entries {
+		user := entry.User
+		if len(entry.SudoUser) > 0 {
+			user = fmt.Sprintf("%s (%s)", entry.User, entry.SudoUser)
+		}
+		result := entry.Result
+		if len(entry.Error) > 0 {
+			result = fmt.Sprintf("%s: %s", entry.Result, entry.Error)
+		}
+		t.AppendRow(table.Row{
+			entry.Time.Local().Format(time.DateTime),
+			user,
+			entry.Action,
+			entry.Tool,
+			entry.OldVersion,
+			entry.NewVersion,
+			result,
+		})
+	}
+
+	t.Render()
+}
+
+func recordHistory(event events.Event) {
+	entry, ok := historyEntryFromEvent(event)
+	if !ok {
+		return
+	}
+
+	err := history.Append(configuration.GetHistoryFile(), entry)
+	if err != nil {
+		logging.Warning.Printfln("Unable to record history: %s", err)
+	}
+
+	if configuration.HistorySyslog {
+		err = history.Forward(entry)
+		if err != nil {
+			logging.Warning.Printfln("Unable to forward history to syslog: %s", err)
+		}
+	}
+}
+
+func historyEntryFromEvent(event events.Event) (history.Entry, bool) {
+	var entry history.Entry
+	switch {
+	case event.Type == events.TypeMetadataUpdated:
+		entry = history.NewEntry(history.ActionMetadataUpdate, "", event.OldRevision, event.Revision)
+
+	case event.Type != events.TypeSucceeded && event.Type != events.TypeFailed:
+		return entry, false
+
+	case event.Command == "update":
+		// Successful updates are recorded by TypeMetadataUpdated
+		if event.Type == events.TypeSucceeded {
+			return entry, false
+		}
+		entry = history.NewEntry(history.ActionMetadataUpdate, "", event.OldRevision, event.Revision)
+
+	case event.Command == "uninstall" || event.Reason == history.ActionUninstall:
+		entry = history.NewEntry(history.ActionUninstall, event.Tool, event.Version, "")
+
+	case event.Reason == history.ActionRollback:
+		entry = history.NewEntry(history.ActionRollback, event.Tool, event.OldVersion, event.Version)
+
+	default:
+		action := history.ActionInstall
+		if len(event.OldVersion) > 0 {
+			if event.OldVersion == event.Version {
+				action = history.ActionReinstall
+			} else {
+				action = history.ActionUpgrade
+			}
+		}
+		entry = history.NewEntry(action, event.Tool, event.OldVersion, event.Version)
+	}
+
+	entry.Time = event.Time
+	entry.Digest = event.Digest
+	if event.Type == events.TypeFailed {
+		entry.Result = history.ResultFailed
+		entry.Error = event.Error
+	}
+
+	return entry, true
+}
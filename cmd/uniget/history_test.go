@@ -0,0 +1,91 @@
+package main
+
+import (
+	"testing"
+
+	"gitlab.com/uniget-org/cli/pkg/events"
+	"gitlab.com/uniget-org/cli/pkg/history"
+)
+
+func TestHistoryEntryFromEvent(t *testing.T) {
+	tt := []struct {
+		name   string
+		event  events.Event
+		ok     bool
+		action string
+		result string
+	}{
+		{
+			name:   "install",
+			event:  events.Event{Command: "install", Type: events.TypeSucceeded, Tool: "jq", Version: "1.7.1"},
+			ok:     true,
+			action: history.ActionInstall,
+			result: history.ResultSucceeded,
+		},
+		{
+			name:   "upgrade",
+			event:  events.Event{Command: "upgrade", Type: events.TypeSucceeded, Tool: "jq", Version: "1.7.1", OldVersion: "1.7.0"},
+			ok:     true,
+			action: history.ActionUpgrade,
+			result: history.ResultSucceeded,
+		},
+		{
+			name:   "reinstall",
+			event:  events.Event{Command: "install", Type: events.TypeSucceeded, Tool: "jq", Version: "1.7.1", OldVersion: "1.7.1"},
+			ok:     true,
+			action: history.ActionReinstall,
+			result: history.ResultSucceeded,
+		},
+		{
+			name:   "failed install",
+			event:  events.Event{Command: "install", Type: events.TypeFailed, Tool: "jq", Version: "1.7.1", Error: "boom"},
+			ok:     true,
+			action: history.ActionInstall,
+			result: history.ResultFailed,
+		},
+		{
+			name:   "uninstall",
+			event:  events.Event{Command: "uninstall", Type: events.TypeSucceeded, Tool: "jq", Version: "1.7.1"},
+			ok:     true,
+			action: history.ActionUninstall,
+			result: history.ResultSucceeded,
+		},
+		{
+			name:   "uninstall from browse",
+			event:  events.Event{Command: "browse", Type: events.TypeSucceeded, Tool: "jq", Version: "1.7.1", Reason: history.ActionUninstall},
+			ok:     true,
+			action: history.ActionUninstall,
+			result: history.ResultSucceeded,
+		},
+		{
+			name:   "metadata update",
+			event:  events.Event{Command: "update", Type: events.TypeMetadataUpdated, Revision: "b", OldRevision: "a"},
+			ok:     true,
+			action: history.ActionMetadataUpdate,
+			result: history.ResultSucceeded,
+		},
+		{
+			name:  "skipped",
+			event: events.Event{Command: "install", Type: events.TypeSkipped, Tool: "jq"},
+			ok:    false,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			entry, ok := historyEntryFromEvent(tc.event)
+			if ok != tc.ok {
+				t.Fatalf("expected ok=%t, got %t", tc.ok, ok)
+			}
+			if !ok {
+				return
+			}
+			if entry.Action != tc.action {
+				t.Errorf("expected action %s, got %s", tc.action, entry.Action)
+			}
+			if entry.Result != tc.result {
+				t.Errorf("expected result %s, got %s", tc.result, entry.Result)
+			}
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"gitlab.com/uniget-org/cli/internal/constants"
 	"gitlab.com/uniget-org/cli/pkg/logging"
 	myos "gitlab.com/uniget-org/cli/pkg/os"
+	"gitlab.com/uniget-org/cli/pkg/uniget"
 )
 
 var (
@@ -280,28 +281,28 @@ var listHooksCmd = &cobra.Command{
 				switch availableHookType {
 				case "pre-install":
 					preInstallHooksDir := configuration.GetHooksPreInstallDirectory()
-					err = processHooks(preInstallHooksDir, func(hookFile string) error {
+					err = uniget.ProcessHooks(preInstallHooksDir, func(hookFile string) error {
 						fmt.Printf("%s: %s\n", availableHookType, hookFile)
 						return nil
 					})
 
 				case "post-install":
 					postInstallHooksDir := configuration.GetHooksPostInstallDirectory()
-					err = processHooks(postInstallHooksDir, func(hookFile string) error {
+					err = uniget.ProcessHooks(postInstallHooksDir, func(hookFile string) error {
 						fmt.Printf("%s: %s\n", availableHookType, hookFile)
 						return nil
 					})
 
 				case "pre-uninstall":
 					preUninstallHooksDir := configuration.GetHooksPreUninstallDirectory()
-					err = processHooks(preUninstallHooksDir, func(hookFile string) error {
+					err = uniget.ProcessHooks(preUninstallHooksDir, func(hookFile string) error {
 						fmt.Printf("%s: %s\n", availableHookType, hookFile)
 						return nil
 					})
 
 				case "post-uninstall":
 					postUninstallHooksDir := configuration.GetHooksPostUninstallDirectory()
-					err = processHooks(postUninstallHooksDir, func(hookFile string) error {
+					err = uniget.ProcessHooks(postUninstallHooksDir, func(hookFile string) error {
 						fmt.Printf("%s: %s\n", availableHookType, hookFile)
 						return nil
 					})
@@ -339,9 +340,9 @@ var runHooksCmd = &cobra.Command{
 			return fmt.Errorf("invalid hook type: %s", hookType)
 		}
 
-		err = processHooks(hookDir, func(hookFile string) error {
+		err = uniget.ProcessHooks(hookDir, func(hookFile string) error {
 			fmt.Printf("Executing %s hook %s:\n", hookType, hookFile)
-			_, err := runHook(hookFile, args...)
+			err := uniget.RunHook(cmd.Context(), hookFile, args...)
 			return fmt.Errorf("unable to execute %s hook %s passing <%v>: %s", hookType, hookFile, args, err)
 		})
 		if err != nil {
@@ -375,153 +376,11 @@ var testHookCmd = &cobra.Command{
 			hookFile = configuration.GetHooksPostUninstallDirectory() + "/" + hookName
 		}
 
-		searchOutputFormat, err = runHook(hookFile, hookArgs...)
+		err = uniget.RunHook(cmd.Context(), hookFile, hookArgs...)
 		if err != nil {
 			return fmt.Errorf("unable to execute %s hook %s passing <%v>: %s", hookType, hookName, hookArgs, err)
 		}
-		fmt.Print(searchOutputFormat)
 
 		return nil
 	},
 }
-
-func runPreInstallHooks(args ...string) error {
-	if len(args) == 0 {
-		return nil
-	}
-
-	hookType = "pre-install"
-	err := processHooks(configuration.GetHooksPreInstallDirectory(), func(hookFile string) error {
-		fmt.Printf("Executing %s hook %s:\n", hookType, hookFile)
-		_, err := runHook(hookFile, args...)
-		if err != nil {
-			return fmt.Errorf("unable to execute %s hook (%s): %s", hookType, hookFile, err)
-		}
-		return nil
-	})
-	if err != nil {
-		return fmt.Errorf("unable to run %s hooks: %s", hookType, err)
-	}
-
-	return nil
-}
-
-func runPostInstallHooks(args ...string) error {
-	if len(args) == 0 {
-		return nil
-	}
-
-	hookType = "post-install"
-	err := processHooks(configuration.GetHooksPostInstallDirectory(), func(hookFile string) error {
-		fmt.Printf("Executing %s hook %s:\n", hookType, hookFile)
-		_, err := runHook(hookFile, args...)
-		if err != nil {
-			return fmt.Errorf("unable to execute %s hook (%s): %s", hookType, hookFile, err)
-		}
-		return nil
-	})
-	if err != nil {
-		return fmt.Errorf("unable to run %s hooks: %s", hookType, err)
-	}
-
-	return nil
-}
-
-func runPreUninstallHooks(args ...string) error {
-	if len(args) == 0 {
-		return nil
-	}
-
-	hookType = "pre-uninstall"
-	err := processHooks(configuration.GetHooksPreUninstallDirectory(), func(hookFile string) error {
-		fmt.Printf("Executing %s hook %s:\n", hookType, hookFile)
-		_, err := runHook(hookFile, args...)
-		if err != nil {
-			return fmt.Errorf("unable to execute %s hook (%s): %s", hookType, hookFile, err)
-		}
-		return nil
-	})
-	if err != nil {
-		return fmt.Errorf("unable to run %s hooks: %s", hookType, err)
-	}
-
-	return nil
-}
-
-func runPostUninstallHooks(args ...string) error {
-	if len(args) == 0 {
-		return nil
-	}
-
-	hookType = "post-uninstall"
-	err := processHooks(configuration.GetHooksPostUninstallDirectory(), func(hookFile string) error {
-		fmt.Printf("Executing %s hook %s:\n", hookType, hookFile)
-		_, err := runHook(hookFile, args...)
-		if err != nil {
-			return fmt.Errorf("unable to execute %s hook (%s): %s", hookType, hookFile, err)
-		}
-		return nil
-	})
-	if err != nil {
-		return fmt.Errorf("unable to run %s hooks: %s", hookType, err)
-	}
-
-	return nil
-}
-
-func processHooks(path string, callback func(file string) error) error {
-	if !myos.DirectoryExists(path) {
-		return nil
-	}
-
-	files, err := os.ReadDir(path)
-	if err != nil {
-		return fmt.Errorf("unable to read directory %s: %w", path, err)
-	}
-
-	for _, file := range files {
-		if file.IsDir() {
-			continue
-		}
-		if strings.HasPrefix(file.Name(), ".") {
-			continue
-		}
-
-		logging.Debugf("processing hook file %s/%s", path, file.Name())
-
-		hookFile := path + "/" + file.Name()
-		hookFileInfo, err := os.Lstat(hookFile)
-		if err != nil {
-			return fmt.Errorf("unable to stat hook file %s: %w", hookFile, err)
-		}
-		if hookFileInfo.Mode()&os.ModeSymlink != 0 {
-			return fmt.Errorf("hook file %s is a symlink, which is not allowed for security reasons", hookFile)
-		}
-
-		err = callback(hookFile)
-		if err != nil {
-			return fmt.Errorf("error processing hook file %s: %w", hookFile, err)
-		}
-	}
-
-	return nil
-}
-
-func runHook(hookFile string, args ...string) (string, error) {
-	if !myos.FileExists(hookFile) {
-		return "", fmt.Errorf("hook does not exist: %s", hookFile)
-	}
-
-	logging.Debugf("running hook in file %s (args: %s)", hookFile, args)
-	command := exec.Command(hookFile, args...) // #nosec G204 -- Tool images are a trusted source
-	command.Stdout = os.Stdout
-	command.Stderr = os.Stderr
-	err := command.Run()
-	if err != nil {
-		fmt.Printf("stdout:\n%s\n", command.Stdout)
-		fmt.Printf("stderr:\n%s\n", command.Stderr)
-		return "", fmt.Errorf("unable to execute hook (%s): %s", hookFile, err)
-	}
-
-	return string(searchOutputFormat), nil
-}
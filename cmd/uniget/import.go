@@ -10,6 +10,8 @@ import (
 )
 
 func initImportCmd() {
+	addFailurePolicyFlags(importCmd)
+
 	rootCmd.AddCommand(importCmd)
 }
 
@@ -64,9 +66,9 @@ var importCmd = &cobra.Command{
 		}
 
 		plannedTools := tools.GetByNames(toolsToImport)
-		err = installTools(cmd.OutOrStdout(), plannedTools, false, false, true, true, true)
+		err = installTools(cmd.Context(), textOutput(cmd), plannedTools, false, false, true, true, true)
 		if err != nil {
-			return fmt.Errorf("failed to import tools: %s", err)
+			return fmt.Errorf("failed to import tools: %w", err)
 		}
 
 		return nil
@@ -61,7 +61,7 @@ var inspectCmd = &cobra.Command{
 
 		logging.Info.Printfln("Inspecting %s %s\n", inspectTool.Name, inspectTool.Version)
 		registries, repositories := inspectTool.GetSourcesWithFallback(constants.Registry, constants.ImageRepository)
-		toolRef, err := containers.FindToolRef(registries, repositories, inspectTool.Name, inspectToolVersion)
+		toolRef, err := containers.FindToolRef(cmd.Context(), registries, repositories, inspectTool.Name, inspectToolVersion)
 		if err != nil {
 			return fmt.Errorf("error finding tool %s:%s: %s", inspectTool.Name, inspectTool.Version, err)
 		}
@@ -71,12 +71,12 @@ var inspectCmd = &cobra.Command{
 		}
 
 		progressReader := common.CreateProgressReader("Downloading", configuration.Debug || configuration.Trace)
-		err = toolCache.Get(toolRef, progressReader, func(reader io.ReadCloser) error { return nil })
+		err = toolCache.Get(cmd.Context(), toolRef, progressReader, func(reader io.ReadCloser) error { return nil })
 		if err != nil {
 			return fmt.Errorf("unable to get image: %s", err)
 		}
 		var files []string
-		err = toolCache.Get(toolRef, progressReader, func(reader io.ReadCloser) error {
+		err = toolCache.Get(cmd.Context(), toolRef, progressReader, func(reader io.ReadCloser) error {
 			files, err = inspectTool.Inspect(cmd.OutOrStdout(), reader, effectivePathRewriteRules)
 			if err != nil {
 				return fmt.Errorf("unable to inspect %s: %s", inspectTool.Name, err)
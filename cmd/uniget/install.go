@@ -1,22 +1,20 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"html/template"
 	"io"
 	"os"
-	"path/filepath"
 	"strings"
-	"syscall"
 
 	"github.com/spf13/cobra"
 
-	"gitlab.com/uniget-org/cli/internal/common"
+	"gitlab.com/uniget-org/cli/internal/client"
 	"gitlab.com/uniget-org/cli/internal/constants"
-	"gitlab.com/uniget-org/cli/pkg/containers"
 	"gitlab.com/uniget-org/cli/pkg/logging"
 	"gitlab.com/uniget-org/cli/pkg/tool"
+	"gitlab.com/uniget-org/cli/pkg/uniget"
 )
 
 var installTagsMode bool
@@ -27,6 +25,7 @@ var installCheck bool
 var installDryRun bool
 var installReinstall bool
 var installPathToTarMappings map[string]string
+var installQuery string
 
 func initInstallCmd() {
 	installCmd.Flags().BoolVar(&installTagsMode, "tags", false, "Install tool(s) matching tag")
@@ -37,8 +36,13 @@ func initInstallCmd() {
 	installCmd.Flags().BoolVar(&installCheck, "check", false, "Abort after checking versions")
 	installCmd.Flags().BoolVarP(&installReinstall, "reinstall", "r", false, "Reinstall tool(s)")
 	installCmd.Flags().StringToStringVar(&installPathToTarMappings, "path-to-tar-mappings", nil, "Map paths in tar file to target paths (for debugging purposes)")
+	addQueryFlag(installCmd, &installQuery, "Install tool(s) matching the query")
 	installCmd.MarkFlagsMutuallyExclusive("tags", "file")
+	installCmd.MarkFlagsMutuallyExclusive("query", "tags")
+	installCmd.MarkFlagsMutuallyExclusive("query", "file")
 	installCmd.MarkFlagsMutuallyExclusive("check", "dry-run")
+	addFailurePolicyFlags(installCmd)
+	addOutputFlag(installCmd)
 	err := installCmd.Flags().MarkHidden("path-to-tar-mappings")
 	if err != nil {
 		logging.Error.Printfln("Unable to mark path-to-tar-mappings flag as hidden: %s", err)
@@ -63,7 +67,17 @@ var installCmd = &cobra.Command{
 		var requestedTools = &tool.Tools{}
 
 		// Collect requested tools based on mode
-		if installTagsMode {
+		if len(installQuery) > 0 {
+			if len(args) > 0 {
+				return fmt.Errorf("tools cannot be specified together with --query")
+			}
+			logging.Debugf("Adding tools matching query <%s> to requested tools", installQuery)
+			requestedTools, err = selectTools(tools, installQuery)
+			if err != nil {
+				return err
+			}
+
+		} else if installTagsMode {
 			logging.Debugf("Adding tools matching tags to requested tools")
 			requestedTools = tools.GetByTags(args)
 			if err != nil {
@@ -104,147 +118,42 @@ var installCmd = &cobra.Command{
 		}
 		logging.Debugf("Requested %d tool(s)", len(requestedTools.Tools))
 
-		return installTools(cmd.OutOrStdout(), requestedTools, installCheck, installDryRun, installReinstall, installSkipDeps, installSkipConflicts)
+		return installTools(cmd.Context(), textOutput(cmd), requestedTools, installCheck, installDryRun, installReinstall, installSkipDeps, installSkipConflicts)
 	},
 }
 
-func findInstalledTools(tools *tool.Tools) (*tool.Tools, error) {
-	var requestedTools = &tool.Tools{}
-	for index, tool := range tools.Tools {
-		logging.Debugf("Getting status for requested tool %s", tool.Name)
-
-		err := tools.Tools[index].UpdateStatus(
-			configuration.Prefix,
-			configuration.Target,
-			configuration.GetCacheDirectory(),
-			configuration.Arch,
-			configuration.AltArch,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to update status for tool %s: %s", tool.Name, err)
-		}
-
-		if tools.Tools[index].IsInstalled() {
-			logging.Debugf("Adding %s to requested tools", tool.Name)
-			requestedTools.Tools = append(requestedTools.Tools, tool)
-		}
-	}
-
-	return requestedTools, nil
+// newClient creates a client for the current configuration and catalog
+func newClient(w io.Writer) *uniget.Client {
+	return client.NewFromConfig(configuration, tools, uniget.Options{
+		Cache:    toolCache,
+		Emitter:  emitter,
+		Output:   w,
+		FailFast: failFast || !keepGoing,
+	})
 }
 
-func installTools(w io.Writer, requestedTools *tool.Tools, check bool, plan bool, reinstall bool, skipDependencies bool, skipConflicts bool) error {
-	var plannedTools tool.Tools
-
-	// Add dependencies of requested tools
-	// Set installation order
-	for _, tool := range requestedTools.Tools {
-		err := tools.ResolveDependencies(&plannedTools, tool.Name)
-		if err != nil {
-			return fmt.Errorf("unable to resolve dependencies for %s: %s", tool.Name, err)
-		}
-	}
-	for _, requestedTool := range requestedTools.Tools {
-		tool, err := plannedTools.GetByName(requestedTool.Name)
-		if err != nil {
-			return fmt.Errorf("unable to find %s in planned tools", requestedTool.Name)
-		}
-		tool.Status.IsRequested = true
-	}
-	logging.Debugf("Planned %d tool(s)", len(plannedTools.Tools))
-
-	renamedTools := make(map[string]string, 0)
-	removedTools := make(map[string]string, 0)
-	for _, plannedTool := range plannedTools.Tools {
-		if len(plannedTool.Lifecycle.RenamedTo) > 0 {
-			renamedTools[plannedTool.Name] = plannedTool.Lifecycle.RenamedTo
-
-		} else if len(plannedTool.Lifecycle.RemovedWithReason) > 0 {
-			removedTools[plannedTool.Name] = plannedTool.Lifecycle.RemovedWithReason
-		}
-	}
-	if len(renamedTools) > 0 {
-		for oldName, newName := range renamedTools {
-			logging.Warning.Printfln("%s was renamed. Please uninstall %s and install %s manually and try again.",
-				oldName, oldName, newName)
-		}
-	}
-	if len(removedTools) > 0 {
-		for oldName, reason := range removedTools {
-			logging.Warning.Printfln("%s was removed: %s. Please uninstall %s manually and try again.",
-				oldName, reason, oldName)
-		}
+func installTools(ctx context.Context, w io.Writer, requestedTools *tool.Tools, check bool, plan bool, reinstall bool, skipDependencies bool, skipConflicts bool) error {
+	client := newClient(w)
+	options := uniget.InstallOptions{
+		Reinstall:         reinstall,
+		SkipDependencies:  skipDependencies,
+		SkipConflicts:     skipConflicts,
+		PathToTarMappings: installPathToTarMappings,
 	}
-	if len(renamedTools) > 0 || len(removedTools) > 0 {
-		return fmt.Errorf("renamed or removed tools require your attention")
-	}
-
-	// Populate status of planned tools
-	for index, tool := range plannedTools.Tools {
-		if skipDependencies && !tool.Status.IsRequested {
-			continue
-		}
-
-		logging.Debugf("Getting status for requested tool %s", tool.Name)
-		err := plannedTools.Tools[index].UpdateStatus(
-			configuration.Prefix,
-			configuration.Target,
-			configuration.GetCacheDirectory(),
-			configuration.Arch,
-			configuration.AltArch,
-		)
-		if err != nil {
-			return fmt.Errorf("failed to update status for tool %s: %s", plannedTools.Tools[index].Name, err)
-		}
 
-		logging.Tracef("Tool %s: %+v", plannedTools.Tools[index].Name, plannedTools.Tools[index])
+	plannedTools, err := client.Plan(ctx, requestedTools.GetNames(), options)
+	if err != nil {
+		return err
 	}
 
-	// Check for conflicts
-	var conflictsDetected = false
-	var conflictsWithInstalled tool.Tools
-	var conflictsBetweenPlanned tool.Tools
-	for index, tool := range plannedTools.Tools {
-		if !tool.Status.BinaryPresent && len(tool.ConflictsWith) > 0 {
-			for _, conflict := range tool.ConflictsWith {
-				conflictTool, err := plannedTools.GetByName(conflict)
-				if err != nil {
-					continue
-				}
-				if plannedTools.Contains(conflict) {
-					if conflictTool.Status.BinaryPresent {
-						conflictsWithInstalled.Tools = append(conflictsWithInstalled.Tools, tool)
-					} else {
-						conflictsBetweenPlanned.Tools = append(conflictsBetweenPlanned.Tools, tool)
-					}
-					conflictsDetected = true
-
-					if skipConflicts {
-						plannedTools.Tools[index].Status.SkipDueToConflicts = true
-					}
-				}
-			}
-		}
-	}
+	conflictsDetected := plannedTools.HasConflicts()
 	if conflictsDetected {
-		plannedTools.ListWithStatus(w)
-	}
-	if len(conflictsWithInstalled.Tools) > 0 {
-		logging.Error.Printfln("Conflicts with installed tools:")
-		for _, conflict := range conflictsWithInstalled.Tools {
-			logging.Error.Printfln("  %s conflicts with %s", conflict.Name, strings.Join(conflict.ConflictsWith, ", "))
+		plannedTools.Tools.ListWithStatus(w)
+		printConflicts("Conflicts with installed tools:", plannedTools.ConflictsWithInstalled)
+		printConflicts("Conflicts between planned tools:", plannedTools.ConflictsBetweenPlanned)
+		if !skipConflicts {
+			return uniget.ErrConflicts
 		}
-		conflictsDetected = true
-	}
-	if len(conflictsBetweenPlanned.Tools) > 0 {
-		logging.Error.Printfln("Conflicts between planned tools:")
-		for _, conflict := range conflictsBetweenPlanned.Tools {
-			logging.Error.Printfln("  %s conflicts with %s", conflict.Name, strings.Join(conflict.ConflictsWith, ", "))
-		}
-		conflictsDetected = true
-	}
-	if conflictsDetected && !skipConflicts {
-		return fmt.Errorf("conflicts detected")
 	}
 
 	// Terminate if checking or planning
@@ -253,11 +162,11 @@ func installTools(w io.Writer, requestedTools *tool.Tools, check bool, plan bool
 		//       - Show version status (installed, outdated, missing)
 		//       - Use color and emoji
 		if !conflictsDetected {
-			plannedTools.ListWithStatus(w)
+			plannedTools.Tools.ListWithStatus(w)
 		}
 	}
 	if check {
-		for _, tool := range plannedTools.Tools {
+		for _, tool := range plannedTools.Tools.Tools {
 			if !tool.Status.BinaryPresent || !tool.Status.VersionMatches {
 				return fmt.Errorf("found missing or outdated tool")
 			}
@@ -267,317 +176,23 @@ func installTools(w io.Writer, requestedTools *tool.Tools, check bool, plan bool
 		return nil
 	}
 
-	// Flag path-to-tar can only be used when installing a single tool
-	if len(installPathToTarMappings) > 0 {
-		logging.Debugf("Using path-to-tar-mappings for installation: %+v", installPathToTarMappings)
-	}
-
-	// Install
-	configuration.AssertWritableTarget()
-	configuration.AssertLibDirectory()
-	err := runPreInstallHooks(plannedTools.GetNames()...)
-	if err != nil {
-		return fmt.Errorf("unable to run pre-install hooks: %s", err)
-	}
-	var postHookTools tool.Tools
-	for _, plannedTool := range plannedTools.Tools {
-
-		if plannedTool.Status.VersionMatches && !reinstall {
-			//logging.Skip.Printfln("Skipping %s %s because it is already installed.", plannedTool.Name, plannedTool.Version)
-			continue
-		}
-		if plannedTool.Status.SkipDueToConflicts {
-			logging.Skip.Printfln("Skipping %s because it conflicts with another tool.", plannedTool.Name)
-			continue
-		}
-		if skipDependencies && !plannedTool.Status.IsRequested {
-			logging.Skip.Printfln("Skipping %s because it is a dependency (--skip-deps was specified)", plannedTool.Name)
-			continue
-		}
-
-		if plannedTool.IsInstalled() {
-			binaryFilePath := plannedTool.Binary
-			file, err := os.OpenFile(binaryFilePath, os.O_WRONLY, 0600) // #nosec G304 -- Retrieved from signed metadata
-			if err != nil {
-				logging.Warning.Printfln("%s: Binary is in use", plannedTool.Name)
-				continue
-			}
-			//nolint:errcheck
-			file.Close() // #nosec G104 -- File is closed immediately after opening
-
-			err = uninstallTool(plannedTool.Name)
-			if err != nil {
-				logging.Warning.Printfln("Unable to uninstall %s: %s", plannedTool.Name, err)
-				continue
-			}
-			err = printToolUpdateMessage(w, plannedTool.Name)
-			if err != nil {
-				logging.Warning.Printfln("Unable to print tool update: %s", err)
-				continue
-			}
-		}
-
-		if !skipDependencies {
-			for _, depName := range plannedTool.RuntimeDependencies {
-				dep, err := plannedTools.GetByName(depName)
-				if err != nil {
-					logging.Error.Printfln("Unable to find dependency %s", depName)
-					return fmt.Errorf("unable to find dependency %s", depName)
-				}
-
-				err = dep.GetBinaryStatus()
-				if err != nil {
-					logging.Error.Printfln("Unable to get binary status of dependency %s: %s", depName, err)
-					return fmt.Errorf("unable to get binary status of dependency %s: %s", depName, err)
-				}
-				err = dep.GetMarkerFileStatus(configuration.GetCacheDirectory())
-				if err != nil {
-					logging.Error.Printfln("Unable to get marker file status of dependency %s: %s", depName, err)
-					return fmt.Errorf("unable to get marker file status of dependency %s: %s", depName, err)
-				}
-				err = dep.GetVersionStatus()
-				if err != nil {
-					logging.Error.Printfln("Unable to get version status of dependency %s: %s", depName, err)
-					return fmt.Errorf("unable to get version status of dependency %s: %s", depName, err)
-				}
-
-				if dep.Status.BinaryPresent || dep.Status.MarkerFilePresent {
-					continue
-				}
-				logging.Error.Printfln("Dependency %s is missing", depName)
-				return fmt.Errorf("dependency %s is missing", depName)
-			}
-		}
-
-		// Change working directory to prefix
-		// so that unpacking can ignore the target directory
-		installDir := configuration.Prefix
-		if len(installDir) == 0 {
-			installDir = "/"
-		}
-		err := os.Chdir(installDir)
-		if err != nil {
-			return fmt.Errorf("error changing directory to %s: %s", installDir, err)
-		}
-		dir, err := os.Getwd()
-		if err != nil {
-			return fmt.Errorf("error getting working directory")
-		}
-		logging.Debugf("Current directory: %s", dir)
-
-		progressReader := common.CreateProgressReader(fmt.Sprintf("%s %s", plannedTool.Name, plannedTool.Version), configuration.Debug || configuration.Trace)
-		if progressReader.IsQuiet() {
-			logging.Info.Printfln("Installing %s %s", plannedTool.Name, plannedTool.Version)
-		}
-
-		var pathToTar string
-		var layer io.ReadCloser
-		var installedFiles []string
-		installTool := func(plannedTool tool.Tool, layer io.ReadCloser) error {
-			installedFiles, err = plannedTool.Install(w, layer, configuration.PathRewriteRules, createPatchFileCallback(plannedTool))
-			if err != nil {
-				logging.Error.Printfln("Unable to install %s: %s", plannedTool.Name, err)
-				logging.Warning.Printfln("Removing partial installation")
-				err = uninstallFiles(installedFiles)
-				if err != nil {
-					logging.Warning.Printfln("Unable to remove partial installation: %s", err)
-				}
-				return fmt.Errorf("unable to install %s: %s", plannedTool.Name, err)
-			}
-
-			return nil
-		}
-		pathToTar, ok := installPathToTarMappings[plannedTool.Name]
-		installSuccessful := true
-		if ok {
-			logging.Debugf("Using tar file mappings for installation")
-			var fileInfo os.FileInfo
-			if fileInfo, err = os.Stat(pathToTar); os.IsNotExist(err) {
-				return fmt.Errorf("tar file %s does not exist", pathToTar)
-			}
-			layer, err = os.Open(pathToTar) // #nosec G304 -- Location supplied by user
-			if err != nil {
-				return fmt.Errorf("unable to read tar file %s: %s", pathToTar, err)
-			}
-			//nolint:errcheck
-			defer layer.Close()
-
-			progressReader.SetTotal(fileInfo.Size())
-			progressReader.SetReader(layer)
-			err = installTool(plannedTool, progressReader)
-			if err != nil {
-				installSuccessful = false
-			}
-
-		} else {
-			logging.Debugf("Using default behaviour for installation")
-			registries, repositories := plannedTool.GetSourcesWithFallback(constants.Registry, constants.ImageRepository)
-			ref, err := containers.FindToolRef(registries, repositories, plannedTool.Name, "latest")
-			if err != nil {
-				return fmt.Errorf("error finding tool %s:%s: %s", plannedTool.Name, plannedTool.Version, err)
-			}
-
-			logging.Debugf("Getting image %s", ref)
-			err = toolCache.Get(ref, progressReader, func(reader io.ReadCloser) error { return nil })
-			if err != nil {
-				return fmt.Errorf("unable to get image: %s", err)
-			}
-			err = toolCache.Get(ref, progressReader, func(reader io.ReadCloser) error {
-				err := installTool(plannedTool, reader)
-				if err != nil {
-					installSuccessful = false
-					return fmt.Errorf("unable to install %s: %s", plannedTool.Name, err)
-				}
-				return nil
-			})
-			if err != nil {
-				return fmt.Errorf("unable to install from image: %s", err)
-			}
-		}
-		if !installSuccessful {
-			continue
-		}
-
-		logging.Debugf("Installed files: %d", len(installedFiles))
-		logging.Tracef("Installed files: %v", installedFiles)
-		err = writeInstalledFiles(&plannedTool, installedFiles)
-		if err != nil {
-			logging.Error.Printfln("Unable to write installed files: %s", err)
-		}
-		plannedToolJson, err := json.MarshalIndent(plannedTool, "", "  ")
-		if err != nil {
-			logging.Error.Printfln("Unable to marshal tool: %s", err)
-		}
-		manifestFilename := configuration.GetLibDirectory() + "/manifests/" + plannedTool.Name + ".json"
-		err = os.WriteFile(manifestFilename, []byte(plannedToolJson), 0644) // #nosec G306 -- File must be world-readable
-		if err != nil {
-			logging.Error.Printfln("Unable to write manifest file: %s", err)
-		}
-		logging.Success.Printfln("%s %s", plannedTool.Name, plannedTool.Version)
-
-		err = printToolUsageMessage(w, plannedTool.Name)
-		if err != nil {
-			logging.Warning.Printfln("Unable to print tool usage: %s", err)
-			continue
-		}
-
-		err = plannedTool.CreateMarkerFile(configuration.GetCacheDirectory())
-		if err != nil {
-			logging.Warning.Printfln("Unable to create marker file: %s", err)
-			continue
-		}
-
-		postHookTools.Tools = append(postHookTools.Tools, plannedTool)
-	}
-
-	err = installProfileDShim()
-	if err != nil {
-		return fmt.Errorf("unable to install profile.d shim: %s", err)
-	}
-
-	if len(postHookTools.Tools) > 0 {
-		err = runPostInstallHooks(postHookTools.GetNames()...)
-		if err != nil {
-			return fmt.Errorf("unable to run post-install hooks: %s", err)
-		}
+	result, err := client.Apply(ctx, plannedTools, options)
+	var failedErr *uniget.FailedError
+	if err != nil && !errors.As(err, &failedErr) && !errors.Is(err, context.Canceled) {
+		return err
 	}
+	printSummary(w, result)
 
-	return nil
+	return withExitCode(err)
 }
 
-func createPatchFileCallback(tool tool.Tool) func(path string) string {
-	var patchFile = func(templatePath string) string {
-		if strings.HasSuffix(templatePath, ".go-template") {
-		} else {
-			logging.Debugf("Skipping file %s. Will not patch.", templatePath)
-			return templatePath
-		}
-
-		curDir, err := os.Getwd()
-		if err != nil {
-			logging.Error.Printfln("Unable to get current directory: %s", err)
-			return templatePath
-		}
-		fullTemplatePath := filepath.Join(curDir, templatePath)
-
-		values := make(map[string]any)
-		values["Target"] = configuration.Target
-		values["RelativeTarget"] = configuration.Target
-		values["Prefix"] = configuration.Prefix
-		values["Name"] = tool.Name
-		values["Version"] = tool.Version
-
-		if configuration.User {
-			values["Target"] = configuration.Prefix + "/" + configuration.Target
-		} else {
-			values["Target"] = "/" + configuration.Target
-		}
-
-		if len(tool.RuntimeDependencies) > 0 {
-			for _, depName := range tool.RuntimeDependencies {
-				depTool, err := tools.GetByName(depName)
-				if err != nil {
-					logging.Warning.Printfln("Unable to find dependency %s: %s", depName, err)
-				}
-				camelCaseDepName := depTool.GetCamelCaseName()
-				values[fmt.Sprintf("%sVersion", camelCaseDepName)] = depTool.Version
-			}
-		}
-		logging.Debugf("Patching file %s with values: %+v", fullTemplatePath, values)
-
-		filePath := strings.TrimSuffix(fullTemplatePath, ".go-template")
-		logging.Debugf("Patching file %s <- %s", filePath, fullTemplatePath)
-		logging.Debugf("values = %v", values)
-
-		templathPathInfo, err := os.Stat(fullTemplatePath)
-		if err != nil {
-			logging.Error.Printfln("Unable to get file info: %s", err)
-			return templatePath
-		}
-
-		file, err := os.Create(filePath) // #nosec G304 -- File path was checked before callback
-		if err != nil {
-			logging.Error.Printfln("Unable to create file: %s", err)
-			return templatePath
-		}
-		defer func() {
-			err := file.Close()
-			if err != nil {
-				logging.Warning.Printfln("Unable to close file: %s", err)
-			}
-		}()
-		if stat, ok := templathPathInfo.Sys().(*syscall.Stat_t); ok {
-			err = file.Chown(int(stat.Uid), int(stat.Gid))
-			if err != nil {
-				logging.Error.Printfln("Unable to set file ownership: %s", err)
-				return templatePath
-			}
-		}
-		err = file.Chmod(templathPathInfo.Mode())
-		if err != nil {
-			logging.Error.Printfln("Unable to set file permissions: %s", err)
-			return templatePath
-		}
-
-		tmpl, err := template.ParseFiles(fullTemplatePath)
-		if err != nil {
-			logging.Error.Printfln("Unable to parse template file: %s", err)
-			return templatePath
-		}
-		err = tmpl.Execute(file, values)
-		if err != nil {
-			logging.Error.Printfln("Unable to execute template: %s", err)
-			return templatePath
-		}
-
-		err = os.Remove(fullTemplatePath)
-		if err != nil {
-			logging.Error.Printfln("Unable to remove template file: %s", err)
-			return templatePath
-		}
-
-		return filePath
+func printConflicts(title string, conflicts tool.Tools) {
+	if len(conflicts.Tools) == 0 {
+		return
 	}
 
-	return patchFile
+	logging.Error.Println(title)
+	for _, conflict := range conflicts.Tools {
+		logging.Error.Printfln("  %s conflicts with %s", conflict.Name, strings.Join(conflict.ConflictsWith, ", "))
+	}
 }
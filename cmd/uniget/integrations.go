@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"gitlab.com/uniget-org/cli/internal/config"
+	"gitlab.com/uniget-org/cli/internal/constants"
+	"gitlab.com/uniget-org/cli/pkg/logging"
+)
+
+var integrationsReapply bool
+
+func initIntegrationsCmd() {
+	enableIntegrationCmd.Flags().BoolVar(&integrationsReapply, "reapply", false, "Reinstall installed tools to apply the integration")
+	disableIntegrationCmd.Flags().BoolVar(&integrationsReapply, "reapply", false, "Reinstall installed tools to remove the integration")
+
+	integrationsCmd.AddCommand(listIntegrationsCmd)
+	integrationsCmd.AddCommand(enableIntegrationCmd)
+	integrationsCmd.AddCommand(disableIntegrationCmd)
+
+	rootCmd.AddCommand(integrationsCmd)
+}
+
+var integrationsCmd = &cobra.Command{
+	Use: "integrations",
+	Aliases: []string{
+		"integration",
+	},
+	Short:   "Manage integrations",
+	Long:    constants.Header + "\nManage integrations which place files of tools where the system looks for them",
+	GroupID: "config",
+}
+
+var listIntegrationsCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"l", "ls"},
+	Short:   "List integrations",
+	Long:    constants.Header + "\nList integrations and whether they are enabled in the current scope",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		t := table.NewWriter()
+		t.SetOutputMirror(cmd.OutOrStdout())
+
+		t.AppendHeader(table.Row{"Name", "Scopes", "Enabled", "Description"})
+		for _, integration := range config.Integrations {
+			enabled := "-"
+			if integration.SupportsScope(configuration.GetScope()) {
+				enabled = fmt.Sprintf("%t", configuration.IsIntegrationEnabled(integration.Name))
+			}
+			t.AppendRow(table.Row{
+				integration.Name,
+				strings.Join(integration.Scopes, ", "),
+				enabled,
+				integration.Description,
+			})
+		}
+
+		t.Render()
+		return nil
+	},
+}
+
+var enableIntegrationCmd = &cobra.Command{
+	Use:   "enable <integration>...",
+	Short: "Enable integrations",
+	Long:  constants.Header + "\nEnable integrations in the current scope",
+	Args:  cobra.MinimumNArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return getIntegrationNames(), cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return changeIntegrations(cmd, args, true)
+	},
+}
+
+var disableIntegrationCmd = &cobra.Command{
+	Use:   "disable <integration>...",
+	Short: "Disable integrations",
+	Long:  constants.Header + "\nDisable integrations in the current scope",
+	Args:  cobra.MinimumNArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return getIntegrationNames(), cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return changeIntegrations(cmd, args, false)
+	},
+}
+
+func getIntegrationNames() []string {
+	names := make([]string, 0, len(config.Integrations))
+	for _, integration := range config.Integrations {
+		names = append(names, integration.Name)
+	}
+	return names
+}
+
+func changeIntegrations(cmd *cobra.Command, names []string, enable bool) error {
+	state, err := configuration.LoadIntegrationState()
+	if err != nil {
+		return fmt.Errorf("unable to load integrations: %s", err)
+	}
+
+	for _, name := range names {
+		integration, err := config.GetIntegration(name)
+		if err != nil {
+			return err
+		}
+		if !integration.SupportsScope(configuration.GetScope()) {
+			return fmt.Errorf("integration %s is not supported in %s scope", name, configuration.GetScope())
+		}
+
+		if enable {
+			state.Enable(name)
+			logging.Success.Printfln("Enabled integration %s", name)
+		} else {
+			state.Disable(name)
+			logging.Success.Printfln("Disabled integration %s", name)
+		}
+	}
+
+	err = configuration.SaveIntegrationState(state)
+	if err != nil {
+		return fmt.Errorf("unable to save integrations: %s", err)
+	}
+	configuration.ApplyIntegrations()
+
+	if !integrationsReapply {
+		logging.Info.Println("Use --reapply to apply the change to installed tools")
+		return nil
+	}
+
+	installedTools, err := newClient(nil).Status(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("unable to find installed tools: %s", err)
+	}
+	if len(installedTools.Tools) == 0 {
+		return nil
+	}
+	return installTools(cmd.Context(), cmd.OutOrStdout(), installedTools, false, false, true, true, true)
+}
@@ -3,22 +3,24 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"gitlab.com/uniget-org/cli/internal/constants"
 	"gitlab.com/uniget-org/cli/pkg/logging"
-	"gitlab.com/uniget-org/cli/pkg/tool"
 	"go.yaml.in/yaml/v3"
 )
 
 var listInstalledOnly bool
 var listUpgradableOnly bool
 var listOutput string
+var listQuery string
 
 func initListCmd() {
 	listCmd.Flags().BoolVar(&listInstalledOnly, "installed", false, "List only installed tools")
 	listCmd.Flags().BoolVar(&listUpgradableOnly, "upgradable", false, "List only upgradable tools")
 	listCmd.Flags().StringVarP(&listOutput, "output", "o", "pretty", "Output options: pretty, json, yaml")
+	addQueryFlag(listCmd, &listQuery, "List only tools matching the query")
 
 	rootCmd.AddCommand(listCmd)
 }
@@ -34,52 +36,20 @@ var listCmd = &cobra.Command{
 	GroupID: "tool",
 	Args:    cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
-		var listTools tool.Tools
-
+		expressions := make([]string, 0)
 		if listInstalledOnly {
-			var installedTools tool.Tools
-			for index := range tools.Tools {
-
-				err := tools.Tools[index].UpdateStatus(
-					configuration.Prefix,
-					configuration.Target,
-					configuration.GetCacheDirectory(),
-					configuration.Arch,
-					configuration.AltArch,
-				)
-				if err != nil {
-					return fmt.Errorf("failed to update status for tool %s: %s", tools.Tools[index].Name, err)
-				}
-
-				if tools.Tools[index].IsInstalled() {
-					installedTools.Tools = append(installedTools.Tools, tools.Tools[index])
-				}
-			}
-			listTools = installedTools
-
-		} else if listUpgradableOnly {
-			var installedTools tool.Tools
-			for index := range tools.Tools {
-
-				err := tools.Tools[index].UpdateStatus(
-					configuration.Prefix,
-					configuration.Target,
-					configuration.GetCacheDirectory(),
-					configuration.Arch,
-					configuration.AltArch,
-				)
-				if err != nil {
-					return fmt.Errorf("failed to update status for tool %s: %s", tools.Tools[index].Name, err)
-				}
-
-				if tools.Tools[index].IsUpgradable() {
-					installedTools.Tools = append(installedTools.Tools, tools.Tools[index])
-				}
-			}
-			listTools = installedTools
+			expressions = append(expressions, "installed:true")
+		}
+		if listUpgradableOnly {
+			expressions = append(expressions, "upgradable:true")
+		}
+		if len(listQuery) > 0 {
+			expressions = append(expressions, "("+listQuery+")")
+		}
 
-		} else {
-			listTools = *tools
+		listTools, err := selectTools(tools, strings.Join(expressions, " AND "))
+		if err != nil {
+			return err
 		}
 
 		switch listOutput {
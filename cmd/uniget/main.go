@@ -1,16 +1,19 @@
 package main
 
 import (
+	"context"
 	_ "embed"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 	"regexp"
 	"strings"
+	"syscall"
 	"time"
 
 	"gitlab.com/uniget-org/cli/internal/config"
 
-	"github.com/njayp/ophis"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 
@@ -20,6 +23,7 @@ import (
 	"gitlab.com/uniget-org/cli/pkg/logging"
 	myos "gitlab.com/uniget-org/cli/pkg/os"
 	"gitlab.com/uniget-org/cli/pkg/tool"
+	"gitlab.com/uniget-org/cli/pkg/transport"
 )
 
 var (
@@ -72,6 +76,11 @@ var (
 
 			logging.Init()
 
+			err = initEmitter(cmd)
+			if err != nil {
+				return fmt.Errorf("unable to initialize output: %s", err)
+			}
+
 			if len(configuration.Prefix) > 0 {
 				re, err := regexp.Compile(`^\/`)
 				if err != nil {
@@ -91,6 +100,13 @@ var (
 				configuration.Target = strings.TrimLeft(configuration.Target, "/")
 			}
 
+			configuration.ApplyTimeouts()
+			transport.UserAgent = fmt.Sprintf("%s/%s", constants.ProjectName, version)
+			err = configuration.ApplyTransport()
+			if err != nil {
+				return fmt.Errorf("unable to configure network: %s", err)
+			}
+
 			if configuration.Debug {
 				logging.Debugf("configuration: %s", configuration)
 
@@ -100,13 +116,9 @@ var (
 				}
 			}
 
-			if !myos.FileExists(configuration.GetMetadataFile()) ||
-				configuration.AutoUpdate ||
-				(len(os.Getenv("UNIGET_IGNORE_METADATA_SIGNATURE")) == 0 &&
-					!myos.FileExists(configuration.GetMetadataFile()+".sigstore.json")) {
-
+			if configuration.MetadataNeedsDownload() {
 				logging.Debugf("Metadata does not exist. Downloading...")
-				err := configuration.DownloadMetadata()
+				err := configuration.DownloadMetadata(cmd.Context())
 				if err != nil {
 					return fmt.Errorf("error downloading metadata: %s", err)
 				}
@@ -196,27 +208,34 @@ func init() {
 		Title: "Helper commands",
 	})
 
+	initBrowseCmd()
 	initBumpCmd()
 	initCacheCmd()
-	initCronCmd()
 	initDebugCmd()
 	initDescribeCmd()
 	initEnvCmd()
 	initGenerateCmd()
 	initHealthcheckCmd()
+	initHistoryCmd()
 	initHooksCmd()
 	initImportCmd()
 	initInspectCmd()
+	initIntegrationsCmd()
 	initInstallCmd()
 	initListCmd()
 	initManpagesCmd()
+	initMcpCmd()
 	initMetadataCmd()
 	initMessageCmd()
 	initRegCmd()
 	initReleaseNotesCmd()
+	initScheduleCmd()
 	initSearchCmd()
+	initServeCmd()
 	initSelfUpgradeCmd()
+	initShellenvCmd()
 	initShimCmd()
+	initSystemdCmd()
 	initTagsCmd()
 	initUninstallCmd()
 	initUpdateCmd()
@@ -239,10 +258,20 @@ func main() {
 	pf.BoolVar(&configuration.AutoUpdate, "auto-update", configuration.AutoUpdate, "Automatically update metadata")
 	pf.BoolVar(&configuration.IntegrateProfileD, "integrate-profiled", configuration.IntegrateProfileD, "Integrate profile.d scripts")
 	pf.BoolVar(&configuration.IntegrateEtc, "integrate-etc", configuration.IntegrateEtc, "Integrate configuration files from /etc")
+	pf.BoolVar(&configuration.IntegrateCompletion, "integrate-completion", configuration.IntegrateCompletion, "Integrate shell completions and man pages (see integrations command)")
 	pf.BoolVar(&configuration.IntegrateAll, "integrate-all", configuration.IntegrateAll, "Integrate all available integrations")
 	pf.StringVar(&configuration.Cache, "cache", configuration.Cache, "Cache backend to use (none, file, docker, containerd)")
 	pf.StringVar(&configuration.FileCacheDirectoryName, "cache-directory", configuration.FileCacheDirectoryName, "Directory for the file cache")
 	pf.IntVar(&configuration.FileCacheRetention, "cache-retention", configuration.FileCacheRetention, "Retention in seconds for the file cache")
+	pf.BoolVar(&configuration.PreserveOwnership, "preserve-ownership", configuration.PreserveOwnership, "Preserve file ownership and setuid/setgid bits (requires root)")
+	pf.BoolVar(&configuration.PreserveXattrs, "preserve-xattrs", configuration.PreserveXattrs, "Preserve extended attributes")
+	pf.BoolVar(&configuration.PreserveCapabilities, "preserve-capabilities", configuration.PreserveCapabilities, "Preserve file capabilities (requires root)")
+	pf.BoolVar(&configuration.GenerateCompletions, "generate-completions", configuration.GenerateCompletions, "Generate shell completions by running the completion command of installed tools")
+	pf.BoolVar(&configuration.HistorySyslog, "history-syslog", configuration.HistorySyslog, "Forward history entries to syslog/journald")
+	pf.StringVar(&configuration.Channel, "channel", configuration.Channel, "Release channel for self-upgrade (latest, stable, a version or a version constraint)")
+	pf.IntVar(&configuration.InstallTimeout, "install-timeout", configuration.InstallTimeout, "Timeout in seconds for downloading and extracting a single tool (0 disables the timeout)")
+	pf.IntVar(&configuration.MetadataTimeout, "metadata-timeout", configuration.MetadataTimeout, "Timeout in seconds for downloading metadata")
+	pf.IntVar(&configuration.ManifestTimeout, "manifest-timeout", configuration.ManifestTimeout, "Timeout in seconds for fetching image manifests")
 
 	rootCmd.MarkFlagsMutuallyExclusive("prefix", "user")
 	rootCmd.MarkFlagsMutuallyExclusive("target", "user")
@@ -251,20 +280,37 @@ func main() {
 	_ = rootCmd.Flags().MarkHidden("trace")
 	_ = rootCmd.Flags().MarkHidden("integrate-profiled")
 	_ = rootCmd.Flags().MarkHidden("integrate-etc")
+	_ = rootCmd.Flags().MarkHidden("integrate-completion")
 	_ = rootCmd.Flags().MarkHidden("integrate-all")
 	_ = rootCmd.Flags().MarkHidden("cache")
 	_ = rootCmd.Flags().MarkHidden("cache-directory")
 	_ = rootCmd.Flags().MarkHidden("cache-retention")
+	_ = rootCmd.Flags().MarkHidden("metadata-timeout")
+	_ = rootCmd.Flags().MarkHidden("manifest-timeout")
 
 	rootCmd.SetHelpCommand(&cobra.Command{GroupID: "helper"})
 	rootCmd.SetCompletionCommandGroupID("config")
 
-	mcp := ophis.Command(nil)
-	mcp.GroupID = "helper"
-	rootCmd.AddCommand(mcp)
-
-	err = rootCmd.Execute()
+	// The first interrupt cancels the context so that running operations
+	// can clean up. Restoring the default behaviour afterwards allows a
+	// second interrupt to terminate immediately.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+		logging.Warning.Println("Interrupted. Cleaning up (interrupt again to exit immediately)")
+	}()
+
+	err = rootCmd.ExecuteContext(ctx)
 	if err != nil {
-		os.Exit(1)
+		if ctx.Err() != nil {
+			os.Exit(exitCodeInterrupted)
+		}
+		var exitErr *exitCodeError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
+		os.Exit(exitCodeFailure)
 	}
 }
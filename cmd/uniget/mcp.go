@@ -0,0 +1,572 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/njayp/ophis"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"gitlab.com/uniget-org/cli/internal/constants"
+	"gitlab.com/uniget-org/cli/pkg/events"
+	"gitlab.com/uniget-org/cli/pkg/logging"
+	"gitlab.com/uniget-org/cli/pkg/tool"
+	"gitlab.com/uniget-org/cli/pkg/uniget"
+)
+
+var mcpListen string
+var mcpTokenFile string
+var mcpAllowMutations bool
+
+// initMcpCmd reuses the editor integration of ophis (claude, vscode, cursor)
+// but replaces the generic server exposing all commands with a curated set
+// of tools
+func initMcpCmd() {
+	mcpCmd := ophis.Command(nil)
+	mcpCmd.GroupID = "helper"
+	for _, subCmd := range mcpCmd.Commands() {
+		switch subCmd.Name() {
+		case "start", "stream", "tools":
+			mcpCmd.RemoveCommand(subCmd)
+		}
+	}
+
+	mcpStreamCmd.Flags().StringVar(&mcpListen, "listen", "127.0.0.1:8080", "Address to listen on")
+	mcpStreamCmd.Flags().StringVar(&mcpTokenFile, "token-file", "", "File containing the bearer token required with --allow-mutations (or UNIGET_SERVE_TOKEN)")
+	for _, subCmd := range []*cobra.Command{mcpStartCmd, mcpStreamCmd, mcpToolsCmd} {
+		subCmd.Flags().BoolVar(&mcpAllowMutations, "allow-mutations", false, "Offer tools to install, upgrade and uninstall")
+		mcpCmd.AddCommand(subCmd)
+	}
+
+	rootCmd.AddCommand(mcpCmd)
+}
+
+const mcpHelp = `
+Read-only tools are always available: search, describe, list_installed,
+plan_install and release_notes. Tools to install, upgrade and uninstall are
+only offered with --allow-mutations (or UNIGET_MCPALLOWMUTATIONS=true).
+Every call is recorded in the audit log (` + constants.McpAuditFileName + `).`
+
+var mcpStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start the MCP server",
+	Long:  constants.Header + "\nStart the MCP server on stdio\n" + mcpHelp,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		reserveStdoutForMcp()
+		return newMcpServer(mcpAllowMutations || configuration.McpAllowMutations).Run(cmd.Context(), &mcp.StdioTransport{})
+	},
+}
+
+var mcpStreamCmd = &cobra.Command{
+	Use:   "stream",
+	Short: "Stream the MCP server over HTTP",
+	Long: constants.Header + "\nServe the MCP server over streamable HTTP\n" + mcpHelp + `
+
+When mutations are allowed, a token is required and requests must send it as
+"Authorization: Bearer <token>".`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		allowMutations := mcpAllowMutations || configuration.McpAllowMutations
+		token, err := readServeToken(mcpTokenFile)
+		if err != nil {
+			return err
+		}
+		if allowMutations && len(token) == 0 {
+			return fmt.Errorf("mutations over HTTP require a token (--token-file or UNIGET_SERVE_TOKEN)")
+		}
+
+		server := newMcpServer(allowMutations)
+		var handler http.Handler = mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server {
+			return server
+		}, nil)
+		if len(token) > 0 {
+			handler = requireBearerToken(token, handler)
+		}
+
+		httpServer := &http.Server{
+			Addr:              mcpListen,
+			Handler:           handler,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		logging.Info.Printfln("Serving MCP on http://%s", mcpListen)
+		err = listenAndServe(cmd.Context(), httpServer)
+		if err != nil {
+			return fmt.Errorf("failed to serve MCP: %s", err)
+		}
+		return nil
+	},
+}
+
+var mcpToolsCmd = &cobra.Command{
+	Use:   "tools",
+	Short: "Show tools offered by the MCP server",
+	Long:  constants.Header + "\nShow tools offered by the MCP server as JSON\n" + mcpHelp,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		definitions, err := listMcpTools(cmd.Context(), newMcpServer(mcpAllowMutations || configuration.McpAllowMutations))
+		if err != nil {
+			return err
+		}
+
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(definitions)
+		if err != nil {
+			return fmt.Errorf("failed to marshal tools: %s", err)
+		}
+		return nil
+	},
+}
+
+// requireBearerToken rejects requests without the token
+func requireBearerToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !hasBearerToken(r, token) {
+			writeError(w, http.StatusUnauthorized, fmt.Errorf("missing or invalid bearer token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// reserveStdoutForMcp moves all human readable output to stderr because
+// stdout carries the protocol
+func reserveStdoutForMcp() {
+	logging.OutputWriter = os.Stderr
+	logging.Init()
+	pterm.SetDefaultOutput(os.Stderr)
+}
+
+func listMcpTools(ctx context.Context, server *mcp.Server) ([]*mcp.Tool, error) {
+	client := mcp.NewClient(&mcp.Implementation{Name: constants.ProjectName}, nil)
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to server: %s", err)
+	}
+	//nolint:errcheck
+	defer serverSession.Close()
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to server: %s", err)
+	}
+	//nolint:errcheck
+	defer clientSession.Close()
+
+	result, err := clientSession.ListTools(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tools: %s", err)
+	}
+	return result.Tools, nil
+}
+
+type mcpSearchInput struct {
+	Terms string `json:"terms" jsonschema:"Search terms matched against name, tags, dependencies and description"`
+	Limit int    `json:"limit,omitempty" jsonschema:"Maximum number of results (default 20)"`
+}
+
+type mcpSearchResult struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+	Score       int      `json:"score"`
+}
+
+type mcpSearchOutput struct {
+	Results []mcpSearchResult `json:"results"`
+}
+
+type mcpDescribeInput struct {
+	Name string `json:"name" jsonschema:"Name of the tool"`
+}
+
+type mcpListInstalledInput struct {
+	OutdatedOnly bool `json:"outdated_only,omitempty" jsonschema:"Only list tools with a newer version"`
+}
+
+type mcpToolsOutput struct {
+	Tools []apiTool `json:"tools"`
+}
+
+type mcpPlanInput struct {
+	Tools            []string `json:"tools,omitempty" jsonschema:"Names of tools to install"`
+	Query            string   `json:"query,omitempty" jsonschema:"Query selecting tools, e.g. tag:kubernetes AND NOT tag:deprecated"`
+	Upgrade          bool     `json:"upgrade,omitempty" jsonschema:"Plan an upgrade of installed tools instead"`
+	Reinstall        bool     `json:"reinstall,omitempty" jsonschema:"Reinstall tools which are up to date"`
+	SkipDependencies bool     `json:"skip_dependencies,omitempty" jsonschema:"Do not install dependencies"`
+}
+
+type mcpPlanOutput struct {
+	Tools []apiPlannedTool `json:"tools"`
+}
+
+type mcpReleaseNotesInput struct {
+	Name    string `json:"name" jsonschema:"Name of the tool"`
+	Version string `json:"version,omitempty" jsonschema:"Version of the tool (default: version in the catalog)"`
+}
+
+type mcpReleaseNotesOutput struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Notes   string `json:"notes"`
+}
+
+type mcpInstallInput struct {
+	Tools     []string `json:"tools" jsonschema:"Names of tools to install"`
+	Reinstall bool     `json:"reinstall,omitempty" jsonschema:"Reinstall tools which are up to date"`
+}
+
+type mcpUpgradeInput struct {
+	Query string `json:"query,omitempty" jsonschema:"Query limiting the upgrade to matching tools"`
+}
+
+type mcpUninstallInput struct {
+	Tools []string `json:"tools" jsonschema:"Names of tools to uninstall"`
+}
+
+// mcpMutationOutput summarizes the events of an install, upgrade or
+// uninstall
+type mcpMutationOutput struct {
+	Succeeded []string `json:"succeeded"`
+	Skipped   []string `json:"skipped"`
+	Failed    []string `json:"failed"`
+	Error     string   `json:"error,omitempty"`
+}
+
+func newMcpServer(allowMutations bool) *mcp.Server {
+	server := mcp.NewServer(&mcp.Implementation{
+		Name:    constants.ProjectName,
+		Version: version,
+	}, nil)
+
+	readOnly := &mcp.ToolAnnotations{ReadOnlyHint: true}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "search",
+		Description: "Search the catalog of tools ordered by relevance",
+		Annotations: readOnly,
+	}, audited("search", mcpSearch))
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "describe",
+		Description: "Show details and installation status of a tool",
+		Annotations: readOnly,
+	}, audited("describe", mcpDescribe))
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_installed",
+		Description: "List installed tools, optionally only outdated ones",
+		Annotations: readOnly,
+	}, audited("list_installed", mcpListInstalled))
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "plan_install",
+		Description: "Show which tools including dependencies would be installed or upgraded without changing anything",
+		Annotations: readOnly,
+	}, audited("plan_install", mcpPlanInstall))
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "release_notes",
+		Description: "Fetch release notes of a tool as markdown",
+		Annotations: readOnly,
+	}, audited("release_notes", mcpReleaseNotes))
+
+	if !allowMutations {
+		return server
+	}
+
+	destructive := true
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "install",
+		Description: "Install tools including their dependencies",
+		Annotations: &mcp.ToolAnnotations{IdempotentHint: true},
+	}, audited("install", mcpInstall))
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "upgrade",
+		Description: "Upgrade installed tools to the versions in the catalog",
+		Annotations: &mcp.ToolAnnotations{IdempotentHint: true},
+	}, audited("upgrade", mcpUpgrade))
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "uninstall",
+		Description: "Uninstall tools",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: &destructive, IdempotentHint: true},
+	}, audited("uninstall", mcpUninstall))
+
+	return server
+}
+
+type mcpAuditEntry struct {
+	Time       time.Time       `json:"time"`
+	Tool       string          `json:"tool"`
+	Arguments  json.RawMessage `json:"arguments,omitempty"`
+	Result     string          `json:"result"`
+	Error      string          `json:"error,omitempty"`
+	DurationMs int64           `json:"duration_ms"`
+}
+
+func appendMcpAudit(path string, entry mcpAuditEntry) error {
+	err := os.MkdirAll(filepath.Dir(path), 0755) // #nosec G301 -- Directory must be accessible by all users
+	if err != nil {
+		return fmt.Errorf("unable to create directory for %s: %s", path, err)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("unable to marshal audit entry: %s", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600) // #nosec G304 -- Location is derived from configuration
+	if err != nil {
+		return fmt.Errorf("unable to open %s: %s", path, err)
+	}
+	//nolint:errcheck
+	defer file.Close()
+
+	_, err = file.Write(append(data, '\n'))
+	if err != nil {
+		return fmt.Errorf("unable to write to %s: %s", path, err)
+	}
+
+	return nil
+}
+
+// audited records every call of a tool in the audit log
+func audited[In, Out any](name string, handler mcp.ToolHandlerFor[In, Out]) mcp.ToolHandlerFor[In, Out] {
+	return func(ctx context.Context, request *mcp.CallToolRequest, input In) (*mcp.CallToolResult, Out, error) {
+		started := time.Now()
+		result, output, err := handler(ctx, request, input)
+
+		entry := mcpAuditEntry{
+			Time:       started.UTC(),
+			Tool:       name,
+			Result:     "succeeded",
+			DurationMs: time.Since(started).Milliseconds(),
+		}
+		arguments, marshalErr := json.Marshal(input)
+		if marshalErr == nil {
+			entry.Arguments = arguments
+		}
+		if err != nil {
+			entry.Result = "failed"
+			entry.Error = err.Error()
+		} else if result != nil && result.IsError {
+			entry.Result = "failed"
+		}
+		auditErr := appendMcpAudit(configuration.GetMcpAuditFile(), entry)
+		if auditErr != nil {
+			logging.Warning.Printfln("Unable to record MCP audit log: %s", auditErr)
+		}
+		logging.Debugf("MCP tool %s %s after %dms", name, entry.Result, entry.DurationMs)
+
+		return result, output, err
+	}
+}
+
+func mcpSearch(ctx context.Context, request *mcp.CallToolRequest, input mcpSearchInput) (*mcp.CallToolResult, mcpSearchOutput, error) {
+	output := mcpSearchOutput{Results: []mcpSearchResult{}}
+	limit := input.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	for _, result := range tools.Search(strings.Fields(input.Terms), tool.AllSearchFields) {
+		if len(output.Results) >= limit {
+			break
+		}
+		output.Results = append(output.Results, mcpSearchResult{
+			Name:        result.Tool.Name,
+			Version:     result.Tool.Version,
+			Description: result.Tool.Description,
+			Tags:        result.Tool.Tags,
+			Score:       result.Score,
+		})
+	}
+	return nil, output, nil
+}
+
+func mcpDescribe(ctx context.Context, request *mcp.CallToolRequest, input mcpDescribeInput) (*mcp.CallToolResult, apiTool, error) {
+	t, err := tools.GetByName(input.Name)
+	if err != nil {
+		return nil, apiTool{}, err
+	}
+	current := *t
+	err = current.UpdateStatus(
+		configuration.Prefix,
+		configuration.Target,
+		configuration.GetCacheDirectory(),
+		configuration.Arch,
+		configuration.AltArch,
+	)
+	if err != nil {
+		return nil, apiTool{}, fmt.Errorf("failed to update status for tool %s: %s", current.Name, err)
+	}
+	return nil, newAPITool(current), nil
+}
+
+func mcpListInstalled(ctx context.Context, request *mcp.CallToolRequest, input mcpListInstalledInput) (*mcp.CallToolResult, mcpToolsOutput, error) {
+	current, err := snapshot()
+	if err != nil {
+		return nil, mcpToolsOutput{}, err
+	}
+
+	output := mcpToolsOutput{Tools: []apiTool{}}
+	for _, t := range current.Tools {
+		if !t.IsInstalled() || (input.OutdatedOnly && !t.IsUpgradable()) {
+			continue
+		}
+		output.Tools = append(output.Tools, newAPITool(t))
+	}
+	return nil, output, nil
+}
+
+func mcpPlanInstall(ctx context.Context, request *mcp.CallToolRequest, input mcpPlanInput) (*mcp.CallToolResult, mcpPlanOutput, error) {
+	apiRequest := apiRequest{
+		Tools:            input.Tools,
+		Query:            input.Query,
+		Reinstall:        input.Reinstall,
+		SkipDependencies: input.SkipDependencies,
+	}
+	selected, err := requestedTools(apiRequest, input.Upgrade)
+	if err != nil {
+		return nil, mcpPlanOutput{}, err
+	}
+	plan, err := newClient(nil).Plan(ctx, selected.GetNames(), uniget.InstallOptions{SkipDependencies: input.SkipDependencies})
+	if err != nil {
+		return nil, mcpPlanOutput{}, err
+	}
+	return nil, mcpPlanOutput{Tools: newAPIPlan(plan.Tools, apiRequest)}, nil
+}
+
+func mcpReleaseNotes(ctx context.Context, request *mcp.CallToolRequest, input mcpReleaseNotesInput) (*mcp.CallToolResult, mcpReleaseNotesOutput, error) {
+	t, err := tools.GetByName(input.Name)
+	if err != nil {
+		return nil, mcpReleaseNotesOutput{}, err
+	}
+	releaseVersion := input.Version
+	if len(releaseVersion) == 0 {
+		releaseVersion = t.Version
+	}
+	notes, err := fetchReleaseNotes(ctx, t, releaseVersion)
+	if err != nil {
+		return nil, mcpReleaseNotesOutput{}, err
+	}
+	return nil, mcpReleaseNotesOutput{Name: t.Name, Version: releaseVersion, Notes: notes}, nil
+}
+
+func mcpInstall(ctx context.Context, request *mcp.CallToolRequest, input mcpInstallInput) (*mcp.CallToolResult, mcpMutationOutput, error) {
+	if len(input.Tools) == 0 {
+		return nil, mcpMutationOutput{}, fmt.Errorf("no tools requested")
+	}
+	err := checkMcpToolNames(input.Tools)
+	if err != nil {
+		return nil, mcpMutationOutput{}, err
+	}
+	args := []string{"install"}
+	if input.Reinstall {
+		args = append(args, "--reinstall")
+	}
+	return runMcpMutation(ctx, args, input.Tools)
+}
+
+func mcpUpgrade(ctx context.Context, request *mcp.CallToolRequest, input mcpUpgradeInput) (*mcp.CallToolResult, mcpMutationOutput, error) {
+	args := []string{"upgrade"}
+	if len(input.Query) > 0 {
+		args = append(args, "--query", input.Query)
+	}
+	return runMcpMutation(ctx, args, nil)
+}
+
+func mcpUninstall(ctx context.Context, request *mcp.CallToolRequest, input mcpUninstallInput) (*mcp.CallToolResult, mcpMutationOutput, error) {
+	if len(input.Tools) == 0 {
+		return nil, mcpMutationOutput{}, fmt.Errorf("no tools requested")
+	}
+	err := checkMcpToolNames(input.Tools)
+	if err != nil {
+		return nil, mcpMutationOutput{}, err
+	}
+	return runMcpMutation(ctx, []string{"uninstall"}, input.Tools)
+}
+
+// checkMcpToolNames only accepts names from the catalog so that clients
+// cannot pass flags to the command
+func checkMcpToolNames(names []string) error {
+	for _, name := range names {
+		if strings.HasPrefix(name, "-") {
+			return fmt.Errorf("invalid tool name %s", name)
+		}
+		if !tools.Contains(name) {
+			return fmt.Errorf("tool %s does not exist", name)
+		}
+	}
+	return nil
+}
+
+// runMcpMutation runs a command in a separate process so that its output
+// does not interfere with the protocol. The result is collected from the
+// JSON events of the command. Names are passed after "--" so that they are
+// never parsed as flags.
+func runMcpMutation(ctx context.Context, args []string, names []string) (*mcp.CallToolResult, mcpMutationOutput, error) {
+	output := mcpMutationOutput{
+		Succeeded: []string{},
+		Skipped:   []string{},
+		Failed:    []string{},
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, output, fmt.Errorf("unable to locate executable: %s", err)
+	}
+	globalArgs := []string{"--prefix", configuration.Prefix, "--target", configuration.Target}
+	if configuration.User {
+		globalArgs = []string{"--user"}
+	}
+	args = append(globalArgs, args...)
+	args = append(args, "--output", events.FormatJSON)
+	if len(names) > 0 {
+		args = append(append(args, "--"), names...)
+	}
+
+	logging.Debugf("Running %s %s", executable, strings.Join(args, " "))
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, executable, args...) // #nosec G204 -- Arguments are passed without a shell
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	runErr := cmd.Run()
+
+	scanner := bufio.NewScanner(&stdout)
+	for scanner.Scan() {
+		var event events.Event
+		err := json.Unmarshal(scanner.Bytes(), &event)
+		if err != nil {
+			continue
+		}
+		switch event.Type {
+		case events.TypeSucceeded:
+			output.Succeeded = append(output.Succeeded, event.Tool)
+		case events.TypeSkipped:
+			output.Skipped = append(output.Skipped, event.Tool)
+		case events.TypeFailed:
+			if len(event.Tool) > 0 && !slices.Contains(output.Failed, event.Tool) {
+				output.Failed = append(output.Failed, event.Tool)
+			}
+		}
+	}
+
+	if runErr != nil {
+		output.Error = strings.TrimSpace(stderr.String())
+		if len(output.Error) == 0 {
+			output.Error = runErr.Error()
+		}
+		return &mcp.CallToolResult{IsError: true}, output, nil
+	}
+	return nil, output, nil
+}
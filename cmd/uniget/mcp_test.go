@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func connectTestMcpServer(t *testing.T, allowMutations bool) *mcp.ClientSession {
+	t.Helper()
+
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := newMcpServer(allowMutations).Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("unable to connect server: %s", err)
+	}
+	t.Cleanup(func() { _ = serverSession.Close() })
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test"}, nil)
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("unable to connect client: %s", err)
+	}
+	t.Cleanup(func() { _ = clientSession.Close() })
+
+	return clientSession
+}
+
+func TestMcpTools(t *testing.T) {
+	useTestCatalog(t)
+
+	for _, allowMutations := range []bool{false, true} {
+		definitions, err := listMcpTools(context.Background(), newMcpServer(allowMutations))
+		if err != nil {
+			t.Fatalf("unable to list tools: %s", err)
+		}
+
+		names := make([]string, 0, len(definitions))
+		for _, definition := range definitions {
+			names = append(names, definition.Name)
+			if definition.OutputSchema == nil {
+				t.Errorf("tool %s has no output schema", definition.Name)
+			}
+		}
+		if slices.Contains(names, "install") != allowMutations || slices.Contains(names, "uninstall") != allowMutations {
+			t.Errorf("unexpected tools with allowMutations=%t: %v", allowMutations, names)
+		}
+		if !slices.Contains(names, "search") || !slices.Contains(names, "plan_install") {
+			t.Errorf("missing read-only tools: %v", names)
+		}
+	}
+}
+
+func TestMcpCallAndAudit(t *testing.T) {
+	useTestCatalog(t)
+	session := connectTestMcpServer(t, false)
+	ctx := context.Background()
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "plan_install",
+		Arguments: map[string]any{"tools": []string{"helm"}},
+	})
+	if err != nil {
+		t.Fatalf("unable to call tool: %s", err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected error result: %+v", result.Content)
+	}
+	data, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		t.Fatalf("unable to marshal structured content: %s", err)
+	}
+	var plan mcpPlanOutput
+	err = json.Unmarshal(data, &plan)
+	if err != nil {
+		t.Fatalf("unable to unmarshal plan: %s", err)
+	}
+	if len(plan.Tools) != 2 || plan.Tools[0].Name != "kubectl" || plan.Tools[1].Action != "install" {
+		t.Errorf("unexpected plan: %+v", plan)
+	}
+
+	result, err = session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "describe",
+		Arguments: map[string]any{"name": "missing"},
+	})
+	if err != nil {
+		t.Fatalf("unable to call tool: %s", err)
+	}
+	if !result.IsError {
+		t.Errorf("expected error result for missing tool")
+	}
+
+	_, err = session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "uninstall",
+		Arguments: map[string]any{"tools": []string{"jq"}},
+	})
+	if err == nil {
+		t.Errorf("expected uninstall to be unavailable")
+	}
+
+	file, err := os.Open(configuration.GetMcpAuditFile())
+	if err != nil {
+		t.Fatalf("unable to open audit log: %s", err)
+	}
+	//nolint:errcheck
+	defer file.Close()
+	var entries []mcpAuditEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry mcpAuditEntry
+		err := json.Unmarshal(scanner.Bytes(), &entry)
+		if err != nil {
+			t.Fatalf("unable to unmarshal audit entry: %s", err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 audit entries but got %d", len(entries))
+	}
+	if entries[0].Tool != "plan_install" || entries[0].Result != "succeeded" || string(entries[0].Arguments) == "" {
+		t.Errorf("unexpected first entry: %+v", entries[0])
+	}
+	if entries[1].Tool != "describe" || entries[1].Result != "failed" || entries[1].Error == "" {
+		t.Errorf("unexpected second entry: %+v", entries[1])
+	}
+}
+
+func TestMcpMutationToolNames(t *testing.T) {
+	useTestCatalog(t)
+	session := connectTestMcpServer(t, true)
+
+	for _, name := range []string{"--prefix=/tmp", "-f", "missing"} {
+		for _, tool := range []string{"install", "uninstall"} {
+			result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+				Name:      tool,
+				Arguments: map[string]any{"tools": []string{name}},
+			})
+			if err != nil {
+				t.Fatalf("unable to call tool: %s", err)
+			}
+			if !result.IsError {
+				t.Errorf("expected %s to reject %s", tool, name)
+			}
+		}
+	}
+}
+
+func TestRequireBearerToken(t *testing.T) {
+	handler := requireBearerToken("secret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		authorization string
+		status        int
+	}{
+		{authorization: "", status: http.StatusUnauthorized},
+		{authorization: "Bearer wrong", status: http.StatusUnauthorized},
+		{authorization: "Bearer secret", status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		if len(tt.authorization) > 0 {
+			req.Header.Set("Authorization", tt.authorization)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		if recorder.Code != tt.status {
+			t.Errorf("expected status %d for %q but got %d", tt.status, tt.authorization, recorder.Code)
+		}
+	}
+}
@@ -2,14 +2,10 @@ package main
 
 import (
 	"fmt"
-	"html/template"
-	"io"
 
-	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 	"gitlab.com/uniget-org/cli/internal/constants"
 	"gitlab.com/uniget-org/cli/pkg/logging"
-	"gitlab.com/uniget-org/cli/pkg/tool"
 )
 
 var messageFind bool
@@ -68,15 +64,16 @@ var messageCmd = &cobra.Command{
 			}
 
 		} else {
-			err := printToolInternalsMessage(cmd.OutOrStdout(), toolName)
+			client := newClient(cmd.OutOrStdout())
+			err := client.PrintInternalsMessage(cmd.OutOrStdout(), toolName)
 			if err != nil {
 				return fmt.Errorf("failed to print tool internals: %s", err)
 			}
-			err = printToolUsageMessage(cmd.OutOrStdout(), toolName)
+			err = client.PrintUsageMessage(cmd.OutOrStdout(), toolName)
 			if err != nil {
 				return fmt.Errorf("failed to print tool usage: %s", err)
 			}
-			err = printToolUpdateMessage(cmd.OutOrStdout(), toolName)
+			err = client.PrintUpdateMessage(cmd.OutOrStdout(), toolName)
 			if err != nil {
 				return fmt.Errorf("failed to print tool update: %s", err)
 			}
@@ -87,123 +84,3 @@ var messageCmd = &cobra.Command{
 		return nil
 	},
 }
-
-func createTemplateVariablesForTool(tool *tool.Tool) (map[string]any, error) {
-	values := make(map[string]any)
-	values["Target"] = fmt.Sprintf("%s/%s", configuration.Prefix, configuration.Target)
-	values["Name"] = tool.Name
-	values["Version"] = tool.Version
-
-	return values, nil
-}
-
-func createTemplateVariablesForToolByName(toolName string) (map[string]any, error) {
-	tool, err := tools.GetByName(toolName)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get tool: %s", err)
-	}
-
-	return createTemplateVariablesForTool(tool)
-}
-
-func printToolInternalsMessage(w io.Writer, toolName string) error {
-	values, err := createTemplateVariablesForToolByName(toolName)
-	if err != nil {
-		return fmt.Errorf("failed to create template variables: %s", err)
-	}
-	return printToolInternalsMessageWithIndentation(w, toolName, 2, values)
-}
-
-func printToolInternalsMessageWithIndentation(w io.Writer, toolName string, indentation int, values map[string]any) error {
-	tool, err := tools.GetByName(toolName)
-	if err != nil {
-		return fmt.Errorf("failed to get tool: %s", err)
-	}
-
-	if tool.Messages.Internals != "" {
-		prefix := pterm.NewStyle(pterm.FgWhite, pterm.BgBlue, pterm.Bold)
-		suffix := pterm.NewStyle(pterm.FgWhite)
-		prefix.Println()
-		prefix.Print(" Internals ")
-		suffix.Printfln(" for %s:", tool.Name)
-		output := tool.ShowInternals(indentation)
-		tmpl, err := template.New("Internals").Parse(output)
-		if err != nil {
-			return fmt.Errorf("failed to parse template: %s", err)
-		}
-		err = tmpl.Execute(w, values)
-		if err != nil {
-			return fmt.Errorf("failed to execute template: %s", err)
-		}
-	}
-
-	return nil
-}
-
-func printToolUsageMessage(w io.Writer, toolName string) error {
-	values, err := createTemplateVariablesForToolByName(toolName)
-	if err != nil {
-		return fmt.Errorf("failed to create template variables: %s", err)
-	}
-	return printToolUsageMessageWithIndentation(w, toolName, 2, values)
-}
-
-func printToolUsageMessageWithIndentation(w io.Writer, toolName string, indentation int, values map[string]any) error {
-	tool, err := tools.GetByName(toolName)
-	if err != nil {
-		return fmt.Errorf("failed to get tool: %s", err)
-	}
-
-	if tool.Messages.Usage != "" {
-		prefix := pterm.NewStyle(pterm.FgWhite, pterm.BgGreen, pterm.Bold)
-		suffix := pterm.NewStyle(pterm.FgWhite)
-		prefix.Println()
-		prefix.Print(" Usage ")
-		suffix.Printfln(" for %s:", tool.Name)
-		output := tool.ShowUsage(indentation)
-		tmpl, err := template.New("Internals").Parse(output)
-		if err != nil {
-			return fmt.Errorf("failed to parse template: %s", err)
-		}
-		err = tmpl.Execute(w, values)
-		if err != nil {
-			return fmt.Errorf("failed to execute template: %s", err)
-		}
-	}
-
-	return nil
-}
-
-func printToolUpdateMessage(w io.Writer, toolName string) error {
-	values, err := createTemplateVariablesForToolByName(toolName)
-	if err != nil {
-		return fmt.Errorf("failed to create template variables: %s", err)
-	}
-	return printToolUpdateMessageWithIndentation(w, toolName, 2, values)
-}
-
-func printToolUpdateMessageWithIndentation(w io.Writer, toolName string, indentation int, values map[string]any) error {
-	tool, err := tools.GetByName(toolName)
-	if err != nil {
-		return fmt.Errorf("failed to get tool: %s", err)
-	}
-
-	if tool.Messages.Update != "" {
-		prefix := pterm.NewStyle(pterm.FgWhite, pterm.BgYellow, pterm.Bold)
-		suffix := pterm.NewStyle(pterm.FgWhite)
-		prefix.Println()
-		prefix.Print(" Update ")
-		suffix.Printfln(" for %s:", tool.Name)
-		output := tool.ShowUpdate(indentation)
-		tmpl, err := template.New("Internals").Parse(output)
-		if err != nil {
-			return fmt.Errorf("failed to parse template: %s", err)
-		}
-		err = tmpl.Execute(w, values)
-		if err != nil {
-			return fmt.Errorf("failed to execute template: %s", err)
-		}
-	}
-
-	return nil
-}
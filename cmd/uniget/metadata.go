@@ -2,21 +2,32 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"os"
 
 	"github.com/google/safearchive/tar"
+	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/spf13/cobra"
 
+	"gitlab.com/uniget-org/cli/internal/config"
 	"gitlab.com/uniget-org/cli/internal/constants"
 	"gitlab.com/uniget-org/cli/pkg/archive"
 	"gitlab.com/uniget-org/cli/pkg/containers"
 	"gitlab.com/uniget-org/cli/pkg/logging"
+	myos "gitlab.com/uniget-org/cli/pkg/os"
+	"gitlab.com/uniget-org/cli/pkg/tool"
 	"gitlab.com/uniget-org/cli/pkg/tui"
 )
 
+var metadataDiffOutput string
+
 func initMetadataCmd() {
 	metadataCmd.AddCommand(downloadMetadataCmd)
+
+	diffMetadataCmd.Flags().StringVarP(&metadataDiffOutput, "output", "o", "table", "Output options: table, json, markdown")
+	metadataCmd.AddCommand(diffMetadataCmd)
 	rootCmd.AddCommand(metadataCmd)
 }
 
@@ -44,20 +55,20 @@ var downloadMetadataCmd = &cobra.Command{
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
 		configuration.AssertCacheDirectory()
-		t, err := containers.FindToolRef([]string{constants.Registry}, []string{constants.ImageRepository}, "metadata", constants.MetadataImageTag)
+		t, err := containers.FindToolRef(cmd.Context(), []string{constants.Registry}, []string{constants.ImageRepository}, "metadata", configuration.GetMetadataImageTag())
 		if err != nil {
 			return fmt.Errorf("error finding metadata: %s", err)
 		}
 		rc := containers.GetRegclient()
 		defer func() {
-			err := rc.Close(context.Background(), t.GetRef())
+			err := rc.Close(cmd.Context(), t.GetRef())
 			if err != nil {
 				logging.Warning.Printfln("error closing registry client: %s", err)
 			}
 		}()
 
 		progressReader := tui.NewProgressReader(nil, nil)
-		err = containers.GetFirstLayerFromRegistry(context.Background(), rc, t.GetRef(), progressReader, func(reader io.ReadCloser) error {
+		err = containers.GetFirstLayerFromRegistry(cmd.Context(), rc, t.GetRef(), progressReader, func(reader io.ReadCloser) error {
 			return archive.ProcessTarContents(reader, func(reader *tar.Reader, header *tar.Header) error {
 				if header.Typeflag == tar.TypeReg && header.Name == "metadata.json" {
 					_, err = io.Copy(cmd.OutOrStdout(), io.NopCloser(reader))
@@ -77,3 +88,137 @@ var downloadMetadataCmd = &cobra.Command{
 		return nil
 	},
 }
+
+var diffMetadataCmd = &cobra.Command{
+	Use:   "diff [<old> [<new>]]",
+	Short: "Show changes between metadata revisions",
+	Long: constants.Header + "\nShow changes between metadata revisions" + `
+
+Revisions are tags, git commits or dates (see update --list-revisions) as
+well as current for the metadata in use and previous for the metadata
+before the last update. Without arguments, previous is compared with
+current. With a single argument, the revision is compared with current.`,
+	Args: cobra.RangeArgs(0, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		oldSpec := "previous"
+		newSpec := "current"
+		if len(args) > 0 {
+			oldSpec = args[0]
+		}
+		if len(args) > 1 {
+			newSpec = args[1]
+		}
+
+		loader := &metadataLoader{}
+		defer loader.Close()
+		oldTools, err := loader.Load(cmd.Context(), oldSpec)
+		if err != nil {
+			return fmt.Errorf("error loading metadata %s: %s", oldSpec, err)
+		}
+		newTools, err := loader.Load(cmd.Context(), newSpec)
+		if err != nil {
+			return fmt.Errorf("error loading metadata %s: %s", newSpec, err)
+		}
+
+		diff := tool.Diff(oldTools, newTools)
+		switch metadataDiffOutput {
+		case "table":
+			printMetadataDiff(cmd.OutOrStdout(), diff, false)
+		case "markdown":
+			printMetadataDiff(cmd.OutOrStdout(), diff, true)
+		case "json":
+			data, err := json.Marshal(diff)
+			if err != nil {
+				return fmt.Errorf("failed to marshal to json: %s", err)
+			}
+			//nolint:errcheck
+			fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		default:
+			return fmt.Errorf("invalid output format: %s", metadataDiffOutput)
+		}
+
+		return nil
+	},
+}
+
+// metadataLoader loads metadata revisions and removes downloaded
+// revisions when closed
+type metadataLoader struct {
+	revisions   []config.MetadataRevision
+	directories []string
+}
+
+func (l *metadataLoader) Load(ctx context.Context, spec string) (*tool.Tools, error) {
+	switch spec {
+	case "current":
+		return configuration.LoadMetadata(configuration.GetMetadataFile())
+	case "previous":
+		if !myos.FileExists(configuration.GetPreviousMetadataFile()) {
+			return nil, fmt.Errorf("no previous metadata found; run update first")
+		}
+		return configuration.LoadMetadata(configuration.GetPreviousMetadataFile())
+	}
+
+	tag := spec
+	if spec != constants.MetadataImageTag {
+		if l.revisions == nil {
+			revisions, err := configuration.ListMetadataRevisions(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("error listing metadata revisions: %s", err)
+			}
+			l.revisions = revisions
+		}
+		revision, err := config.ResolveMetadataRevision(l.revisions, spec)
+		if err != nil {
+			return nil, err
+		}
+		tag = revision.Tag
+	}
+
+	directory, err := os.MkdirTemp("", constants.ProjectName+"-metadata-*")
+	if err != nil {
+		return nil, fmt.Errorf("error creating temporary directory: %s", err)
+	}
+	l.directories = append(l.directories, directory)
+
+	err = configuration.DownloadMetadataRevision(ctx, tag, directory)
+	if err != nil {
+		return nil, err
+	}
+	return configuration.LoadMetadata(directory + "/" + constants.MetadataFileName)
+}
+
+func (l *metadataLoader) Close() {
+	for _, directory := range l.directories {
+		err := os.RemoveAll(directory)
+		if err != nil {
+			logging.Warning.Printfln("Unable to remove %s: %s", directory, err)
+		}
+	}
+}
+
+func printMetadataDiff(w io.Writer, diff tool.ToolsDiff, markdown bool) {
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+
+	t.AppendHeader(table.Row{"Tool", "Change", "Old", "New", "Detail"})
+	for _, change := range diff.Changes {
+		t.AppendRow(table.Row{change.Tool, change.Change, change.Old, change.New, change.Detail})
+	}
+	t.AppendFooter(table.Row{"", "", "", "",
+		fmt.Sprintf("%d added, %d removed, %d renamed, %d updated",
+			diff.Count(tool.ChangeAdded),
+			diff.Count(tool.ChangeRemoved),
+			diff.Count(tool.ChangeRenamed),
+			diff.Count(tool.ChangeVersion),
+		),
+	})
+
+	if markdown {
+		//nolint:errcheck
+		fmt.Fprintf(w, "## Metadata changes from %s to %s\n\n", diff.OldRevision, diff.NewRevision)
+		t.RenderMarkdown()
+		return
+	}
+	t.Render()
+}
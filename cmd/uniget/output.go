@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"gitlab.com/uniget-org/cli/pkg/events"
+	"gitlab.com/uniget-org/cli/pkg/logging"
+)
+
+var outputFormat = events.FormatText
+var emitter = events.NewTextEmitter()
+
+func addOutputFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", events.FormatText, "Output options: "+strings.Join(events.Formats, ", "))
+}
+
+func initEmitter(cmd *cobra.Command) error {
+	if !slices.Contains(events.Formats, outputFormat) {
+		return fmt.Errorf("output format %s not supported", outputFormat)
+	}
+
+	emitter = events.NewEmitter(cmd.OutOrStdout(), outputFormat)
+	emitter.SetCommand(cmd.Name())
+	emitter.AddSink(recordHistory)
+
+	// Keep stdout reserved for events
+	if emitter.IsJSON() {
+		logging.OutputWriter = cmd.ErrOrStderr()
+		logging.Init()
+		pterm.SetDefaultOutput(cmd.ErrOrStderr())
+	}
+
+	return nil
+}
+
+func textOutput(cmd *cobra.Command) io.Writer {
+	if emitter.IsJSON() {
+		return cmd.ErrOrStderr()
+	}
+	return cmd.OutOrStdout()
+}
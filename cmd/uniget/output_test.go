@@ -0,0 +1,21 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"gitlab.com/uniget-org/cli/pkg/events"
+)
+
+func TestOutputFormat(t *testing.T) {
+	tt := []cobraTest{
+		{
+			name:      "unsupported format",
+			args:      []string{"update", "--output", "xml"},
+			expectErr: fmt.Errorf("unable to initialize output: output format xml not supported"),
+		},
+	}
+	runCobraTests(t, tt)
+
+	outputFormat = events.FormatText
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"gitlab.com/uniget-org/cli/pkg/query"
+	"gitlab.com/uniget-org/cli/pkg/tool"
+)
+
+var queryHelp = `
+Queries:
+  Terms like tag:kubernetes are combined using AND (default), OR, NOT and
+  parentheses. Values support wildcards (name:kube*), versions support
+  constraints (version:>=1.0). Terms without a field match name and
+  description. Supported fields: ` + strings.Join(query.Fields, ", ") + `
+  Example: tag:kubernetes AND NOT tag:deprecated license:Apache-2.0 installed:true`
+
+func addQueryFlag(cmd *cobra.Command, target *string, usage string) {
+	cmd.Flags().StringVar(target, "query", "", usage)
+	cmd.Long += "\n" + queryHelp
+}
+
+// selectTools returns the tools matching the query. The status of tools
+// is only updated if the query depends on it.
+func selectTools(source *tool.Tools, expression string) (*tool.Tools, error) {
+	q, err := query.Parse(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query: %s", err)
+	}
+
+	if q.NeedsStatus() {
+		for index := range source.Tools {
+			err := source.Tools[index].UpdateStatus(
+				configuration.Prefix,
+				configuration.Target,
+				configuration.GetCacheDirectory(),
+				configuration.Arch,
+				configuration.AltArch,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("failed to update status for tool %s: %s", source.Tools[index].Name, err)
+			}
+		}
+	}
+
+	return q.Filter(source), nil
+}
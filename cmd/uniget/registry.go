@@ -1,17 +1,23 @@
 package main
 
 import (
-	"context"
 	"fmt"
+	"net/http"
+	"net/url"
 	"sort"
+	"time"
 
+	"github.com/dustin/go-humanize"
+	"github.com/pterm/pterm"
 	"github.com/regclient/regclient/pkg/template"
 	"github.com/regclient/regclient/types/manifest"
 	"github.com/regclient/regclient/types/platform"
 	"github.com/regclient/regclient/types/ref"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"gitlab.com/uniget-org/cli/internal/constants"
 	"gitlab.com/uniget-org/cli/pkg/containers"
+	"gitlab.com/uniget-org/cli/pkg/logging"
 )
 
 var (
@@ -20,6 +26,14 @@ var (
 	regFormat           = "pretty"
 	regManifestPlatform = ""
 	regSizeHuman        = false
+
+	regServeListen           = ":5000"
+	regServeStorage          = ""
+	regServeMaxSize          = ""
+	regServeTTL              = 7 * 24 * time.Hour
+	regServeUpstream         = "https://" + constants.Registry
+	regServeRepository       = constants.Organization
+	regServeEvictionInterval = 5 * time.Minute
 )
 
 func initRegCmd() {
@@ -39,11 +53,30 @@ func initRegCmd() {
 
 	regRefCmd.Flags().StringVarP(&regVersion, "version", "", regVersion, "Specify the version for the reference")
 
+	regServeCmd.Flags().StringVar(&regServeListen, "listen", regServeListen, "Address to listen on")
+	regServeCmd.Flags().StringVar(&regServeStorage, "storage", regServeStorage, "Directory to store cached content in")
+	regServeCmd.Flags().StringVar(&regServeMaxSize, "max-size", regServeMaxSize, "Maximum size of cached content, e.g. 20GB (unlimited if empty)")
+	regServeCmd.Flags().DurationVar(&regServeTTL, "ttl", regServeTTL, "Remove content which was not requested again within this duration (0 keeps content)")
+	regServeCmd.Flags().StringVar(&regServeUpstream, "upstream", regServeUpstream, "URL of the upstream registry")
+	regServeCmd.Flags().StringVar(&regServeRepository, "repository", regServeRepository, "Only serve repositories below this prefix (all if empty)")
+	regServeCmd.Flags().DurationVar(&regServeEvictionInterval, "eviction-interval", regServeEvictionInterval, "Interval for checking the maximum size")
+	err := regServeCmd.MarkFlagRequired("storage")
+	if err != nil {
+		logging.Error.Printfln("Failed to mark flag as required: %v", err)
+	}
+	for _, flag := range []string{"upstream", "eviction-interval"} {
+		err = regServeCmd.Flags().MarkHidden(flag)
+		if err != nil {
+			logging.Error.Printfln("Failed to mark flag as hidden: %v", err)
+		}
+	}
+
 	regCmd.AddCommand(regIndexCmd)
 	regCmd.AddCommand(regManifestCmd)
 	regCmd.AddCommand(regSizeCmd)
 	regCmd.AddCommand(regTagsCmd)
 	regCmd.AddCommand(regRefCmd)
+	regCmd.AddCommand(regServeCmd)
 
 	rootCmd.AddCommand(regCmd)
 }
@@ -54,10 +87,9 @@ var regCmd = &cobra.Command{
 		"reg",
 		"r",
 	},
-	Short:   "Display installation paths as environment variables",
-	Long:    constants.Header + "\nDisplay installation paths as environment variables",
+	Short:   "Inspect tool images and serve a pull-through cache",
+	Long:    constants.Header + "\nInspect tool images and serve a pull-through cache",
 	GroupID: "helper",
-	Hidden:  true,
 }
 
 func getFormatString() string {
@@ -116,7 +148,7 @@ var regIndexCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
 		format := getFormatString()
 
-		ctx := context.Background()
+		ctx := cmd.Context()
 
 		image := buildReference(args[0])
 		r, err := ref.New(image)
@@ -154,7 +186,7 @@ var regManifestCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
 		format := getFormatString()
 
-		ctx := context.Background()
+		ctx := cmd.Context()
 
 		image := buildReference(args[0])
 		r, err := ref.New(image)
@@ -198,7 +230,7 @@ var regSizeCmd = &cobra.Command{
 		return tools.GetNames(), cobra.ShellCompDirectiveNoFileComp
 	},
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
-		ctx := context.Background()
+		ctx := cmd.Context()
 
 		image := buildReference(args[0])
 		r, err := ref.New(image)
@@ -259,7 +291,7 @@ var regTagsCmd = &cobra.Command{
 		return tools.GetNames(), cobra.ShellCompDirectiveNoFileComp
 	},
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
-		ctx := context.Background()
+		ctx := cmd.Context()
 
 		image := buildReference(args[0])
 		r, err := ref.New(image)
@@ -285,3 +317,93 @@ var regTagsCmd = &cobra.Command{
 		return nil
 	},
 }
+
+var regServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve a pull-through cache for tools and metadata",
+	Long: constants.Header + "\nServe a pull-through cache for tools and metadata" + `
+
+The cache fetches missing content from the upstream registry and stores it in
+the storage directory. When the cache exceeds --max-size, the least recently
+used layers are removed. Clients use the cache as a mirror:
+
+  UNIGET_MIRRORS=ghcr.io=cache.example.com:5000
+  UNIGET_TLSHOSTS=cache.example.com:5000=disabled`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		ctx := cmd.Context()
+
+		if regServeEvictionInterval <= 0 {
+			return fmt.Errorf("eviction interval must be positive")
+		}
+
+		var maxSize uint64
+		if len(regServeMaxSize) > 0 {
+			maxSize, err = humanize.ParseBytes(regServeMaxSize)
+			if err != nil {
+				return fmt.Errorf("invalid maximum size %s: %s", regServeMaxSize, err)
+			}
+		}
+
+		opts := containers.PullThroughCacheOptions{
+			StorageDirectory: regServeStorage,
+			Upstream:         regServeUpstream,
+			Repository:       regServeRepository,
+			MaxSize:          int64(maxSize), // #nosec G115 -- sizes beyond int64 are not realistic
+			TTL:              regServeTTL,
+		}
+		upstream, err := url.Parse(regServeUpstream)
+		if err != nil {
+			return fmt.Errorf("invalid upstream registry URL %s: %s", regServeUpstream, err)
+		}
+		credentials, err := configuration.LoadRegistryCredentials()
+		if err != nil {
+			return err
+		}
+		for _, credential := range credentials {
+			if credential.Registry == upstream.Host {
+				opts.Username = credential.Username
+				opts.Password = credential.Password
+			}
+		}
+
+		// distribution logs every request at info level
+		if logging.Level != pterm.LogLevelDebug && logging.Level != pterm.LogLevelTrace {
+			logrus.SetLevel(logrus.WarnLevel)
+		}
+
+		cache, err := containers.NewPullThroughCache(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("failed to create cache: %s", err)
+		}
+
+		size, err := cache.Size()
+		if err != nil {
+			return fmt.Errorf("failed to determine size of cache: %s", err)
+		}
+		logging.Info.Printfln("Cache in %s contains %s", regServeStorage, humanize.IBytes(uint64(size))) // #nosec G115 -- size is not negative
+		if maxSize > 0 {
+			removed, err := cache.Evict()
+			if err != nil {
+				return fmt.Errorf("failed to evict blobs: %s", err)
+			}
+			if removed > 0 {
+				logging.Info.Printfln("Evicted %s from the cache", humanize.IBytes(uint64(removed))) // #nosec G115 -- size is not negative
+			}
+			go cache.EvictPeriodically(ctx, regServeEvictionInterval)
+		}
+
+		httpServer := &http.Server{
+			Addr:              regServeListen,
+			Handler:           cache.Handler(),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		logging.Info.Printfln("Serving cache for %s on %s", regServeUpstream, regServeListen)
+		err = listenAndServe(ctx, httpServer)
+		if err != nil {
+			return fmt.Errorf("failed to serve cache: %s", err)
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRegServeEvictionInterval(t *testing.T) {
+	interval := regServeEvictionInterval
+	t.Cleanup(func() { regServeEvictionInterval = interval })
+
+	for _, value := range []time.Duration{0, -time.Minute} {
+		regServeEvictionInterval = value
+		err := regServeCmd.RunE(regServeCmd, []string{})
+		if err == nil {
+			t.Errorf("expected error for eviction interval %s", value)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -12,6 +13,8 @@ import (
 	"github.com/spf13/cobra"
 	"gitlab.com/uniget-org/cli/internal/constants"
 	"gitlab.com/uniget-org/cli/pkg/logging"
+	"gitlab.com/uniget-org/cli/pkg/tool"
+	"gitlab.com/uniget-org/cli/pkg/transport"
 
 	"github.com/charmbracelet/glamour"
 )
@@ -40,70 +43,12 @@ var releaseNotesCmd = &cobra.Command{
 			return fmt.Errorf("failed to get tool: %s", err)
 		}
 
-		var payload []byte
-		var bodyFieldName string
-		versionTag := tool.Version
-		if len(releaseNotesVersion) > 0 {
-			logging.Debugf("Using version %s for release notes", releaseNotesVersion)
-			versionTag = releaseNotesVersion
-		}
-		if tool.Renovate.ExtractVersion != "" {
-			re, err := regexp.Compile(`\(\?[^)]+\)`)
-			if err != nil {
-				return fmt.Errorf("cannot compile regexp: %w", err)
-			}
-			versionTag = re.ReplaceAllString(tool.Renovate.ExtractVersion, versionTag)
-			versionTag = strings.ReplaceAll(versionTag, "^", "")
-			versionTag = strings.ReplaceAll(versionTag, "$", "")
-		}
-		logging.Debugf("Using version tag %s for release notes", versionTag)
-		switch tool.Renovate.Datasource {
-		case "github-releases":
-			payload, err = fetchBodyFromGitHubRelease(tool.Renovate.Package, versionTag)
-			if err != nil {
-				return fmt.Errorf("failed to fetch body of GitHub release for tool %s: %s", tool.Name, err)
-			}
-			bodyFieldName = "body"
-
-		case "gitlab-releases":
-			payload, err = fetchBodyFromGitLabRelease(tool.Renovate.Package, versionTag)
-			if err != nil {
-				return fmt.Errorf("failed to fetch body of GitLab release for tool %s: %s", tool.Name, err)
-			}
-			bodyFieldName = "description"
-
-		case "gitea-releases":
-			payload, err = fetchBodyFromGiteaRelease(tool.Renovate.Package, versionTag)
-			if err != nil {
-				return fmt.Errorf("failed to fetch body of Gitea release for tool %s: %s", tool.Name, err)
-			}
-			bodyFieldName = "body"
-
-		case "npm":
-			payload, err = fetchBodyFromNpm(tool.Renovate.Package, versionTag)
-			if err != nil {
-				return fmt.Errorf("failed to fetch body from npm for tool %s: %s", tool.Name, err)
-			}
-			bodyFieldName = "body"
-
-		case "pypi":
-			payload, err = fetchBodyFromPypi(tool.Renovate.Package, versionTag)
-			if err != nil {
-				return fmt.Errorf("failed to fetch body from pypi for tool %s: %s", tool.Name, err)
-			}
-			bodyFieldName = "body"
-
-		default:
-			return fmt.Errorf("release notes are not available for datasource %s", tool.Renovate.Datasource)
-		}
-
-		var result map[string]any
-		err = json.Unmarshal(payload, &result)
+		notes, err := fetchReleaseNotes(cmd.Context(), tool, releaseNotesVersion)
 		if err != nil {
-			return fmt.Errorf("failed to parse body of GitHub release for tool %s: %s", tool.Name, err)
+			return err
 		}
 
-		out, err := glamour.Render(result[bodyFieldName].(string), "dark")
+		out, err := glamour.Render(notes, "dark")
 		if err != nil {
 			return fmt.Errorf("failed to render release notes: %s", err)
 		}
@@ -113,16 +58,88 @@ var releaseNotesCmd = &cobra.Command{
 	},
 }
 
-func fetchUrl(url string) ([]byte, error) {
+// fetchReleaseNotes returns the release notes of a tool as markdown. The
+// release version defaults to the version in the metadata.
+func fetchReleaseNotes(ctx context.Context, t *tool.Tool, releaseVersion string) (string, error) {
+	var err error
+	var payload []byte
+	var bodyFieldName string
+	versionTag := t.Version
+	if len(releaseVersion) > 0 {
+		logging.Debugf("Using version %s for release notes", releaseVersion)
+		versionTag = releaseVersion
+	}
+	if t.Renovate.ExtractVersion != "" {
+		re, err := regexp.Compile(`\(\?[^)]+\)`)
+		if err != nil {
+			return "", fmt.Errorf("cannot compile regexp: %w", err)
+		}
+		versionTag = re.ReplaceAllString(t.Renovate.ExtractVersion, versionTag)
+		versionTag = strings.ReplaceAll(versionTag, "^", "")
+		versionTag = strings.ReplaceAll(versionTag, "$", "")
+	}
+	logging.Debugf("Using version tag %s for release notes", versionTag)
+	switch t.Renovate.Datasource {
+	case "github-releases":
+		payload, err = fetchBodyFromGitHubRelease(ctx, t.Renovate.Package, versionTag)
+		if err != nil {
+			return "", fmt.Errorf("failed to fetch body of GitHub release for tool %s: %s", t.Name, err)
+		}
+		bodyFieldName = "body"
+
+	case "gitlab-releases":
+		payload, err = fetchBodyFromGitLabRelease(ctx, t.Renovate.Package, versionTag)
+		if err != nil {
+			return "", fmt.Errorf("failed to fetch body of GitLab release for tool %s: %s", t.Name, err)
+		}
+		bodyFieldName = "description"
+
+	case "gitea-releases":
+		payload, err = fetchBodyFromGiteaRelease(ctx, t.Renovate.Package, versionTag)
+		if err != nil {
+			return "", fmt.Errorf("failed to fetch body of Gitea release for tool %s: %s", t.Name, err)
+		}
+		bodyFieldName = "body"
+
+	case "npm":
+		payload, err = fetchBodyFromNpm(ctx, t.Renovate.Package, versionTag)
+		if err != nil {
+			return "", fmt.Errorf("failed to fetch body from npm for tool %s: %s", t.Name, err)
+		}
+		bodyFieldName = "body"
+
+	case "pypi":
+		payload, err = fetchBodyFromPypi(ctx, t.Renovate.Package, versionTag)
+		if err != nil {
+			return "", fmt.Errorf("failed to fetch body from pypi for tool %s: %s", t.Name, err)
+		}
+		bodyFieldName = "body"
+
+	default:
+		return "", fmt.Errorf("release notes are not available for datasource %s", t.Renovate.Datasource)
+	}
+
+	var result map[string]any
+	err = json.Unmarshal(payload, &result)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse body of GitHub release for tool %s: %s", t.Name, err)
+	}
+
+	body, ok := result[bodyFieldName].(string)
+	if !ok {
+		return "", fmt.Errorf("release notes for tool %s are empty", t.Name)
+	}
+	return body, nil
+}
+
+func fetchUrl(ctx context.Context, url string) ([]byte, error) {
 	logging.Debugf("Fetching %s", url)
 
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return []byte{}, fmt.Errorf("failed to create request: %s", err)
 	}
-	req.Header.Set("User-Agent", fmt.Sprintf("%s/%s", constants.ProjectName, version))
-	resp, err := client.Do(req) // #nosec G704 -- Called from internal functions with controlled URLs
+	resp, err := transport.Client().Do(req) // #nosec G704 -- Called from internal functions with controlled URLs
 	if err != nil {
 		return []byte{}, fmt.Errorf("failed fetch url: %s", err)
 	}
@@ -145,7 +162,7 @@ func fetchUrl(url string) ([]byte, error) {
 	return bodyBytes, nil
 }
 
-func fetchBodyFromGitHubRelease(project string, versionTag string) ([]byte, error) {
+func fetchBodyFromGitHubRelease(ctx context.Context, project string, versionTag string) ([]byte, error) {
 	if len(os.Getenv("GITHUB_TOKEN")) == 0 {
 		logging.Warning.Printfln("GITHUB_TOKEN is not set. You may experience failed requests due to rate limiting.")
 	}
@@ -153,7 +170,7 @@ func fetchBodyFromGitHubRelease(project string, versionTag string) ([]byte, erro
 	url := fmt.Sprintf("https://api.github.com/repos/%s/releases/tags/%s", project, versionTag)
 	logging.Debugf("Fetching release notes from %s", url)
 
-	bodyBytes, err := fetchUrl(url)
+	bodyBytes, err := fetchUrl(ctx, url)
 	if err != nil {
 		return []byte{}, fmt.Errorf("failed to fetch body of GitHub release: %s", err)
 	}
@@ -161,12 +178,12 @@ func fetchBodyFromGitHubRelease(project string, versionTag string) ([]byte, erro
 	return bodyBytes, nil
 }
 
-func fetchBodyFromGitLabRelease(project string, versionTag string) ([]byte, error) {
+func fetchBodyFromGitLabRelease(ctx context.Context, project string, versionTag string) ([]byte, error) {
 	projectUrlEncoded := strings.ReplaceAll(project, "/", "%2f")
 	url := fmt.Sprintf("https://gitlab.com/api/v4/projects/%s/releases/%s", projectUrlEncoded, versionTag)
 	logging.Debugf("Fetching release notes from %s", url)
 
-	bodyBytes, err := fetchUrl(url)
+	bodyBytes, err := fetchUrl(ctx, url)
 	if err != nil {
 		return []byte{}, fmt.Errorf("failed to fetch body of GitLab release: %s", err)
 	}
@@ -174,11 +191,11 @@ func fetchBodyFromGitLabRelease(project string, versionTag string) ([]byte, erro
 	return bodyBytes, nil
 }
 
-func fetchBodyFromGiteaRelease(project string, versionTag string) ([]byte, error) {
+func fetchBodyFromGiteaRelease(ctx context.Context, project string, versionTag string) ([]byte, error) {
 	url := fmt.Sprintf("https://gitea.com/api/v1/repos/%s/releases/tags/%s", project, versionTag)
 	logging.Debugf("Fetching release notes from %s", url)
 
-	bodyBytes, err := fetchUrl(url)
+	bodyBytes, err := fetchUrl(ctx, url)
 	if err != nil {
 		return []byte{}, fmt.Errorf("failed to fetch body of Gitea release: %s", err)
 	}
@@ -198,11 +215,11 @@ func extractGitHubOwnerAndProject(url string) (string, error) {
 	return fmt.Sprintf("%s/%s", owner, project), nil
 }
 
-func fetchBodyFromNpm(project string, versionTag string) ([]byte, error) {
+func fetchBodyFromNpm(ctx context.Context, project string, versionTag string) ([]byte, error) {
 	url := fmt.Sprintf("https://registry.npmjs.com/%s/%s", project, versionTag)
 	logging.Debugf("Fetching release notes from %s", url)
 
-	bodyBytes, err := fetchUrl(url)
+	bodyBytes, err := fetchUrl(ctx, url)
 	if err != nil {
 		return []byte{}, fmt.Errorf("failed to fetch body from npm: %s", err)
 	}
@@ -220,7 +237,7 @@ func fetchBodyFromNpm(project string, versionTag string) ([]byte, error) {
 			return []byte{}, fmt.Errorf("failed to fetch body of GitHub release for npm package: %s", err)
 		}
 
-		GhBody, err := fetchBodyFromGitHubRelease(project, versionTag)
+		GhBody, err := fetchBodyFromGitHubRelease(ctx, project, versionTag)
 		if err != nil {
 			return []byte{}, fmt.Errorf("failed to fetch body of GitHub release for npm package: %s", err)
 		}
@@ -232,11 +249,11 @@ func fetchBodyFromNpm(project string, versionTag string) ([]byte, error) {
 	}
 }
 
-func fetchBodyFromPypi(project string, versionTag string) ([]byte, error) {
+func fetchBodyFromPypi(ctx context.Context, project string, versionTag string) ([]byte, error) {
 	url := fmt.Sprintf("https://pypi.org/pypi/%s/%s/json", project, versionTag)
 	logging.Debugf("Fetching release notes from %s", url)
 
-	bodyBytes, err := fetchUrl(url)
+	bodyBytes, err := fetchUrl(ctx, url)
 	if err != nil {
 		return []byte{}, fmt.Errorf("failed to fetch body from pypi: %s", err)
 	}
@@ -255,7 +272,7 @@ func fetchBodyFromPypi(project string, versionTag string) ([]byte, error) {
 			return []byte{}, fmt.Errorf("failed to extract owner/project from homepage <%s>: %s", urls["Homepage"].(string), err)
 		}
 
-		GhBody, err := fetchBodyFromGitHubRelease(project, versionTag)
+		GhBody, err := fetchBodyFromGitHubRelease(ctx, project, versionTag)
 		if err != nil {
 			return []byte{}, fmt.Errorf("failed to fetch body of GitHub release for npm package: %s", err)
 		}
@@ -0,0 +1,505 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/sys/unix"
+
+	"gitlab.com/uniget-org/cli/internal/constants"
+	"gitlab.com/uniget-org/cli/pkg/logging"
+	"gitlab.com/uniget-org/cli/pkg/systemd"
+)
+
+const (
+	scheduleBackendSystemd = "systemd"
+	scheduleBackendCron    = "cron"
+
+	scheduleJobUpgrade     = "upgrade"
+	scheduleJobNotify      = "notify"
+	scheduleJobSelfUpgrade = "self-upgrade"
+
+	// Managed entries are identified by these markers instead of
+	// matching every line or file mentioning uniget
+	scheduleCronMarker = "# uniget-managed:"
+	scheduleUnitMarker = "# Managed by uniget schedule. Do not edit."
+)
+
+var (
+	scheduleBackend             = ""
+	scheduleUpgradeCron         = "30 0 * * *"
+	scheduleSelfUpgradeCron     = "0 0 * * *"
+	scheduleUpgradeCalendar     = "*-*-* 00:30:00"
+	scheduleSelfUpgradeCalendar = "*-*-* 00:00:00"
+	scheduleRandomizedDelay     = 30 * time.Minute
+	scheduleNotifyOnly          = false
+	scheduleRunJob              = ""
+	scheduleRunDelay            time.Duration
+	scheduleRunLogFile          = ""
+)
+
+// Entries created by previous versions of uniget cron create
+var scheduleLegacyCronLine = regexp.MustCompile(`^\S+\s+\S+\s+\S+\s+\S+\s+\S+\s+uniget --user=(true|false) (upgrade --auto-update|self-upgrade)$`)
+
+func initScheduleCmd() {
+	rootCmd.AddCommand(scheduleCmd)
+
+	scheduleCmd.AddCommand(scheduleCreateCmd)
+	scheduleCmd.AddCommand(scheduleRemoveCmd)
+	scheduleCmd.AddCommand(scheduleRunCmd)
+
+	scheduleCmd.PersistentFlags().StringVar(&scheduleBackend, "backend", scheduleBackend, "Scheduler backend (systemd, cron). Defaults to systemd if available")
+	scheduleCreateCmd.Flags().StringVar(&scheduleUpgradeCron, "upgrade-cron", scheduleUpgradeCron, "Cron schedule to run cron jobs for tool upgrade")
+	scheduleCreateCmd.Flags().StringVar(&scheduleSelfUpgradeCron, "self-upgrade-cron", scheduleSelfUpgradeCron, "Cron schedule to run cron jobs for self-upgrade")
+	scheduleCreateCmd.Flags().StringVar(&scheduleUpgradeCalendar, "upgrade-calendar", scheduleUpgradeCalendar, "systemd calendar event for tool upgrade")
+	scheduleCreateCmd.Flags().StringVar(&scheduleSelfUpgradeCalendar, "self-upgrade-calendar", scheduleSelfUpgradeCalendar, "systemd calendar event for self-upgrade")
+	scheduleCreateCmd.Flags().DurationVar(&scheduleRandomizedDelay, "randomized-delay", scheduleRandomizedDelay, "Delay jobs by a random time up to this duration")
+	scheduleCreateCmd.Flags().BoolVar(&scheduleNotifyOnly, "notify-only", scheduleNotifyOnly, "Only update metadata and report available upgrades")
+
+	scheduleRunCmd.Flags().StringVar(&scheduleRunJob, "job", "", "Job to run ("+scheduleJobUpgrade+", "+scheduleJobNotify+", "+scheduleJobSelfUpgrade+")")
+	scheduleRunCmd.Flags().DurationVar(&scheduleRunDelay, "randomized-delay", 0, "Delay job by a random time up to this duration")
+	scheduleRunCmd.Flags().StringVar(&scheduleRunLogFile, "log-file", "", "Append output to this file")
+}
+
+var scheduleCmd = &cobra.Command{
+	Use: "schedule",
+	Aliases: []string{
+		"cron",
+		"s",
+	},
+	Short:   "Manage scheduled upgrades",
+	Long:    constants.Header + "\nManage scheduled upgrades using systemd timers or cron jobs",
+	GroupID: "config",
+	Args:    cobra.NoArgs,
+}
+
+var scheduleCreateCmd = &cobra.Command{
+	Use: "create",
+	Aliases: []string{
+		"c",
+	},
+	Short: "Create scheduled jobs",
+	Long:  constants.Header + "\nCreate scheduled jobs",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		backend, err := getScheduleBackend()
+		if err != nil {
+			return err
+		}
+
+		switch backend {
+		case scheduleBackendSystemd:
+			return createTimers()
+		default:
+			return createCron()
+		}
+	},
+}
+
+var scheduleRemoveCmd = &cobra.Command{
+	Use: "remove",
+	Aliases: []string{
+		"r",
+	},
+	Short: "Remove scheduled jobs",
+	Long:  constants.Header + "\nRemove scheduled jobs",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		backend, err := getScheduleBackend()
+		if err != nil {
+			return err
+		}
+
+		switch backend {
+		case scheduleBackendSystemd:
+			return removeTimers()
+		default:
+			return removeCron()
+		}
+	},
+}
+
+var scheduleRunCmd = &cobra.Command{
+	Use:    "run",
+	Short:  "Run scheduled job",
+	Long:   constants.Header + "\nRun scheduled job. This is called by systemd timers and cron jobs.",
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runScheduledJob(cmd.OutOrStdout())
+	},
+}
+
+type scheduleJob struct {
+	Name     string
+	Cron     string
+	Calendar string
+}
+
+func getScheduleJobs() []scheduleJob {
+	if scheduleNotifyOnly {
+		return []scheduleJob{
+			{Name: scheduleJobNotify, Cron: scheduleUpgradeCron, Calendar: scheduleUpgradeCalendar},
+		}
+	}
+
+	return []scheduleJob{
+		{Name: scheduleJobUpgrade, Cron: scheduleUpgradeCron, Calendar: scheduleUpgradeCalendar},
+		{Name: scheduleJobSelfUpgrade, Cron: scheduleSelfUpgradeCron, Calendar: scheduleSelfUpgradeCalendar},
+	}
+}
+
+func getScheduleJobCommands(job string) ([][]string, error) {
+	switch job {
+	case scheduleJobUpgrade:
+		return [][]string{{"upgrade", "--auto-update"}}, nil
+	case scheduleJobNotify:
+		return [][]string{{"update"}, {"upgrade", "--dry-run"}}, nil
+	case scheduleJobSelfUpgrade:
+		return [][]string{{"self-upgrade"}}, nil
+	}
+	return nil, fmt.Errorf("unknown job %s", job)
+}
+
+func getScheduleBackend() (string, error) {
+	switch scheduleBackend {
+	case scheduleBackendSystemd, scheduleBackendCron:
+		return scheduleBackend, nil
+	case "":
+		if systemd.NewSystemctl(configuration.User).IsAvailable() {
+			return scheduleBackendSystemd, nil
+		}
+		return scheduleBackendCron, nil
+	}
+	return "", fmt.Errorf("unsupported backend %s", scheduleBackend)
+}
+
+func getUnigetExecutable() string {
+	executable, err := os.Executable()
+	if err != nil {
+		logging.Warning.Printfln("Unable to determine path of uniget: %s", err)
+		return constants.ProjectName
+	}
+	return executable
+}
+
+func getScheduleRunCommand(job string) string {
+	return fmt.Sprintf("%s --user=%t schedule run --job %s", getUnigetExecutable(), configuration.User, job)
+}
+
+func getScheduleLogFile() string {
+	return configuration.GetLibDirectory() + "/schedule.log"
+}
+
+func getUserCrontab() ([]string, error) {
+	cmd := exec.Command("crontab", "-l")
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && strings.Contains(string(exitErr.Stderr), "no crontab") {
+			return []string{}, nil
+		}
+		return nil, fmt.Errorf("cannot get user crontab: %w", err)
+	}
+
+	lines := []string{}
+	if len(output) > 0 {
+		lines = strings.Split(strings.TrimSuffix(string(output), "\n"), "\n")
+	}
+
+	return lines, nil
+}
+
+func removeManagedCronLines(lines []string) []string {
+	newLines := []string{}
+
+	for _, line := range lines {
+		if strings.Contains(line, scheduleCronMarker) || scheduleLegacyCronLine.MatchString(strings.TrimSpace(line)) {
+			continue
+		}
+		newLines = append(newLines, line)
+	}
+
+	return newLines
+}
+
+func getCronLine(job scheduleJob) string {
+	return fmt.Sprintf("%s %s --randomized-delay %s --log-file %s %s%s",
+		job.Cron,
+		getScheduleRunCommand(job.Name),
+		scheduleRandomizedDelay,
+		getScheduleLogFile(),
+		scheduleCronMarker,
+		job.Name,
+	)
+}
+
+func setUserCrontab(lines []string) error {
+	input := strings.Join(lines, "\n") + "\n"
+	if len(lines) == 0 {
+		input = ""
+	}
+	cmd := exec.Command("crontab", "-")
+	cmd.Stdin = strings.NewReader(input)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("cannot set user crontab: %s", output)
+	}
+
+	return nil
+}
+
+func createCron() error {
+	lines, err := getUserCrontab()
+	if err != nil {
+		return fmt.Errorf("cannot get user crontab: %w", err)
+	}
+	lines = removeManagedCronLines(lines)
+	for _, job := range getScheduleJobs() {
+		lines = append(lines, getCronLine(job))
+	}
+
+	err = setUserCrontab(lines)
+	if err != nil {
+		return fmt.Errorf("cannot set user crontab: %w", err)
+	}
+
+	logging.Success.Printfln("Created cron jobs. Output is written to %s", getScheduleLogFile())
+	return nil
+}
+
+func removeCron() error {
+	lines, err := getUserCrontab()
+	if err != nil {
+		return fmt.Errorf("cannot get user crontab: %w", err)
+	}
+	lines = removeManagedCronLines(lines)
+
+	err = setUserCrontab(lines)
+	if err != nil {
+		return fmt.Errorf("cannot set user crontab: %w", err)
+	}
+
+	return nil
+}
+
+func getTimerDirectory() string {
+	if configuration.User {
+		return configuration.Prefix + "/" + configuration.ConfigRoot + "/systemd/user"
+	}
+	return "/etc/systemd/system"
+}
+
+func getServiceUnit(job scheduleJob) string {
+	return fmt.Sprintf(`%s
+[Unit]
+Description=uniget %s
+Wants=network-online.target
+After=network-online.target
+
+[Service]
+Type=oneshot
+ExecStart=%s
+StandardOutput=journal
+StandardError=journal
+`, scheduleUnitMarker, job.Name, getScheduleRunCommand(job.Name))
+}
+
+func getTimerUnit(job scheduleJob) string {
+	return fmt.Sprintf(`%s
+[Unit]
+Description=Run uniget %s on schedule
+
+[Timer]
+OnCalendar=%s
+RandomizedDelaySec=%d
+Persistent=true
+
+[Install]
+WantedBy=timers.target
+`, scheduleUnitMarker, job.Name, job.Calendar, int(scheduleRandomizedDelay.Seconds()))
+}
+
+func findManagedTimers() ([]string, error) {
+	files, err := filepath.Glob(getTimerDirectory() + "/" + constants.ProjectName + "-*")
+	if err != nil {
+		return nil, fmt.Errorf("unable to list units: %s", err)
+	}
+
+	managedFiles := []string{}
+	for _, file := range files {
+		data, err := os.ReadFile(file) // #nosec G304 -- Path is built from configuration
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s: %s", file, err)
+		}
+		if strings.HasPrefix(string(data), scheduleUnitMarker) {
+			managedFiles = append(managedFiles, file)
+		}
+	}
+
+	return managedFiles, nil
+}
+
+func removeTimers() error {
+	systemctl := systemd.NewSystemctl(configuration.User)
+
+	files, err := findManagedTimers()
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return nil
+	}
+
+	timers := []string{}
+	for _, file := range files {
+		if strings.HasSuffix(file, ".timer") {
+			timers = append(timers, filepath.Base(file))
+		}
+	}
+	if len(timers) > 0 {
+		err = systemctl.DisableNow(timers...)
+		if err != nil {
+			logging.Warning.Printfln("Unable to disable timers: %s", err)
+		}
+	}
+
+	for _, file := range files {
+		err = os.Remove(file)
+		if err != nil {
+			return fmt.Errorf("unable to remove %s: %s", file, err)
+		}
+	}
+
+	err = systemctl.DaemonReload()
+	if err != nil {
+		return fmt.Errorf("unable to reload systemd: %s", err)
+	}
+
+	return nil
+}
+
+func createTimers() error {
+	err := removeTimers()
+	if err != nil {
+		return fmt.Errorf("unable to remove existing timers: %s", err)
+	}
+
+	err = os.MkdirAll(getTimerDirectory(), 0755) // #nosec G301 -- Directory must be accessible by all users
+	if err != nil {
+		return fmt.Errorf("unable to create directory %s: %s", getTimerDirectory(), err)
+	}
+
+	timers := []string{}
+	for _, job := range getScheduleJobs() {
+		unitName := constants.ProjectName + "-" + job.Name
+
+		err = os.WriteFile(getTimerDirectory()+"/"+unitName+".service", []byte(getServiceUnit(job)), 0644) // #nosec G306 -- File must be world-readable
+		if err != nil {
+			return fmt.Errorf("unable to write service for %s: %s", job.Name, err)
+		}
+		err = os.WriteFile(getTimerDirectory()+"/"+unitName+".timer", []byte(getTimerUnit(job)), 0644) // #nosec G306 -- File must be world-readable
+		if err != nil {
+			return fmt.Errorf("unable to write timer for %s: %s", job.Name, err)
+		}
+		timers = append(timers, unitName+".timer")
+	}
+
+	systemctl := systemd.NewSystemctl(configuration.User)
+	err = systemctl.DaemonReload()
+	if err != nil {
+		return fmt.Errorf("unable to reload systemd: %s", err)
+	}
+	err = systemctl.EnableNow(timers...)
+	if err != nil {
+		return fmt.Errorf("unable to enable timers: %s", err)
+	}
+
+	journalctl := "journalctl"
+	if configuration.User {
+		journalctl += " --user"
+	}
+	logging.Success.Printfln("Created timers %s. Check output using %s -u '%s-*'", strings.Join(timers, ", "), journalctl, constants.ProjectName)
+	return nil
+}
+
+// lockScheduledRun prevents scheduled jobs from running concurrently. The
+// lock is released when the returned file is closed.
+func lockScheduledRun() (*os.File, error) {
+	lockFile := configuration.GetLibDirectory() + "/schedule.lock"
+	err := os.MkdirAll(filepath.Dir(lockFile), 0755) // #nosec G301 -- Directory must be accessible by all users
+	if err != nil {
+		return nil, fmt.Errorf("unable to create directory for %s: %s", lockFile, err)
+	}
+
+	file, err := os.OpenFile(lockFile, os.O_CREATE|os.O_RDWR, 0600) // #nosec G304 -- Path is built from configuration
+	if err != nil {
+		return nil, fmt.Errorf("unable to open %s: %s", lockFile, err)
+	}
+
+	err = unix.Flock(int(file.Fd()), unix.LOCK_EX|unix.LOCK_NB) // #nosec G115 -- File descriptors fit into int
+	if err != nil {
+		//nolint:errcheck
+		file.Close()
+		return nil, err
+	}
+
+	return file, nil
+}
+
+func runScheduledJob(w io.Writer) error {
+	commands, err := getScheduleJobCommands(scheduleRunJob)
+	if err != nil {
+		return err
+	}
+
+	lock, err := lockScheduledRun()
+	if errors.Is(err, unix.EWOULDBLOCK) {
+		logging.Warning.Println("Another scheduled job is running. Skipping.")
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("unable to lock: %s", err)
+	}
+	//nolint:errcheck
+	defer lock.Close()
+
+	if scheduleRunDelay > 0 {
+		delay := rand.N(scheduleRunDelay) // #nosec G404 -- Jitter does not require a secure random number
+		logging.Debugf("Waiting %s before running %s", delay, scheduleRunJob)
+		time.Sleep(delay)
+	}
+
+	if len(scheduleRunLogFile) > 0 {
+		logFile, err := os.OpenFile(scheduleRunLogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644) // #nosec G302 G304 -- Log file is supplied by user
+		if err != nil {
+			return fmt.Errorf("unable to open log file %s: %s", scheduleRunLogFile, err)
+		}
+		//nolint:errcheck
+		defer logFile.Close()
+		w = logFile
+	}
+
+	//nolint:errcheck
+	fmt.Fprintf(w, "=== %s %s\n", time.Now().Format(time.RFC3339), scheduleRunJob)
+	for _, args := range commands {
+		args = append([]string{fmt.Sprintf("--user=%t", configuration.User)}, args...)
+		cmd := exec.Command(getUnigetExecutable(), args...) // #nosec G204 -- Arguments are fixed
+		cmd.Stdout = w
+		cmd.Stderr = w
+		err = cmd.Run()
+		if err != nil {
+			return fmt.Errorf("%s failed: %s", strings.Join(args, " "), err)
+		}
+	}
+
+	return nil
+}
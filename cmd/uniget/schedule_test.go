@@ -0,0 +1,42 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRemoveManagedCronLines(t *testing.T) {
+	lines := []string{
+		"0 1 * * * backup.sh",
+		"30 0 * * * uniget --user=false upgrade --auto-update",
+		"0 0 * * * /usr/local/bin/uniget --user=false schedule run --job self-upgrade # uniget-managed:self-upgrade",
+		"0 2 * * * my-uniget-report.sh",
+	}
+
+	expected := []string{
+		"0 1 * * * backup.sh",
+		"0 2 * * * my-uniget-report.sh",
+	}
+	result := removeManagedCronLines(lines)
+	if !slices.Equal(result, expected) {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+}
+
+func TestScheduleTimerUnit(t *testing.T) {
+	previousDelay := scheduleRandomizedDelay
+	defer func() { scheduleRandomizedDelay = previousDelay }()
+	scheduleRandomizedDelay = 30 * time.Minute
+
+	unit := getTimerUnit(scheduleJob{Name: scheduleJobNotify, Calendar: "daily"})
+	if !strings.HasPrefix(unit, scheduleUnitMarker) {
+		t.Errorf("expected unit to start with marker, got:\n%s", unit)
+	}
+	for _, expected := range []string{"OnCalendar=daily", "RandomizedDelaySec=1800"} {
+		if !strings.Contains(unit, expected) {
+			t.Errorf("expected unit to contain %q, got:\n%s", expected, unit)
+		}
+	}
+}
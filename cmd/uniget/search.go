@@ -3,11 +3,16 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
 
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 	"gitlab.com/uniget-org/cli/internal/constants"
 	"gitlab.com/uniget-org/cli/pkg/logging"
+	myos "gitlab.com/uniget-org/cli/pkg/os"
+	"gitlab.com/uniget-org/cli/pkg/tool"
 )
 
 var (
@@ -20,6 +25,8 @@ var (
 	searchOnlyInDeps        bool
 	searchNotInDeps         bool
 	searchOutputFormat      string
+	searchLimit             int
+	searchQuery             string
 )
 
 func initSearchCmd() {
@@ -34,6 +41,8 @@ func initSearchCmd() {
 	searchCmd.Flags().BoolVar(&searchOnlyInDeps, "only-deps", false, "Search only in dependencies")
 	searchCmd.Flags().BoolVar(&searchNotInDeps, "no-deps", false, "Do not search in dependencies")
 	searchCmd.Flags().StringVar(&searchOutputFormat, "output", "table", "Output format (table, name, json)")
+	searchCmd.Flags().IntVar(&searchLimit, "limit", 0, "Show only the best matching tools (0 for all)")
+	addQueryFlag(searchCmd, &searchQuery, "Show only tools matching the query")
 
 	searchCmd.MarkFlagsMutuallyExclusive("only-names", "no-names")
 	searchCmd.MarkFlagsMutuallyExclusive("only-description", "no-description")
@@ -48,10 +57,15 @@ var searchCmd = &cobra.Command{
 		"find",
 		"f",
 	},
-	Short:   "Search for tools",
-	Long:    constants.Header + "\nSearch for tools",
+	Short: "Search for tools",
+	Long: constants.Header + "\nSearch for tools" + `
+
+Results match all terms and are ranked by relevance: exact names rank over
+name prefixes over tags over descriptions. Terms tolerate typos.
+
+Without terms, all tools matching --query are shown unranked.`,
 	GroupID: "tool",
-	Args:    cobra.MinimumNArgs(1),
+	Args:    searchArgs,
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
 		if searchOutputFormat != "table" && searchOutputFormat != "name" && searchOutputFormat != "json" {
 			return fmt.Errorf("error: output format %s not supported", searchOutputFormat)
@@ -66,30 +80,52 @@ var searchCmd = &cobra.Command{
 			return fmt.Errorf("error: Can only process one of only-names, only-description, only-tags and only-deps at the same time")
 		}
 
-		results := tools
-		for _, term := range args {
-			results = results.Find(
-				term,
-				!searchNotInName && !searchOnlyInDescription && !searchOnlyInTags && !searchOnlyInDeps,
-				!searchNotInDescription && !searchOnlyInName && !searchOnlyInTags && !searchOnlyInDeps,
-				!searchNotInTags && !searchOnlyInName && !searchOnlyInDescription && !searchOnlyInDeps,
-				!searchNotInDeps && !searchOnlyInName && !searchOnlyInDescription && !searchOnlyInTags,
-			)
+		candidates := tools
+		if len(searchQuery) > 0 {
+			candidates, err = selectTools(tools, searchQuery)
+			if err != nil {
+				return err
+			}
+		}
+
+		var results []tool.SearchResult
+		if len(args) == 0 {
+			for i := range candidates.Tools {
+				results = append(results, tool.SearchResult{Tool: &candidates.Tools[i]})
+			}
+			if len(results) == 0 {
+				logging.Info.Printfln("No tools found for query <%s>", searchQuery)
+				return nil
+			}
+		} else {
+			results = candidates.Search(args, tool.SearchFields{
+				Name:         !searchNotInName && !searchOnlyInDescription && !searchOnlyInTags && !searchOnlyInDeps,
+				Description:  !searchNotInDescription && !searchOnlyInName && !searchOnlyInTags && !searchOnlyInDeps,
+				Tags:         !searchNotInTags && !searchOnlyInName && !searchOnlyInDescription && !searchOnlyInDeps,
+				Dependencies: !searchNotInDeps && !searchOnlyInName && !searchOnlyInDescription && !searchOnlyInTags,
+			})
+			if len(results) == 0 {
+				logging.Info.Printfln("No tools found for term <%s>", strings.Join(args, " "))
+				return nil
+			}
 		}
-		if len(results.Tools) == 0 {
-			logging.Info.Printfln("No tools found for term <%s>", strings.Join(args, " "))
-			return nil
+		if searchLimit > 0 && len(results) > searchLimit {
+			results = results[:searchLimit]
 		}
 
 		switch searchOutputFormat {
 		case "table":
-			results.List(cmd.OutOrStdout())
+			printSearchResults(cmd.OutOrStdout(), results, myos.IsTty())
 		case "name":
-			for _, tool := range results.Tools {
-				fmt.Println(tool.Name)
+			for _, result := range results {
+				fmt.Println(result.Tool.Name)
 			}
 		case "json":
-			data, err := json.Marshal(results)
+			var matchingTools tool.Tools
+			for _, result := range results {
+				matchingTools.Tools = append(matchingTools.Tools, *result.Tool)
+			}
+			data, err := json.Marshal(matchingTools)
 			if err != nil {
 				return fmt.Errorf("failed to marshal to json: %s", err)
 			}
@@ -99,3 +135,49 @@ var searchCmd = &cobra.Command{
 		return nil
 	},
 }
+
+// searchArgs requires at least one term unless tools are selected by a query
+func searchArgs(cmd *cobra.Command, args []string) error {
+	if len(searchQuery) > 0 {
+		return nil
+	}
+	return cobra.MinimumNArgs(1)(cmd, args)
+}
+
+func printSearchResults(w io.Writer, results []tool.SearchResult, highlight bool) {
+	mark := func(text string) string {
+		return text
+	}
+	if highlight {
+		style := pterm.NewStyle(pterm.FgYellow, pterm.Bold)
+		mark = func(text string) string {
+			return style.Sprint(text)
+		}
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{
+			Number:   4,
+			WidthMax: 80,
+		},
+	})
+	t.Style().Options.DrawBorder = false
+	t.Style().Options.SeparateColumns = false
+	t.Style().Options.SeparateFooter = false
+	t.Style().Options.SeparateHeader = false
+	t.Style().Options.SeparateRows = false
+
+	t.AppendHeader(table.Row{"#", "Name", "Version", "Description"})
+	for index, result := range results {
+		t.AppendRow(table.Row{
+			index + 1,
+			result.Highlight(tool.FieldName, result.Tool.Name, mark),
+			result.Tool.Version,
+			result.Highlight(tool.FieldDescription, result.Tool.Description, mark),
+		})
+	}
+
+	t.Render()
+}
@@ -0,0 +1,24 @@
+package main
+
+import "testing"
+
+func TestSearchArgs(t *testing.T) {
+	tests := []struct {
+		query   string
+		args    []string
+		wantErr bool
+	}{
+		{query: "", args: []string{}, wantErr: true},
+		{query: "", args: []string{"kubernetes"}, wantErr: false},
+		{query: "tag:kubernetes", args: []string{}, wantErr: false},
+		{query: "tag:kubernetes", args: []string{"helm"}, wantErr: false},
+	}
+	for _, tt := range tests {
+		searchQuery = tt.query
+		err := searchArgs(searchCmd, tt.args)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("expected error %v for query %q and args %v, got %v", tt.wantErr, tt.query, tt.args, err)
+		}
+	}
+	searchQuery = ""
+}
@@ -1,6 +1,9 @@
 package main
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
@@ -8,6 +11,8 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
+	"time"
 	"unicode"
 
 	"github.com/google/safearchive/tar"
@@ -17,8 +22,14 @@ import (
 	"gitlab.com/uniget-org/cli/internal/constants"
 	"gitlab.com/uniget-org/cli/pkg/archive"
 	"gitlab.com/uniget-org/cli/pkg/containers"
+	"gitlab.com/uniget-org/cli/pkg/events"
+	"gitlab.com/uniget-org/cli/pkg/history"
 	"gitlab.com/uniget-org/cli/pkg/logging"
+	myos "gitlab.com/uniget-org/cli/pkg/os"
+	"gitlab.com/uniget-org/cli/pkg/security"
+	"gitlab.com/uniget-org/cli/pkg/semver"
 	"gitlab.com/uniget-org/cli/pkg/tool"
+	"gitlab.com/uniget-org/cli/pkg/transport"
 )
 
 type selfUpgradeSourceEnum string
@@ -52,14 +63,19 @@ func selfUpgradeSourceCompletion(cmd *cobra.Command, args []string, toComplete s
 
 var selfUpgradeVersion = ""
 var selfUpgradePath = ""
-var selfUpgradeSource selfUpgradeSourceEnum = selfUpgradeSourceUniget
+var selfUpgradeSource selfUpgradeSourceEnum = selfUpgradeSourceRelease
+var selfUpgradeRollback = false
+var selfUpgradeSkipVerify = false
 
 func initSelfUpgradeCmd() {
 	var err error
 
 	selfUpgradeCmd.Flags().StringVarP(&selfUpgradeVersion, "version", "v", selfUpgradeVersion, "Version to upgrade to")
 	selfUpgradeCmd.Flags().StringVar(&selfUpgradePath, "path", selfUpgradePath, "Binary to upgrade")
-	selfUpgradeCmd.Flags().VarP(&selfUpgradeSource, "source", "s", "Source to upgrade from. Allowed uniget, release")
+	selfUpgradeCmd.Flags().VarP(&selfUpgradeSource, "source", "s", "Source to upgrade from. Allowed release, uniget (requires --insecure-skip-verify)")
+	selfUpgradeCmd.Flags().BoolVar(&selfUpgradeRollback, "rollback", selfUpgradeRollback, "Restore the version before the last self-upgrade")
+	selfUpgradeCmd.Flags().BoolVar(&selfUpgradeSkipVerify, "insecure-skip-verify", selfUpgradeSkipVerify, "Do not verify the signature of release assets")
+	selfUpgradeCmd.MarkFlagsMutuallyExclusive("rollback", "version")
 
 	err = selfUpgradeCmd.Flags().MarkHidden("version")
 	if err != nil {
@@ -73,6 +89,10 @@ func initSelfUpgradeCmd() {
 	if err != nil {
 		logging.Error.Printfln("Failed to mark source flag as hidden: %s", err)
 	}
+	err = selfUpgradeCmd.Flags().MarkHidden("insecure-skip-verify")
+	if err != nil {
+		logging.Error.Printfln("Failed to mark insecure-skip-verify flag as hidden: %s", err)
+	}
 
 	err = selfUpgradeCmd.RegisterFlagCompletionFunc("source", selfUpgradeSourceCompletion)
 	if err != nil {
@@ -86,10 +106,18 @@ var selfUpgradeCmd = &cobra.Command{
 	Use:     "self-upgrade",
 	Aliases: []string{},
 	Short:   "Self upgrade " + constants.ProjectName,
-	Long:    constants.Header + "\nUpgrade " + constants.ProjectName + " to latest version",
+	Long: constants.Header + "\nUpgrade " + constants.ProjectName + " to latest version" + `
+
+The new binary is downloaded next to the current binary, verified and tested
+before it replaces the current binary. The previous binary is kept and can be
+restored using --rollback.`,
 	GroupID: "config",
 	Args:    cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		if selfUpgradeRollback {
+			return rollbackSelfUpgrade()
+		}
+
 		unigetTool, err := tools.GetByName("uniget")
 		if err != nil {
 			return fmt.Errorf("failed to get uniget tool: %s", err)
@@ -97,100 +125,286 @@ var selfUpgradeCmd = &cobra.Command{
 
 		requestedVersion := selfUpgradeVersion
 		if requestedVersion == "" {
-			requestedVersion = unigetTool.Version
-		} else {
-			unigetTool.Version = requestedVersion
+			channel, resolvedVersion, err := resolveUnigetVersion(cmd.Context(), unigetTool)
+			if err != nil {
+				return fmt.Errorf("failed to resolve version for channel %s: %s", configuration.Channel, err)
+			}
+			if !channel.ShouldUpgrade(version, resolvedVersion) {
+				logging.Info.Printfln("uniget %s is already installed (channel %s resolves to %s)", version, channel.Spec, resolvedVersion)
+				return nil
+			}
+			requestedVersion = resolvedVersion
 		}
+		unigetTool.Version = requestedVersion
 
 		if requestedVersion == version {
 			logging.Info.Printfln("uniget %s is already installed", requestedVersion)
 			return nil
 		}
 
-		selfExe := filepath.Base(os.Args[0])
-		if selfExe == "." {
-			return fmt.Errorf("failed to get base name for %s", os.Args[0])
+		// Image layers cannot be verified because the metadata does not
+		// contain their digests
+		if selfUpgradeSource == selfUpgradeSourceUniget && !selfUpgradeSkipVerify {
+			return fmt.Errorf("the binary in the uniget image cannot be verified; use --source release or --insecure-skip-verify")
 		}
-		if selfExe != "uniget" {
-			logging.Warning.Printf("Binary must be called uniget but is %s\n", selfExe)
-			return nil
-		}
-		logging.Tracef("Self upgrade binary: %s", selfExe)
 
-		if selfUpgradePath == "" {
-			path, err := exec.LookPath(selfExe)
-			if err != nil {
-				logging.Error.Printfln("Failed to find %s in PATH", selfUpgradePath)
-				return fmt.Errorf("failed to find %s in PATH: %s", selfUpgradePath, err)
-			}
-			logging.Debugf("%s is available at %s\n", selfUpgradePath, path)
-			selfUpgradePath = filepath.Dir(path)
+		selfPath, err := getSelfUpgradeTarget()
+		if err != nil {
+			return err
+		}
+		if selfPath == "" {
+			return nil
 		}
-		logging.Tracef("Self upgrade path: %s", selfUpgradePath)
 
 		logging.Info.Printfln("Installing version %s", requestedVersion)
 
-		logging.Tracef("Changing directory to %s", selfUpgradePath)
-		err = os.Chdir(selfUpgradePath)
+		// The new binary is created in the same directory so that it can
+		// be renamed atomically
+		newBinary, err := os.CreateTemp(filepath.Dir(selfPath), "."+constants.ProjectName+"-*")
 		if err != nil {
-			return fmt.Errorf("error changing directory to %s: %s", selfUpgradePath, err)
+			return fmt.Errorf("failed to create temporary file: %s", err)
 		}
-
-		_, err = os.Stat(selfExe)
-		if err == nil {
-			logging.Tracef("Removing %s", selfUpgradePath)
-			err = os.Remove(selfExe)
-			if err != nil {
-				return fmt.Errorf("failed to remove %s: %s", selfExe, err)
+		defer func() {
+			err := os.Remove(newBinary.Name())
+			if err != nil && !os.IsNotExist(err) {
+				logging.Warning.Printfln("Failed to remove %s: %s", newBinary.Name(), err)
 			}
-		}
+		}()
 
 		switch selfUpgradeSource {
 		case "uniget":
-			err = selfUpdateFromUniget(unigetTool)
+			logging.Warning.Printfln("Skipping verification of the binary from the uniget image")
+			err = selfUpdateFromUniget(cmd.Context(), unigetTool, newBinary)
 			if err != nil {
+				_ = newBinary.Close()
 				return fmt.Errorf("failed to upgrade from uniget: %s", err)
 			}
 		case "release":
-			err = selfUpdateFromRelease(unigetTool)
+			err = selfUpdateFromRelease(cmd.Context(), unigetTool, newBinary)
 			if err != nil {
+				_ = newBinary.Close()
 				return fmt.Errorf("failed to upgrade from release: %s", err)
 			}
 		default:
+			_ = newBinary.Close()
 			return fmt.Errorf("invalid source %s; must be either uniget or release", selfUpgradeSource)
 		}
+		err = newBinary.Close()
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %s", newBinary.Name(), err)
+		}
+		err = os.Chmod(newBinary.Name(), 0755) // #nosec G302 -- Binary must be executable
+		if err != nil {
+			return fmt.Errorf("failed to make %s executable: %s", newBinary.Name(), err)
+		}
 
+		err = smokeTestBinary(newBinary.Name(), requestedVersion)
+		if err != nil {
+			return fmt.Errorf("new binary failed to run: %s", err)
+		}
+
+		err = replaceBinary(selfPath, newBinary.Name())
+		if err != nil {
+			return fmt.Errorf("failed to replace %s: %s", selfPath, err)
+		}
+
+		emitter.Emit(events.Event{
+			Type:       events.TypeSucceeded,
+			Tool:       constants.ProjectName,
+			Version:    requestedVersion,
+			OldVersion: version,
+			Message:    fmt.Sprintf("Upgraded %s from %s to %s", constants.ProjectName, version, requestedVersion),
+		})
 		return nil
 	},
 }
 
-func selfUpdateFromUniget(unigetTool *tool.Tool) (err error) {
+// resolveUnigetVersion returns the version of uniget selected by the
+// configured channel. The latest channel follows the metadata while all
+// other channels are evaluated against the tags in the registry.
+func resolveUnigetVersion(ctx context.Context, unigetTool *tool.Tool) (semver.Channel, string, error) {
+	channel, err := semver.ParseChannel(configuration.Channel)
+	if err != nil {
+		return channel, "", err
+	}
+	if channel.Kind == semver.ChannelLatest {
+		return channel, unigetTool.Version, nil
+	}
+
 	registries, repositories := unigetTool.GetSourcesWithFallback(constants.Registry, constants.ImageRepository)
-	ref, err := containers.FindToolRef(registries, repositories, unigetTool.Name, unigetTool.Version)
+	toolRef, err := containers.FindToolRef(ctx, registries, repositories, unigetTool.Name, unigetTool.Version)
+	if err != nil {
+		return channel, "", fmt.Errorf("unable to find tool ref: %s", err)
+	}
+	tags, err := containers.GetImageTags(ctx, toolRef)
+	if err != nil {
+		return channel, "", fmt.Errorf("failed to get image tags: %s", err)
+	}
+	logging.Debugf("Resolving channel %s against %d tags", channel.Spec, len(tags))
+
+	resolvedVersion, err := channel.Resolve(tags)
+	if err != nil {
+		return channel, "", err
+	}
+	return channel, resolvedVersion, nil
+}
+
+// getSelfUpgradeTarget returns the path of the binary to replace or an
+// empty string if the binary must not be replaced
+func getSelfUpgradeTarget() (string, error) {
+	selfExe := filepath.Base(os.Args[0])
+	if selfExe == "." {
+		return "", fmt.Errorf("failed to get base name for %s", os.Args[0])
+	}
+	if selfExe != "uniget" {
+		logging.Warning.Printf("Binary must be called uniget but is %s\n", selfExe)
+		return "", nil
+	}
+	logging.Tracef("Self upgrade binary: %s", selfExe)
+
+	if selfUpgradePath == "" {
+		path, err := exec.LookPath(selfExe)
+		if err != nil {
+			logging.Error.Printfln("Failed to find %s in PATH", selfExe)
+			return "", fmt.Errorf("failed to find %s in PATH: %s", selfExe, err)
+		}
+		logging.Debugf("%s is available at %s\n", selfExe, path)
+		selfUpgradePath = filepath.Dir(path)
+	}
+	logging.Tracef("Self upgrade path: %s", selfUpgradePath)
+
+	return filepath.Join(selfUpgradePath, selfExe), nil
+}
+
+func getSelfUpgradeBackup(selfPath string) string {
+	return filepath.Join(filepath.Dir(selfPath), "."+filepath.Base(selfPath)+".previous")
+}
+
+// smokeTestBinary makes sure that the binary runs on this machine and
+// reports the expected version
+func smokeTestBinary(path string, expectedVersion string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, path, "--version").CombinedOutput() // #nosec G204 -- Binary was just downloaded
+	if err != nil {
+		return fmt.Errorf("%s --version failed: %s: %s", path, err, strings.TrimSpace(string(output)))
+	}
+	if len(expectedVersion) > 0 && !strings.Contains(string(output), expectedVersion) {
+		return fmt.Errorf("expected version %s but got %s", expectedVersion, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// replaceBinary keeps the current binary as a backup and atomically
+// replaces it with the new binary
+func replaceBinary(selfPath string, newPath string) error {
+	backupPath := getSelfUpgradeBackup(selfPath)
+
+	if myos.FileExists(selfPath) {
+		err := os.Remove(backupPath)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove previous backup %s: %s", backupPath, err)
+		}
+
+		err = os.Link(selfPath, backupPath)
+		if err != nil {
+			logging.Debugf("Failed to link %s to %s: %s. Copying instead.", selfPath, backupPath, err)
+			err = myos.CopyFile(selfPath, backupPath)
+			if err != nil {
+				return fmt.Errorf("failed to keep backup of %s: %s", selfPath, err)
+			}
+			err = os.Chmod(backupPath, 0755) // #nosec G302 -- Binary must be executable
+			if err != nil {
+				return fmt.Errorf("failed to make backup executable: %s", err)
+			}
+		}
+	}
+
+	err := os.Rename(newPath, selfPath)
+	if err != nil {
+		return fmt.Errorf("failed to rename %s to %s: %s", newPath, selfPath, err)
+	}
+
+	return nil
+}
+
+func rollbackSelfUpgrade() error {
+	selfPath, err := getSelfUpgradeTarget()
+	if err != nil {
+		return err
+	}
+	if selfPath == "" {
+		return nil
+	}
+
+	backupPath := getSelfUpgradeBackup(selfPath)
+	if !myos.FileExists(backupPath) {
+		return fmt.Errorf("no previous version found at %s", backupPath)
+	}
+
+	err = smokeTestBinary(backupPath, "")
+	if err != nil {
+		return fmt.Errorf("previous binary failed to run: %s", err)
+	}
+
+	// Swap both binaries so that the rollback can be undone
+	newBackupPath := backupPath + ".new"
+	err = os.Link(selfPath, newBackupPath)
+	if err != nil {
+		logging.Debugf("Failed to keep current binary: %s", err)
+	}
+	err = os.Rename(backupPath, selfPath)
+	if err != nil {
+		return fmt.Errorf("failed to restore %s: %s", selfPath, err)
+	}
+	if myos.FileExists(newBackupPath) {
+		err = os.Rename(newBackupPath, backupPath)
+		if err != nil {
+			logging.Warning.Printfln("Failed to keep current binary as %s: %s", backupPath, err)
+		}
+	}
+
+	emitter.Emit(events.Event{
+		Type:       events.TypeSucceeded,
+		Tool:       constants.ProjectName,
+		OldVersion: version,
+		Reason:     history.ActionRollback,
+		Message:    fmt.Sprintf("Restored previous version of %s", constants.ProjectName),
+	})
+	return nil
+}
+
+func selfUpdateFromUniget(ctx context.Context, unigetTool *tool.Tool, w io.Writer) (err error) {
+	registries, repositories := unigetTool.GetSourcesWithFallback(constants.Registry, constants.ImageRepository)
+	ref, err := containers.FindToolRef(ctx, registries, repositories, unigetTool.Name, unigetTool.Version)
 	if err != nil {
 		return fmt.Errorf("error finding tool %s:%s: %s", unigetTool.Name, unigetTool.Version, err)
 	}
 	logging.Debugf("Getting image %s", ref)
+	found := false
 	unpackUnigetBinary := func(reader *tar.Reader, header *tar.Header) error {
 		logging.Tracef("Processing tar item: %s", header.Name)
 		if header.Typeflag == tar.TypeReg && header.Name == "bin/uniget" {
 			logging.Debugf("Extracting %s", header.Name)
 
-			err = archive.ExtractFileFromTar(selfUpgradePath, "uniget", reader, header)
+			_, err := io.Copy(w, reader) // #nosec G110 -- Only used with --insecure-skip-verify and smoke tested before replacing the binary
 			if err != nil {
 				return fmt.Errorf("failed to extract %s from tar: %s", header.Name, err)
 			}
+			found = true
 		}
 
 		return nil
 	}
 
 	progressReader := common.CreateProgressReader("Downloading", configuration.Debug || configuration.Trace)
-	err = toolCache.Get(ref, progressReader, func(reader io.ReadCloser) error { return nil })
+	err = toolCache.Get(ctx, ref, progressReader, func(reader io.ReadCloser) error { return nil })
 	if err != nil {
 		return fmt.Errorf("unable to get image: %s", err)
 	}
-	err = toolCache.Get(ref, progressReader, func(reader io.ReadCloser) error {
+	err = toolCache.Get(ctx, ref, progressReader, func(reader io.ReadCloser) error {
 		err := archive.ProcessTarContents(reader, unpackUnigetBinary)
 		if err != nil {
 			return fmt.Errorf("unable to process tar contents: %s", err)
@@ -201,37 +415,63 @@ func selfUpdateFromUniget(unigetTool *tool.Tool) (err error) {
 	if err != nil {
 		return fmt.Errorf("unable to upgrade from image: %s", err)
 	}
+	if !found {
+		return fmt.Errorf("image does not contain bin/uniget")
+	}
 
 	return nil
 }
 
-func selfUpdateFromRelease(unigetTool *tool.Tool) (err error) {
-	url := fmt.Sprintf("https://gitlab.com/%s/cli/-/releases/v%s/downloads/uniget_%s_%s.tar.gz", constants.Organization, unigetTool.Version, string(unicode.ToUpper(rune(runtime.GOOS[0])))+runtime.GOOS[1:], configuration.Arch)
-	logging.Debugf("Downloading from %s", url)
+func selfUpdateFromRelease(ctx context.Context, unigetTool *tool.Tool, w io.Writer) (err error) {
+	baseURL := fmt.Sprintf("https://gitlab.com/%s/cli/-/releases/v%s/downloads", constants.Organization, unigetTool.Version)
+	assetName := fmt.Sprintf("uniget_%s_%s.tar.gz", string(unicode.ToUpper(rune(runtime.GOOS[0])))+runtime.GOOS[1:], configuration.Arch)
 
-	resp, err := downloadReleaseAsset(url)
+	downloadDir, err := os.MkdirTemp("", constants.ProjectName+"-release-*")
 	if err != nil {
-		return fmt.Errorf("failed to download %s: %s", url, err)
+		return fmt.Errorf("failed to create temporary directory: %s", err)
 	}
 	defer func() {
-		err := resp.Body.Close()
+		err := os.RemoveAll(downloadDir)
 		if err != nil {
-			logging.Warning.Printfln("failed to close response body: %s", err)
+			logging.Warning.Printfln("failed to remove %s: %s", downloadDir, err)
 		}
 	}()
 
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	assetPath := filepath.Join(downloadDir, assetName)
+	found, err := downloadReleaseFile(ctx, baseURL+"/"+assetName, assetPath)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("release asset %s not found", assetName)
 	}
 
-	err = archive.Gunzip(resp.Body, func(reader io.Reader) error {
+	if selfUpgradeSkipVerify {
+		logging.Warning.Printfln("Skipping verification of %s", assetName)
+	} else {
+		err = verifyReleaseAsset(ctx, baseURL, downloadDir, assetName)
+		if err != nil {
+			return fmt.Errorf("failed to verify %s: %s", assetName, err)
+		}
+	}
+
+	asset, err := os.Open(assetPath) // #nosec G304 -- Path is built from temporary directory
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %s", assetPath, err)
+	}
+	//nolint:errcheck
+	defer asset.Close()
+
+	found = false
+	err = archive.Gunzip(asset, func(reader io.Reader) error {
 		return archive.ProcessTarContents(io.NopCloser(reader), func(tar *tar.Reader, header *tar.Header) error {
 			if header.Name == "uniget" {
 				logging.Debugf("Extracting %s", header.Name)
-				err := archive.CallbackExtractTarItem(tar, header)
+				_, err := io.Copy(w, tar) // #nosec G110 -- Archive was verified
 				if err != nil {
 					return fmt.Errorf("failed to extract %s: %s", header.Name, err)
 				}
+				found = true
 			}
 
 			return nil
@@ -240,22 +480,141 @@ func selfUpdateFromRelease(unigetTool *tool.Tool) (err error) {
 	if err != nil {
 		return fmt.Errorf("failed to extract tar.gz: %s", err)
 	}
+	if !found {
+		return fmt.Errorf("release asset does not contain uniget")
+	}
 
 	return nil
 }
 
-func downloadReleaseAsset(url string) (*http.Response, error) {
-	client := &http.Client{
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			return nil
-		},
+// verifyReleaseAsset checks the Sigstore bundle of the asset. Releases
+// without a bundle for the asset are verified using the signed checksums.
+func verifyReleaseAsset(ctx context.Context, baseURL string, downloadDir string, assetName string) error {
+	assetPath := filepath.Join(downloadDir, assetName)
+	bundlePath := assetPath + ".sigstore.json"
+	found, err := downloadReleaseFile(ctx, baseURL+"/"+assetName+".sigstore.json", bundlePath)
+	if err != nil {
+		return err
+	}
+	if found {
+		logging.Debugf("Verifying Sigstore bundle of %s", assetName)
+		_, err = security.VerifySigstoreBundle(assetPath, bundlePath, constants.ReleaseOIDCIssuer, "", "", constants.ReleaseSANRegex)
+		return err
+	}
+
+	checksumsName := constants.ProjectName + "_checksums.txt"
+	checksumsPath := filepath.Join(downloadDir, checksumsName)
+	found, err = downloadReleaseFile(ctx, baseURL+"/"+checksumsName, checksumsPath)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("neither Sigstore bundle nor checksums found")
+	}
+	found, err = downloadReleaseFile(ctx, baseURL+"/"+checksumsName+".sigstore.json", checksumsPath+".sigstore.json")
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("checksums are not signed")
+	}
+	logging.Debugf("Verifying Sigstore bundle of %s", checksumsName)
+	_, err = security.VerifySigstoreBundle(checksumsPath, checksumsPath+".sigstore.json", constants.ReleaseOIDCIssuer, "", "", constants.ReleaseSANRegex)
+	if err != nil {
+		return err
+	}
+
+	checksums, err := os.ReadFile(checksumsPath) // #nosec G304 -- Path is built from temporary directory
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %s", checksumsPath, err)
+	}
+	expectedChecksum, err := findChecksum(checksums, assetName)
+	if err != nil {
+		return err
+	}
+	checksum, err := sha256File(assetPath)
+	if err != nil {
+		return err
+	}
+	if checksum != expectedChecksum {
+		return fmt.Errorf("checksum mismatch: expected %s but got %s", expectedChecksum, checksum)
+	}
+
+	return nil
+}
+
+func findChecksum(checksums []byte, name string) (string, error) {
+	for line := range strings.SplitSeq(string(checksums), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return fields[0], nil
+		}
+	}
+	return "", fmt.Errorf("no checksum found for %s", name)
+}
+
+func sha256File(path string) (string, error) {
+	file, err := os.Open(path) // #nosec G304 -- Path is built from temporary directory
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %s", path, err)
+	}
+	//nolint:errcheck
+	defer file.Close()
+
+	h := sha256.New()
+	_, err = io.Copy(h, file)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash %s: %s", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// downloadReleaseFile returns false if the file does not exist
+func downloadReleaseFile(ctx context.Context, url string, path string) (bool, error) {
+	logging.Debugf("Downloading from %s", url)
+	resp, err := downloadReleaseAsset(ctx, url)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		err := resp.Body.Close()
+		if err != nil {
+			logging.Warning.Printfln("failed to close response body: %s", err)
+		}
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+
+	file, err := os.Create(path) // #nosec G304 -- Path is built from temporary directory
+	if err != nil {
+		return false, fmt.Errorf("failed to create %s: %s", path, err)
+	}
+	//nolint:errcheck
+	defer file.Close()
+
+	_, err = io.Copy(file, resp.Body)
+	if err != nil {
+		return false, fmt.Errorf("failed to download %s: %s", url, err)
+	}
+
+	return true, nil
+}
+
+func downloadReleaseAsset(ctx context.Context, url string) (*http.Response, error) {
+	client := transport.Client()
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return nil
 	}
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %s", err)
 	}
 	req.Header.Set("Accept", "application/octet-stream")
-	req.Header.Set("User-Agent", fmt.Sprintf("%s/%s", constants.ProjectName, version))
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to download %s: %s", url, err)
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindChecksum(t *testing.T) {
+	checksums := []byte(`1111  uniget_Linux_x86_64.tar.gz
+2222  uniget_Linux_aarch64.tar.gz
+3333 *uniget_Darwin_aarch64.tar.gz
+`)
+
+	tt := []struct {
+		name     string
+		expected string
+		fails    bool
+	}{
+		{name: "uniget_Linux_aarch64.tar.gz", expected: "2222"},
+		{name: "uniget_Darwin_aarch64.tar.gz", expected: "3333"},
+		{name: "uniget_Linux_x86_64", fails: true},
+	}
+
+	for _, tc := range tt {
+		checksum, err := findChecksum(checksums, tc.name)
+		if tc.fails {
+			if err == nil {
+				t.Errorf("expected error for %s", tc.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error for %s: %s", tc.name, err)
+		}
+		if checksum != tc.expected {
+			t.Errorf("expected %s for %s but got %s", tc.expected, tc.name, checksum)
+		}
+	}
+}
+
+func TestReplaceBinary(t *testing.T) {
+	dir := t.TempDir()
+	selfPath := filepath.Join(dir, "uniget")
+	newPath := filepath.Join(dir, ".uniget-new")
+
+	err := os.WriteFile(selfPath, []byte("old"), 0755) // #nosec G306 -- Test binary
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(newPath, []byte("new"), 0755) // #nosec G306 -- Test binary
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = replaceBinary(selfPath, newPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for path, expected := range map[string]string{
+		selfPath:                       "new",
+		getSelfUpgradeBackup(selfPath): "old",
+	} {
+		data, err := os.ReadFile(path) // #nosec G304 -- Test file
+		if err != nil {
+			t.Fatalf("failed to read %s: %s", path, err)
+		}
+		if string(data) != expected {
+			t.Errorf("expected %s in %s but got %s", expected, path, data)
+		}
+	}
+	_, err = os.Stat(newPath)
+	if !os.IsNotExist(err) {
+		t.Errorf("expected %s to be renamed", newPath)
+	}
+}
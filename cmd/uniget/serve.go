@@ -0,0 +1,540 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"gitlab.com/uniget-org/cli/internal/constants"
+	"gitlab.com/uniget-org/cli/pkg/events"
+	"gitlab.com/uniget-org/cli/pkg/logging"
+	"gitlab.com/uniget-org/cli/pkg/tool"
+	"gitlab.com/uniget-org/cli/pkg/uniget"
+)
+
+var serveListen string
+var serveTokenFile string
+
+func initServeCmd() {
+	serveCmd.Flags().StringVar(&serveListen, "listen", "127.0.0.1:8080", "Address to listen on")
+	serveCmd.Flags().StringVar(&serveTokenFile, "token-file", "", "File containing the bearer token required for install and upgrade (or UNIGET_SERVE_TOKEN)")
+
+	rootCmd.AddCommand(serveCmd)
+}
+
+var serveCmd = &cobra.Command{
+	Use:     "serve",
+	Aliases: []string{},
+	Short:   "Serve a JSON API",
+	Long: constants.Header + "\nServe a JSON API for dashboards and automation" + `
+
+Endpoints:
+  GET  /api/v1/health               Version and metadata revision
+  GET  /api/v1/tools[?query=...]    Catalog with status of tools
+  GET  /api/v1/tools/{name}         Single tool with status
+  GET  /api/v1/installed            Installed tools
+  GET  /api/v1/upgradable           Outdated tools
+  GET  /api/v1/plan?tool=...        Planned tools for an installation
+  GET  /api/v1/plan?upgrade=true    Planned tools for an upgrade
+  POST /api/v1/install              Install tools (requires token)
+  POST /api/v1/upgrade              Upgrade tools (requires token)
+
+Install and upgrade are only enabled if a token is configured. Requests must
+send it as "Authorization: Bearer <token>". Both stream events as newline
+delimited JSON.` + "\n" + queryHelp,
+	GroupID: "helper",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		token, err := readServeToken(serveTokenFile)
+		if err != nil {
+			return err
+		}
+
+		host, _, err := net.SplitHostPort(serveListen)
+		if err != nil {
+			return fmt.Errorf("invalid listen address %s: %s", serveListen, err)
+		}
+		ip := net.ParseIP(host)
+		if ip == nil || !ip.IsLoopback() {
+			logging.Warning.Printfln("Listening on %s exposes the API beyond this host", serveListen)
+		}
+		if len(token) == 0 {
+			logging.Info.Println("Install and upgrade are disabled because no token is configured")
+		}
+
+		server := newAPIServer(cmd.Context(), token, textOutput(cmd))
+		emitter.EnableProgress()
+		emitter.AddSink(server.forward)
+
+		httpServer := &http.Server{
+			Addr:              serveListen,
+			Handler:           server.Handler(),
+			ReadHeaderTimeout: 10 * time.Second,
+			BaseContext: func(net.Listener) context.Context {
+				return cmd.Context()
+			},
+		}
+		logging.Info.Printfln("Serving API on http://%s/api/v1", serveListen)
+		err = listenAndServe(cmd.Context(), httpServer)
+		if err != nil {
+			return fmt.Errorf("failed to serve API: %s", err)
+		}
+
+		return nil
+	},
+}
+
+// listenAndServe serves until the context is done and shuts down the server
+// gracefully afterwards
+func listenAndServe(ctx context.Context, httpServer *http.Server) error {
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-ctx.Done()
+		logging.Info.Println("Shutting down server")
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+		err := httpServer.Shutdown(shutdownCtx)
+		if err != nil {
+			logging.Warning.Printfln("Unable to shut down server: %s", err)
+		}
+	}()
+
+	err := httpServer.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	// Wait for running requests to finish
+	<-stopped
+	return nil
+}
+
+func readServeToken(path string) (string, error) {
+	if len(path) == 0 {
+		return strings.TrimSpace(os.Getenv("UNIGET_SERVE_TOKEN")), nil
+	}
+
+	data, err := os.ReadFile(path) // #nosec G304 -- Location supplied by user
+	if err != nil {
+		return "", fmt.Errorf("unable to read token file: %s", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if len(token) == 0 {
+		return "", fmt.Errorf("token file %s is empty", path)
+	}
+	return token, nil
+}
+
+type apiToolStatus struct {
+	Installed        bool   `json:"installed"`
+	Upgradable       bool   `json:"upgradable"`
+	InstalledVersion string `json:"installed_version,omitempty"`
+}
+
+type apiTool struct {
+	Name                string         `json:"name"`
+	Version             string         `json:"version"`
+	Description         string         `json:"description"`
+	Homepage            string         `json:"homepage,omitempty"`
+	Repository          string         `json:"repository,omitempty"`
+	License             tool.License   `json:"license"`
+	Tags                []string       `json:"tags"`
+	RuntimeDependencies []string       `json:"runtime_dependencies,omitempty"`
+	Platforms           []string       `json:"platforms,omitempty"`
+	ConflictsWith       []string       `json:"conflicts_with,omitempty"`
+	Messages            tool.Messages  `json:"messages"`
+	Lifecycle           tool.Lifecycle `json:"lifecycle"`
+	Status              apiToolStatus  `json:"status"`
+}
+
+type apiPlannedTool struct {
+	apiTool
+	Requested bool   `json:"requested"`
+	Action    string `json:"action"`
+}
+
+type apiHealth struct {
+	Status   string `json:"status"`
+	Version  string `json:"version"`
+	Revision string `json:"revision"`
+	Tools    int    `json:"tools"`
+	Writable bool   `json:"writable"`
+}
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+// apiRequest selects tools for install and upgrade
+type apiRequest struct {
+	Tools            []string `json:"tools"`
+	Query            string   `json:"query"`
+	Reinstall        bool     `json:"reinstall"`
+	SkipDependencies bool     `json:"skip_dependencies"`
+}
+
+func newAPITool(t tool.Tool) apiTool {
+	status := apiToolStatus{
+		Installed:  t.IsInstalled(),
+		Upgradable: t.IsUpgradable(),
+	}
+	if status.Installed {
+		status.InstalledVersion = t.Status.MarkerFileVersion
+	}
+	return apiTool{
+		Name:                t.Name,
+		Version:             t.Version,
+		Description:         t.Description,
+		Homepage:            t.Homepage,
+		Repository:          t.Repository,
+		License:             t.License,
+		Tags:                t.Tags,
+		RuntimeDependencies: t.RuntimeDependencies,
+		Platforms:           t.Platforms,
+		ConflictsWith:       t.ConflictsWith,
+		Messages:            t.Messages,
+		Lifecycle:           t.Lifecycle,
+		Status:              status,
+	}
+}
+
+type apiServer struct {
+	// ctx is cancelled when the server shuts down
+	ctx    context.Context
+	token  string
+	writer io.Writer
+
+	// Only one installation runs at a time because installations change the
+	// installed files
+	busy sync.Mutex
+
+	streamMutex sync.Mutex
+	stream      func(events.Event)
+}
+
+func newAPIServer(ctx context.Context, token string, w io.Writer) *apiServer {
+	return &apiServer{
+		ctx:    ctx,
+		token:  token,
+		writer: w,
+	}
+}
+
+func (s *apiServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/health", s.handleHealth)
+	mux.HandleFunc("GET /api/v1/tools", s.handleTools)
+	mux.HandleFunc("GET /api/v1/tools/{name}", s.handleTool)
+	mux.HandleFunc("GET /api/v1/installed", s.handleInstalled)
+	mux.HandleFunc("GET /api/v1/upgradable", s.handleUpgradable)
+	mux.HandleFunc("GET /api/v1/plan", s.handlePlan)
+	mux.HandleFunc("POST /api/v1/install", s.authorize(s.handleInstall))
+	mux.HandleFunc("POST /api/v1/upgrade", s.authorize(s.handleUpgrade))
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(value)
+	if err != nil {
+		logging.Warning.Printfln("Unable to write response: %s", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, apiError{Error: err.Error()})
+}
+
+func (s *apiServer) authorize(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if len(s.token) == 0 {
+			writeError(w, http.StatusForbidden, fmt.Errorf("install and upgrade are disabled because no token is configured"))
+			return
+		}
+		if !hasBearerToken(r, s.token) {
+			writeError(w, http.StatusUnauthorized, fmt.Errorf("missing or invalid bearer token"))
+			return
+		}
+		next(w, r)
+	}
+}
+
+func hasBearerToken(r *http.Request, token string) bool {
+	received, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return found && subtle.ConstantTimeCompare([]byte(received), []byte(token)) == 1
+}
+
+// snapshot returns a copy of the catalog with the current status of all
+// tools so that requests do not modify the shared catalog
+func snapshot() (*tool.Tools, error) {
+	result := &tool.Tools{
+		Revision: tools.Revision,
+		Tools:    slices.Clone(tools.Tools),
+	}
+	for index := range result.Tools {
+		err := result.Tools[index].UpdateStatus(
+			configuration.Prefix,
+			configuration.Target,
+			configuration.GetCacheDirectory(),
+			configuration.Arch,
+			configuration.AltArch,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update status for tool %s: %s", result.Tools[index].Name, err)
+		}
+	}
+	return result, nil
+}
+
+func writeTools(w http.ResponseWriter, selected *tool.Tools, filter func(*tool.Tool) bool) {
+	result := make([]apiTool, 0, len(selected.Tools))
+	for index := range selected.Tools {
+		if filter == nil || filter(&selected.Tools[index]) {
+			result = append(result, newAPITool(selected.Tools[index]))
+		}
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *apiServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, apiHealth{
+		Status:   "ok",
+		Version:  version,
+		Revision: tools.Revision,
+		Tools:    len(tools.Tools),
+		Writable: len(s.token) > 0,
+	})
+}
+
+func (s *apiServer) handleTools(w http.ResponseWriter, r *http.Request) {
+	current, err := snapshot()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if expression := r.URL.Query().Get("query"); len(expression) > 0 {
+		current, err = selectTools(current, expression)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	writeTools(w, current, nil)
+}
+
+func (s *apiServer) handleTool(w http.ResponseWriter, r *http.Request) {
+	t, err := tools.GetByName(r.PathValue("name"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	current := *t
+	err = current.UpdateStatus(
+		configuration.Prefix,
+		configuration.Target,
+		configuration.GetCacheDirectory(),
+		configuration.Arch,
+		configuration.AltArch,
+	)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to update status for tool %s: %s", current.Name, err))
+		return
+	}
+	writeJSON(w, http.StatusOK, newAPITool(current))
+}
+
+func (s *apiServer) handleInstalled(w http.ResponseWriter, r *http.Request) {
+	current, err := snapshot()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeTools(w, current, (*tool.Tool).IsInstalled)
+}
+
+func (s *apiServer) handleUpgradable(w http.ResponseWriter, r *http.Request) {
+	current, err := snapshot()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeTools(w, current, (*tool.Tool).IsUpgradable)
+}
+
+// requestedTools resolves the tools selected by an API request. Upgrades
+// are limited to installed tools.
+func requestedTools(request apiRequest, upgrade bool) (*tool.Tools, error) {
+	current, err := snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	selected := current
+	if upgrade {
+		selected = &tool.Tools{Revision: current.Revision}
+		for _, t := range current.Tools {
+			if t.IsInstalled() {
+				selected.Tools = append(selected.Tools, t)
+			}
+		}
+	} else if len(request.Tools) == 0 && len(request.Query) == 0 {
+		return nil, fmt.Errorf("no tools requested")
+	}
+
+	if len(request.Tools) > 0 {
+		for _, name := range request.Tools {
+			if !selected.Contains(name) {
+				return nil, fmt.Errorf("tool %s not found", name)
+			}
+		}
+		selected = selected.GetByNames(request.Tools)
+	}
+	if len(request.Query) > 0 {
+		selected, err = selectTools(selected, request.Query)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return selected, nil
+}
+
+func (s *apiServer) handlePlan(w http.ResponseWriter, r *http.Request) {
+	request := apiRequest{
+		Tools: r.URL.Query()["tool"],
+		Query: r.URL.Query().Get("query"),
+	}
+	upgrade := r.URL.Query().Get("upgrade") == "true"
+	request.Reinstall = r.URL.Query().Get("reinstall") == "true"
+	request.SkipDependencies = r.URL.Query().Get("skip_dependencies") == "true"
+
+	selected, err := requestedTools(request, upgrade)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if !s.busy.TryLock() {
+		writeError(w, http.StatusConflict, fmt.Errorf("an installation is in progress"))
+		return
+	}
+	plan, err := newClient(s.writer).Plan(r.Context(), selected.GetNames(), uniget.InstallOptions{SkipDependencies: request.SkipDependencies})
+	s.busy.Unlock()
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newAPIPlan(plan.Tools, request))
+}
+
+func newAPIPlan(plannedTools tool.Tools, request apiRequest) []apiPlannedTool {
+	result := make([]apiPlannedTool, 0, len(plannedTools.Tools))
+	for _, t := range plannedTools.Tools {
+		action := "none"
+		switch {
+		case request.SkipDependencies && !t.Status.IsRequested:
+			action = "skip"
+		case !t.IsInstalled():
+			action = "install"
+		case !t.Status.VersionMatches:
+			action = "upgrade"
+		case request.Reinstall:
+			action = "reinstall"
+		}
+		result = append(result, apiPlannedTool{
+			apiTool:   newAPITool(t),
+			Requested: t.Status.IsRequested,
+			Action:    action,
+		})
+	}
+	return result
+}
+
+func (s *apiServer) handleInstall(w http.ResponseWriter, r *http.Request) {
+	s.run(w, r, false)
+}
+
+func (s *apiServer) handleUpgrade(w http.ResponseWriter, r *http.Request) {
+	s.run(w, r, true)
+}
+
+// forward passes events of the running installation to the client
+func (s *apiServer) forward(event events.Event) {
+	s.streamMutex.Lock()
+	defer s.streamMutex.Unlock()
+
+	if s.stream != nil {
+		s.stream(event)
+	}
+}
+
+func (s *apiServer) run(w http.ResponseWriter, r *http.Request, upgrade bool) {
+	var request apiRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %s", err))
+		return
+	}
+
+	selected, err := requestedTools(request, upgrade)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if !s.busy.TryLock() {
+		writeError(w, http.StatusConflict, fmt.Errorf("an installation is in progress"))
+		return
+	}
+	defer s.busy.Unlock()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+	send := func(event events.Event) {
+		err := encoder.Encode(event)
+		if err != nil {
+			logging.Debugf("Unable to stream event: %s", err)
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	s.streamMutex.Lock()
+	s.stream = send
+	s.streamMutex.Unlock()
+
+	// Installations continue when the client disconnects but are cancelled
+	// and cleaned up when the server shuts down
+	err = installTools(s.ctx, s.writer, selected, false, false, request.Reinstall, request.SkipDependencies, false)
+
+	s.streamMutex.Lock()
+	s.stream = nil
+	s.streamMutex.Unlock()
+
+	finished := events.Event{
+		Time: time.Now().UTC(),
+		Type: events.TypeFinished,
+	}
+	if err != nil {
+		finished.Error = err.Error()
+	}
+	send(finished)
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gitlab.com/uniget-org/cli/internal/config"
+	"gitlab.com/uniget-org/cli/pkg/tool"
+)
+
+// useTestCatalog replaces the catalog and configuration for the duration
+// of a test
+func useTestCatalog(t *testing.T) {
+	t.Helper()
+
+	previousTools := tools
+	previousConfiguration := configuration
+	t.Cleanup(func() {
+		tools = previousTools
+		configuration = previousConfiguration
+	})
+
+	configuration = config.NewDefaultConfig()
+	configuration.Prefix = t.TempDir()
+	tools = &tool.Tools{
+		Revision: "abc",
+		Tools: []tool.Tool{
+			{Name: "kubectl", Version: "1.30.0", Binary: "${target}/bin/kubectl", Tags: []string{"kubernetes"}},
+			{Name: "helm", Version: "3.15.0", Binary: "${target}/bin/helm", Tags: []string{"kubernetes"}, RuntimeDependencies: []string{"kubectl"}},
+			{Name: "jq", Version: "1.7.1", Binary: "${target}/bin/jq"},
+		},
+	}
+}
+
+func newTestAPIServer(t *testing.T, token string) *httptest.Server {
+	t.Helper()
+
+	useTestCatalog(t)
+	server := httptest.NewServer(newAPIServer(context.Background(), token, &strings.Builder{}).Handler())
+	t.Cleanup(server.Close)
+	return server
+}
+
+func getJSON(t *testing.T, url string, value any) int {
+	t.Helper()
+
+	response, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("request failed: %s", err)
+	}
+	//nolint:errcheck
+	defer response.Body.Close()
+
+	err = json.NewDecoder(response.Body).Decode(value)
+	if err != nil {
+		t.Fatalf("unable to decode response: %s", err)
+	}
+	return response.StatusCode
+}
+
+func TestServeRead(t *testing.T) {
+	server := newTestAPIServer(t, "")
+
+	var health apiHealth
+	if status := getJSON(t, server.URL+"/api/v1/health", &health); status != http.StatusOK {
+		t.Fatalf("unexpected status %d", status)
+	}
+	if health.Revision != "abc" || health.Tools != 3 || health.Writable {
+		t.Errorf("unexpected health: %+v", health)
+	}
+
+	var result []apiTool
+	getJSON(t, server.URL+"/api/v1/tools?query=tag:kubernetes", &result)
+	if len(result) != 2 || result[0].Name != "kubectl" || result[0].Status.Installed {
+		t.Errorf("unexpected tools: %+v", result)
+	}
+
+	var single apiTool
+	if status := getJSON(t, server.URL+"/api/v1/tools/jq", &single); status != http.StatusOK || single.Version != "1.7.1" {
+		t.Errorf("unexpected tool (%d): %+v", status, single)
+	}
+
+	var apiErr apiError
+	if status := getJSON(t, server.URL+"/api/v1/tools/missing", &apiErr); status != http.StatusNotFound {
+		t.Errorf("expected not found but got %d", status)
+	}
+	if status := getJSON(t, server.URL+"/api/v1/tools?query=tag:", &apiErr); status != http.StatusBadRequest {
+		t.Errorf("expected bad request but got %d", status)
+	}
+
+	getJSON(t, server.URL+"/api/v1/installed", &result)
+	if len(result) != 0 {
+		t.Errorf("expected no installed tools but got %d", len(result))
+	}
+}
+
+func TestServePlan(t *testing.T) {
+	server := newTestAPIServer(t, "")
+
+	var planned []apiPlannedTool
+	if status := getJSON(t, server.URL+"/api/v1/plan?tool=helm", &planned); status != http.StatusOK {
+		t.Fatalf("unexpected status %d", status)
+	}
+	if len(planned) != 2 {
+		t.Fatalf("expected 2 planned tools but got %d", len(planned))
+	}
+	if planned[0].Name != "kubectl" || planned[0].Requested || planned[0].Action != "install" {
+		t.Errorf("unexpected dependency: %+v", planned[0])
+	}
+	if planned[1].Name != "helm" || !planned[1].Requested {
+		t.Errorf("unexpected requested tool: %+v", planned[1])
+	}
+
+	var raw []map[string]any
+	getJSON(t, server.URL+"/api/v1/plan?tool=jq", &raw)
+	if _, ok := raw[0]["Status"]; ok {
+		t.Errorf("internal status must not be exposed: %v", raw[0])
+	}
+
+	var apiErr apiError
+	if status := getJSON(t, server.URL+"/api/v1/plan", &apiErr); status != http.StatusBadRequest {
+		t.Errorf("expected bad request but got %d", status)
+	}
+}
+
+func TestServeAuthorization(t *testing.T) {
+	tt := []struct {
+		name   string
+		token  string
+		header string
+		status int
+	}{
+		{name: "disabled", token: "", header: "Bearer secret", status: http.StatusForbidden},
+		{name: "missing", token: "secret", header: "", status: http.StatusUnauthorized},
+		{name: "wrong", token: "secret", header: "Bearer wrong", status: http.StatusUnauthorized},
+		{name: "unknown tool", token: "secret", header: "Bearer secret", status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestAPIServer(t, tc.token)
+
+			request, err := http.NewRequest(http.MethodPost, server.URL+"/api/v1/install", strings.NewReader(`{"tools":["missing"]}`))
+			if err != nil {
+				t.Fatalf("unable to create request: %s", err)
+			}
+			if len(tc.header) > 0 {
+				request.Header.Set("Authorization", tc.header)
+			}
+			response, err := http.DefaultClient.Do(request)
+			if err != nil {
+				t.Fatalf("request failed: %s", err)
+			}
+			//nolint:errcheck
+			response.Body.Close()
+
+			if response.StatusCode != tc.status {
+				t.Errorf("expected status %d but got %d", tc.status, response.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gitlab.com/uniget-org/cli/internal/constants"
+	myos "gitlab.com/uniget-org/cli/pkg/os"
+)
+
+var shellenvShell string
+
+var shellenvShells = []string{"bash", "zsh", "fish", "nu"}
+
+func initShellenvCmd() {
+	shellenvCmd.Flags().StringVar(&shellenvShell, "shell", "", "Shell to generate the environment for ("+strings.Join(shellenvShells, ", ")+"). Defaults to $SHELL")
+
+	rootCmd.AddCommand(shellenvCmd)
+}
+
+var shellenvCmd = &cobra.Command{
+	Use:     "shellenv",
+	Aliases: []string{},
+	Short:   "Print shell environment for installed tools",
+	Long: constants.Header + "\nPrint shell environment for installed tools" + `
+
+Add the output to the configuration of your shell:
+  bash: eval "$(uniget shellenv --shell bash)" in ~/.bashrc
+  zsh:  eval "$(uniget shellenv --shell zsh)" in ~/.zshrc
+  fish: uniget shellenv --shell fish | source in ~/.config/fish/config.fish
+  nu:   uniget shellenv --shell nu | save -f ($nu.default-config-dir | path join "vendor/autoload/uniget.nu")`,
+	GroupID: "config",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		shell := shellenvShell
+		if len(shell) == 0 {
+			shell = filepath.Base(os.Getenv("SHELL"))
+		}
+		if !slices.Contains(shellenvShells, shell) {
+			return fmt.Errorf("unsupported shell: %s", shell)
+		}
+
+		env := newShellEnv()
+		return env.Render(cmd.OutOrStdout(), shell)
+	},
+}
+
+type shellEnv struct {
+	Paths          []string
+	ManPaths       []string
+	DataDirs       []string
+	ZshCompletions []string
+	FishCompletion []string
+	ProfileScripts []string
+	FishScripts    []string
+}
+
+func newShellEnv() shellEnv {
+	target := "/" + configuration.Target
+	if configuration.User {
+		target = configuration.Prefix + "/" + configuration.Target
+	}
+	target = filepath.Clean(target)
+
+	prefix := configuration.Prefix
+	if !configuration.User {
+		prefix = "/"
+	}
+	env := shellEnv{
+		Paths:          []string{target + "/bin"},
+		ManPaths:       []string{filepath.Join(prefix, configuration.GetManDirectory())},
+		DataDirs:       []string{filepath.Join(prefix, configuration.GetDataDirectory())},
+		ZshCompletions: []string{filepath.Join(prefix, configuration.GetCompletionDirectory("zsh"))},
+		FishCompletion: []string{filepath.Join(prefix, configuration.GetCompletionDirectory("fish"))},
+	}
+	if !configuration.User {
+		env.Paths = append(env.Paths, target+"/sbin")
+	}
+
+	profileDirectories := []string{target + "/etc/profile.d"}
+	if configuration.User && configuration.IsIntegrationEnabled("profile.d") {
+		profileDirectories = append(profileDirectories, configuration.GetProfileDDirectory())
+	}
+	for _, directory := range profileDirectories {
+		env.ProfileScripts = append(env.ProfileScripts, findScripts(directory, ".sh")...)
+	}
+	env.FishScripts = findScripts(target+"/share/fish/vendor_conf.d", ".fish")
+
+	return env
+}
+
+func findScripts(directory string, extension string) []string {
+	if !myos.DirectoryExists(directory) {
+		return nil
+	}
+
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil
+	}
+
+	scripts := make([]string, 0)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), extension) {
+			continue
+		}
+		scripts = append(scripts, directory+"/"+entry.Name())
+	}
+	sort.Strings(scripts)
+	return scripts
+}
+
+func (e shellEnv) Render(w io.Writer, shell string) error {
+	var output string
+	switch shell {
+	case "bash":
+		output = e.renderPosix(false)
+	case "zsh":
+		output = e.renderPosix(true)
+	case "fish":
+		output = e.renderFish()
+	case "nu":
+		output = e.renderNu()
+	default:
+		return fmt.Errorf("unsupported shell: %s", shell)
+	}
+
+	_, err := io.WriteString(w, output)
+	if err != nil {
+		return fmt.Errorf("unable to write shell environment: %s", err)
+	}
+	return nil
+}
+
+func posixQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+func fishQuote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return "'" + strings.ReplaceAll(value, "'", `\'`) + "'"
+}
+
+func nuQuote(value string) string {
+	return "r#'" + value + "'#"
+}
+
+func (e shellEnv) renderPosix(zsh bool) string {
+	var b strings.Builder
+
+	prepend := func(variable string, paths []string) {
+		for _, path := range slices.Backward(paths) {
+			fmt.Fprintf(&b, "case \":${%s}:\" in *:%s:*) ;; *) export %s=%s\"${%s:+:${%s}}\" ;; esac\n",
+				variable, posixQuote(path), variable, posixQuote(path), variable, variable)
+		}
+	}
+	prepend("PATH", e.Paths)
+	// An empty element makes man(1) search the default locations as well
+	b.WriteString("[ -n \"${MANPATH+x}\" ] || export MANPATH=:\n")
+	prepend("MANPATH", e.ManPaths)
+	b.WriteString("export XDG_DATA_DIRS=\"${XDG_DATA_DIRS:-/usr/local/share:/usr/share}\"\n")
+	prepend("XDG_DATA_DIRS", e.DataDirs)
+
+	if zsh {
+		for _, path := range slices.Backward(e.ZshCompletions) {
+			fmt.Fprintf(&b, "(( ${fpath[(Ie)%s]} )) || fpath=(%s $fpath)\n", posixQuote(path), posixQuote(path))
+		}
+	}
+
+	for _, script := range e.ProfileScripts {
+		fmt.Fprintf(&b, ". %s\n", posixQuote(script))
+	}
+
+	return b.String()
+}
+
+func (e shellEnv) renderFish() string {
+	var b strings.Builder
+
+	prepend := func(variable string, paths []string) {
+		for _, path := range slices.Backward(paths) {
+			fmt.Fprintf(&b, "contains -- %s $%s; or set -gx --path %s %s $%s\n",
+				fishQuote(path), variable, variable, fishQuote(path), variable)
+		}
+	}
+	prepend("PATH", e.Paths)
+	// An empty element makes man(1) search the default locations as well
+	b.WriteString("set -q MANPATH; or set -gx --path MANPATH ''\n")
+	prepend("MANPATH", e.ManPaths)
+	b.WriteString("set -q XDG_DATA_DIRS; or set -gx --path XDG_DATA_DIRS /usr/local/share /usr/share\n")
+	prepend("XDG_DATA_DIRS", e.DataDirs)
+
+	for _, path := range slices.Backward(e.FishCompletion) {
+		fmt.Fprintf(&b, "contains -- %s $fish_complete_path; or set -g fish_complete_path %s $fish_complete_path\n",
+			fishQuote(path), fishQuote(path))
+	}
+
+	for _, script := range e.FishScripts {
+		fmt.Fprintf(&b, "source %s\n", fishQuote(script))
+	}
+
+	return b.String()
+}
+
+func (e shellEnv) renderNu() string {
+	var b strings.Builder
+
+	quote := func(paths []string) string {
+		quoted := make([]string, 0, len(paths))
+		for _, path := range paths {
+			quoted = append(quoted, nuQuote(path))
+		}
+		return strings.Join(quoted, " ")
+	}
+	prepend := func(variable string, fallback string, paths []string) {
+		fmt.Fprintf(&b, "$env.%s = ($env.%s? | default %s | split row (char esep) | prepend [%s] | uniq | str join (char esep))\n",
+			variable, variable, nuQuote(fallback), quote(paths))
+	}
+	// PATH is converted to a list by nushell
+	fmt.Fprintf(&b, "$env.PATH = ($env.PATH | split row (char esep) | prepend [%s] | uniq)\n", quote(e.Paths))
+	prepend("MANPATH", ":", e.ManPaths)
+	prepend("XDG_DATA_DIRS", "/usr/local/share:/usr/share", e.DataDirs)
+
+	return b.String()
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestShellenvRender(t *testing.T) {
+	env := shellEnv{
+		Paths:          []string{"/home/user/.local/bin"},
+		ManPaths:       []string{"/home/user/.local/share/man"},
+		DataDirs:       []string{"/home/user/.local/share"},
+		ZshCompletions: []string{"/home/user/.local/share/zsh/site-functions"},
+		FishCompletion: []string{"/home/user/.local/share/fish/vendor_completions.d"},
+		ProfileScripts: []string{"/home/user/.local/etc/profile.d/tool.sh"},
+	}
+
+	tt := []struct {
+		shell    string
+		expected []string
+	}{
+		{
+			shell: "bash",
+			expected: []string{
+				`export PATH='/home/user/.local/bin'"${PATH:+:${PATH}}"`,
+				`export MANPATH='/home/user/.local/share/man'`,
+				`export XDG_DATA_DIRS='/home/user/.local/share'`,
+				`. '/home/user/.local/etc/profile.d/tool.sh'`,
+			},
+		},
+		{
+			shell: "zsh",
+			expected: []string{
+				`fpath=('/home/user/.local/share/zsh/site-functions' $fpath)`,
+			},
+		},
+		{
+			shell: "fish",
+			expected: []string{
+				`set -gx --path PATH '/home/user/.local/bin' $PATH`,
+				`set -g fish_complete_path '/home/user/.local/share/fish/vendor_completions.d' $fish_complete_path`,
+			},
+		},
+		{
+			shell: "nu",
+			expected: []string{
+				`prepend [r#'/home/user/.local/bin'#]`,
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.shell, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := env.Render(&buf, tc.shell)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, expected := range tc.expected {
+				if !strings.Contains(buf.String(), expected) {
+					t.Errorf("expected output to contain %q, got:\n%s", expected, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestShellenvFindScripts(t *testing.T) {
+	directory := t.TempDir()
+	for _, name := range []string{"b.sh", "a.sh", "c.fish"} {
+		err := os.WriteFile(directory+"/"+name, []byte(""), 0600)
+		if err != nil {
+			t.Fatalf("failed to create %s: %v", name, err)
+		}
+	}
+
+	scripts := findScripts(directory, ".sh")
+	if fmt.Sprint(scripts) != fmt.Sprintf("[%s/a.sh %s/b.sh]", directory, directory) {
+		t.Errorf("unexpected scripts: %v", scripts)
+	}
+}
@@ -1,25 +1,12 @@
 package main
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
 	"fmt"
-	"os"
-	"strings"
 
 	"github.com/spf13/cobra"
 	"gitlab.com/uniget-org/cli/internal/constants"
-	"gitlab.com/uniget-org/cli/pkg/logging"
-	myos "gitlab.com/uniget-org/cli/pkg/os"
 )
 
-var profileDShim = `
-SCRIPTS="$( find "${target}/etc/profile.d" -type f )"
-for SCRIPT in ${SCRIPTS}; do
-	source "${SCRIPT}"
-done
-`
-
 func initShimCmd() {
 	rootCmd.AddCommand(shimCmd)
 }
@@ -32,7 +19,7 @@ var shimCmd = &cobra.Command{
 	GroupID: "helper",
 	Args:    cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
-		err = installProfileDShim()
+		err = newClient(cmd.OutOrStdout()).InstallProfileDShim()
 		if err != nil {
 			return fmt.Errorf("unable to install profile.d shim: %s", err)
 		}
@@ -40,53 +27,3 @@ var shimCmd = &cobra.Command{
 		return nil
 	},
 }
-
-func installProfileDShim() error {
-	profileDShimFile := configuration.GetProfileDDirectory() + "/uniget-profile.d.sh"
-	profileDScript := strings.ReplaceAll(profileDShim, "${target}", "/"+configuration.Target)
-
-	if configuration.User {
-		profileDShimFile = configuration.GetProfileDDirectory() + "/uniget-profile.d.sh"
-		profileDScript = strings.ReplaceAll(profileDShim, "${target}", configuration.Prefix+"/"+configuration.Target)
-	}
-
-	if myos.FileExists(profileDShimFile) {
-		file, err := os.ReadFile(profileDShimFile) // #nosec G304 -- Filename constructed from configuration
-		if err != nil {
-			return fmt.Errorf("cannot read profile.d shim: %w", err)
-		}
-
-		h := sha256.New()
-		_, err = h.Write(file)
-		if err != nil {
-			return fmt.Errorf("cannot hash profile.d shim: %w", err)
-		}
-		fileSha256 := hex.EncodeToString(h.Sum(nil))
-
-		h = sha256.New()
-		_, err = h.Write([]byte(profileDScript))
-		if err != nil {
-			return fmt.Errorf("cannot hash profile.d shim: %w", err)
-		}
-		profileDScriptSha256 := hex.EncodeToString(h.Sum(nil))
-
-		if fileSha256 == profileDScriptSha256 {
-			logging.Info.Printfln("Profile.d shim is up to date")
-			return nil
-		}
-
-		logging.Info.Printfln("Installing shim for profile.d in %s", profileDShimFile)
-		if myos.DirectoryIsWritable(profileDShimFile) {
-			err := os.WriteFile(
-				profileDShimFile,
-				[]byte(profileDScript),
-				0644,
-			) // #nosec G306 -- File must be world-readable
-			if err != nil {
-				return fmt.Errorf("cannot write profile.d shim: %w", err)
-			}
-		}
-	}
-
-	return nil
-}
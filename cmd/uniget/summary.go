@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"gitlab.com/uniget-org/cli/pkg/uniget"
+)
+
+const (
+	exitCodeFailure        = 1
+	exitCodePartialFailure = 2
+	exitCodeInterrupted    = 130
+)
+
+const exitCodesHelp = `
+Exit codes:
+  0    All tools were processed successfully or were skipped
+  1    The command failed or all processed tools failed
+  2    Some tools failed while others succeeded (partial failure)
+  130  The command was interrupted (SIGINT or SIGTERM)`
+
+var failFast bool
+var keepGoing bool
+
+func addFailurePolicyFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&failFast, "fail-fast", false, "Stop processing tools after the first failure")
+	cmd.Flags().BoolVar(&keepGoing, "keep-going", true, "Continue processing tools after a failure (default)")
+	cmd.MarkFlagsMutuallyExclusive("fail-fast", "keep-going")
+	cmd.Long += "\n" + exitCodesHelp
+}
+
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+func (e *exitCodeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitCodeError) Unwrap() error {
+	return e.err
+}
+
+func printSummary(w io.Writer, result *uniget.Result) {
+	if len(result.Tools) == 0 {
+		return
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+
+	t.AppendHeader(table.Row{"#", "Name", "Version", "Result", "Reason"})
+	for index, r := range result.Tools {
+		t.AppendRow(table.Row{index + 1, r.Name, r.Version, r.Result, r.Reason})
+	}
+	t.AppendFooter(table.Row{"", "", "", "",
+		fmt.Sprintf("%d succeeded, %d skipped, %d failed", result.Count(uniget.ResultSucceeded), result.Count(uniget.ResultSkipped), result.Count(uniget.ResultFailed)),
+	})
+
+	t.Render()
+}
+
+// withExitCode maps failed tools to the exit codes of the CLI
+func withExitCode(err error) error {
+	var failedErr *uniget.FailedError
+	if !errors.As(err, &failedErr) {
+		return err
+	}
+
+	if failedErr.Succeeded == 0 {
+		return &exitCodeError{
+			code: exitCodeFailure,
+			err:  err,
+		}
+	}
+	return &exitCodeError{
+		code: exitCodePartialFailure,
+		err:  err,
+	}
+}
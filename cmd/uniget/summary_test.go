@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"gitlab.com/uniget-org/cli/pkg/uniget"
+)
+
+func TestWithExitCode(t *testing.T) {
+	tt := []struct {
+		name     string
+		results  []string
+		exitCode int
+	}{
+		{
+			name:     "empty",
+			results:  []string{},
+			exitCode: 0,
+		},
+		{
+			name:     "succeeded and skipped",
+			results:  []string{uniget.ResultSucceeded, uniget.ResultSkipped},
+			exitCode: 0,
+		},
+		{
+			name:     "all failed",
+			results:  []string{uniget.ResultFailed, uniget.ResultSkipped},
+			exitCode: exitCodeFailure,
+		},
+		{
+			name:     "partial failure",
+			results:  []string{uniget.ResultSucceeded, uniget.ResultFailed},
+			exitCode: exitCodePartialFailure,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			result := &uniget.Result{}
+			for index, r := range tc.results {
+				result.Tools = append(result.Tools, uniget.ToolResult{Name: fmt.Sprintf("tool%d", index), Result: r})
+			}
+
+			err := withExitCode(result.Err())
+			if tc.exitCode == 0 {
+				if err != nil {
+					t.Errorf("expected no error but got %v", err)
+				}
+				return
+			}
+
+			var exitErr *exitCodeError
+			if !errors.As(fmt.Errorf("wrapped: %w", err), &exitErr) {
+				t.Fatalf("expected exit code error but got %v", err)
+			}
+			if exitErr.code != tc.exitCode {
+				t.Errorf("expected exit code %d but got %d", tc.exitCode, exitErr.code)
+			}
+		})
+	}
+}
+
+func TestPrintSummary(t *testing.T) {
+	result := &uniget.Result{
+		Tools: []uniget.ToolResult{
+			{Name: "foo", Version: "1.0.0", Result: uniget.ResultSucceeded},
+			{Name: "bar", Version: "2.0.0", Result: uniget.ResultFailed, Reason: "unable to get image"},
+		},
+	}
+
+	var buf bytes.Buffer
+	printSummary(&buf, result)
+	out := strings.ToLower(buf.String())
+
+	for _, expected := range []string{"foo", "bar", "unable to get image", "1 succeeded, 0 skipped, 1 failed"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected summary to contain %q but got:\n%s", expected, out)
+		}
+	}
+}
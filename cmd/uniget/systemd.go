@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"gitlab.com/uniget-org/cli/internal/constants"
+	"gitlab.com/uniget-org/cli/pkg/logging"
+	"gitlab.com/uniget-org/cli/pkg/systemd"
+)
+
+var systemdPolicy systemd.Policy
+
+func initSystemdCmd() {
+	setSystemdPolicyCmd.Flags().BoolVar(&systemdPolicy.Enable, "enable", false, "Enable units after installation")
+	setSystemdPolicyCmd.Flags().BoolVar(&systemdPolicy.Start, "start", false, "Start units after installation")
+	setSystemdPolicyCmd.Flags().BoolVar(&systemdPolicy.RestartOnUpgrade, "restart-on-upgrade", false, "Restart running units after an upgrade")
+
+	systemdCmd.AddCommand(listSystemdCmd)
+	systemdCmd.AddCommand(setSystemdPolicyCmd)
+	systemdCmd.AddCommand(unsetSystemdPolicyCmd)
+
+	rootCmd.AddCommand(systemdCmd)
+}
+
+var systemdCmd = &cobra.Command{
+	Use:     "systemd",
+	Short:   "Manage systemd units of installed tools",
+	Long:    constants.Header + "\nManage systemd units of installed tools\n\nUnits are reloaded after installation and stopped before uninstallation. Policies enable, start and restart units per tool.",
+	GroupID: "config",
+}
+
+var listSystemdCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"l", "ls"},
+	Short:   "List units of installed tools",
+	Long:    constants.Header + "\nList units of installed tools and their policies",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		policies, err := systemd.LoadPolicies(configuration.GetSystemdPoliciesFile())
+		if err != nil {
+			return fmt.Errorf("unable to load policies: %s", err)
+		}
+
+		t := table.NewWriter()
+		t.SetOutputMirror(cmd.OutOrStdout())
+
+		client := newClient(cmd.OutOrStdout())
+		t.AppendHeader(table.Row{"Tool", "Units", "Enable", "Start", "Restart on upgrade"})
+		for _, tool := range tools.Tools {
+			installedFiles, err := client.InstalledFiles(tool.Name)
+			if err != nil {
+				return fmt.Errorf("unable to read installed files of %s: %s", tool.Name, err)
+			}
+			units := systemd.FindUnits(installedFiles)
+			policy, hasPolicy := policies[tool.Name]
+			if len(units) == 0 && !hasPolicy {
+				continue
+			}
+			t.AppendRow(table.Row{tool.Name, strings.Join(units, ", "), policy.Enable, policy.Start, policy.RestartOnUpgrade})
+		}
+
+		t.Render()
+		return nil
+	},
+}
+
+var setSystemdPolicyCmd = &cobra.Command{
+	Use:   "set <tool>",
+	Short: "Set policy for units of a tool",
+	Long:  constants.Header + "\nSet policy for units of a tool",
+	Args:  cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return tools.GetNames(), cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, err := tools.GetByName(args[0])
+		if err != nil {
+			return fmt.Errorf("unable to find tool %s: %s", args[0], err)
+		}
+
+		policies, err := systemd.LoadPolicies(configuration.GetSystemdPoliciesFile())
+		if err != nil {
+			return fmt.Errorf("unable to load policies: %s", err)
+		}
+		policies[args[0]] = systemdPolicy
+		err = systemd.SavePolicies(configuration.GetSystemdPoliciesFile(), policies)
+		if err != nil {
+			return fmt.Errorf("unable to save policies: %s", err)
+		}
+
+		logging.Success.Printfln("Set policy for units of %s", args[0])
+		return nil
+	},
+}
+
+var unsetSystemdPolicyCmd = &cobra.Command{
+	Use:   "unset <tool>",
+	Short: "Remove policy for units of a tool",
+	Long:  constants.Header + "\nRemove policy for units of a tool",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		policies, err := systemd.LoadPolicies(configuration.GetSystemdPoliciesFile())
+		if err != nil {
+			return fmt.Errorf("unable to load policies: %s", err)
+		}
+		delete(policies, args[0])
+		err = systemd.SavePolicies(configuration.GetSystemdPoliciesFile(), policies)
+		if err != nil {
+			return fmt.Errorf("unable to save policies: %s", err)
+		}
+
+		logging.Success.Printfln("Removed policy for units of %s", args[0])
+		return nil
+	},
+}
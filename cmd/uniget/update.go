@@ -1,22 +1,35 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"time"
 
-	"github.com/pterm/pterm"
+	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/spf13/cobra"
 
+	"gitlab.com/uniget-org/cli/internal/config"
 	"gitlab.com/uniget-org/cli/internal/constants"
+	"gitlab.com/uniget-org/cli/pkg/events"
 	"gitlab.com/uniget-org/cli/pkg/logging"
+	"gitlab.com/uniget-org/cli/pkg/semver"
 	"gitlab.com/uniget-org/cli/pkg/tool"
 )
 
 var updateQuiet bool
 var updateShowAllTools bool
+var updateRevision string
+var updateListRevisions bool
 
 func initUpdateCmd() {
 	updateCmd.Flags().BoolVarP(&updateQuiet, "quiet", "q", false, "Do not print new tools")
 	updateCmd.Flags().BoolVar(&updateShowAllTools, "all", false, "Show all updates including tools that are not installed")
+	updateCmd.Flags().StringVar(&updateRevision, "revision", "", "Pin metadata to a revision by tag, git commit or date (2006-01-02). Use main to follow the latest metadata")
+	updateCmd.Flags().BoolVar(&updateListRevisions, "list-revisions", false, "List available metadata revisions")
+	updateCmd.MarkFlagsMutuallyExclusive("revision", "list-revisions")
+	addOutputFlag(updateCmd)
 
 	rootCmd.AddCommand(updateCmd)
 }
@@ -25,30 +38,47 @@ var updateCmd = &cobra.Command{
 	Use:     "update",
 	Aliases: []string{},
 	Short:   "Update tool manifest",
-	Long:    constants.Header + "\nUpdate tool manifest",
+	Long: constants.Header + "\nUpdate tool manifest" + `
+
+The metadata can be pinned to a revision using --revision. The pinned
+revision is stored in the configuration directory and can be overridden
+using UNIGET_METADATAREVISION.`,
 	GroupID: "metadata",
 	Args:    cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
-		newRevisionAvailable, err := configuration.HasMetadataUpdate(tools.Revision)
-		if err != nil {
-			return fmt.Errorf("error checking for metadata update: %s", err)
-		}
-		if newRevisionAvailable {
-			err = configuration.DownloadMetadata()
+		if updateListRevisions {
+			revisions, err := configuration.ListMetadataRevisions(cmd.Context())
 			if err != nil {
-				return fmt.Errorf("error downloading metadata: %s", err)
+				return fmt.Errorf("error listing metadata revisions: %s", err)
 			}
+			if emitter.IsJSON() {
+				data, err := json.Marshal(revisions)
+				if err != nil {
+					return fmt.Errorf("failed to marshal to json: %s", err)
+				}
+				//nolint:errcheck
+				fmt.Fprintln(cmd.OutOrStdout(), string(data))
+				return nil
+			}
+			printMetadataRevisions(cmd.OutOrStdout(), revisions, configuration.GetMetadataImageTag())
+			return nil
+		}
 
-		} else {
-			logging.Info.Println("Metadata is up to date")
+		if cmd.Flags().Changed("revision") {
+			err = pinMetadataRevision(cmd.Context(), updateRevision)
+			if err != nil {
+				return err
+			}
+		} else if configuration.IsMetadataRevisionPinned() {
+			logging.Info.Printfln("Metadata is pinned to revision %s", configuration.MetadataRevision)
 		}
 
-		var newTools *tool.Tools
-		newTools, err = configuration.LoadMetadata(configuration.GetMetadataFile())
+		client := newClient(cmd.OutOrStdout())
+		_, err = client.UpdateMetadata(cmd.Context())
 		if err != nil {
-			return fmt.Errorf("error loading metadata: %s", err)
+			return err
 		}
-		logging.Debugf("Loaded %d tools from metadata", len(newTools.Tools))
+		newTools := client.Tools()
 
 		if updateQuiet {
 			return nil
@@ -57,14 +87,10 @@ var updateCmd = &cobra.Command{
 		var addedTools tool.Tools
 		var updatedTools tool.Tools
 		var updatedInstalledTools tool.Tools
-		newUnigetVersion := ""
+		installedVersions := make(map[string]string)
 		for _, newTool := range newTools.Tools {
 			logging.Debugf("Checking tool %s for updates", newTool.Name)
 
-			if version != "main" && newTool.Name == "uniget" && newTool.Version != version {
-				newUnigetVersion = newTool.Version
-			}
-
 			tool, err := tools.GetByName(newTool.Name)
 			if err != nil {
 				addedTools.Tools = append(addedTools.Tools, newTool)
@@ -85,6 +111,7 @@ var updateCmd = &cobra.Command{
 				logging.Debugf("  Tool %s was updated from %s to %s", newTool.Name, tool.Status.Version, newTool.Version)
 
 				updatedTools.Tools = append(updatedTools.Tools, newTool)
+				installedVersions[newTool.Name] = tool.Status.Version
 				if tool.IsInstalled() {
 					logging.Debugf("  Tool %s is installed", newTool.Name)
 					updatedInstalledTools.Tools = append(updatedInstalledTools.Tools, newTool)
@@ -94,19 +121,94 @@ var updateCmd = &cobra.Command{
 
 		logging.Debugf("Showing new tools and updates")
 		for _, tool := range addedTools.Tools {
-			logging.Customf(pterm.FgBlack, pterm.BgGreen, pterm.FgWhite, pterm.BgDefault, "NEW", " %s (%s)", tool.Name, tool.Description)
+			emitter.Emit(events.Event{
+				Type:    events.TypeToolAdded,
+				Tool:    tool.Name,
+				Version: tool.Version,
+				Message: fmt.Sprintf("%s (%s)", tool.Name, tool.Description),
+			})
 		}
 		toolsToShow := updatedInstalledTools
 		if updateShowAllTools {
 			toolsToShow = updatedTools
 		}
 		for _, tool := range toolsToShow.Tools {
-			logging.Customf(pterm.FgBlack, pterm.BgYellow, pterm.FgWhite, pterm.BgDefault, "UPDATE", "%s %s", tool.Name, tool.Version)
+			emitter.Emit(events.Event{
+				Type:       events.TypeToolUpdated,
+				Tool:       tool.Name,
+				Version:    tool.Version,
+				OldVersion: installedVersions[tool.Name],
+				Message:    fmt.Sprintf("%s %s", tool.Name, tool.Version),
+			})
 		}
-		if len(newUnigetVersion) > 0 {
-			logging.Customf(pterm.FgBlack, pterm.BgYellow, pterm.FgWhite, pterm.BgDefault, "NEWS", "Update to uniget %s by running 'uniget self-upgrade'", newUnigetVersion)
+		if version != "main" {
+			unigetTool, err := newTools.GetByName("uniget")
+			if err == nil {
+				channel, newUnigetVersion, err := resolveUnigetVersion(cmd.Context(), unigetTool)
+				if err != nil {
+					logging.Warning.Printfln("Unable to resolve version for channel %s: %s", configuration.Channel, err)
+				} else if channel.ShouldUpgrade(version, newUnigetVersion) {
+					message := fmt.Sprintf("Update to uniget %s by running 'uniget self-upgrade'", newUnigetVersion)
+					if channel.Kind != semver.ChannelLatest {
+						message = fmt.Sprintf("Update to uniget %s from channel %s by running 'uniget self-upgrade'", newUnigetVersion, channel.Spec)
+					}
+					emitter.Emit(events.Event{
+						Type:    events.TypeNews,
+						Tool:    "uniget",
+						Version: newUnigetVersion,
+						Message: message,
+					})
+				}
+			}
 		}
 
 		return nil
 	},
 }
+
+func pinMetadataRevision(ctx context.Context, spec string) error {
+	if len(spec) == 0 || spec == constants.MetadataImageTag {
+		err := configuration.SaveMetadataRevision("")
+		if err != nil {
+			return fmt.Errorf("error unpinning metadata revision: %s", err)
+		}
+		logging.Info.Printfln("Following the latest metadata")
+		return nil
+	}
+
+	revisions, err := configuration.ListMetadataRevisions(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing metadata revisions: %s", err)
+	}
+	revision, err := config.ResolveMetadataRevision(revisions, spec)
+	if err != nil {
+		return fmt.Errorf("error resolving metadata revision: %s", err)
+	}
+
+	err = configuration.SaveMetadataRevision(revision.Tag)
+	if err != nil {
+		return fmt.Errorf("error pinning metadata revision: %s", err)
+	}
+	logging.Info.Printfln("Pinned metadata to revision %s (commit %s, created %s)", revision.Tag, revision.Revision, revision.Created.Local().Format(time.DateTime))
+	return nil
+}
+
+func printMetadataRevisions(w io.Writer, revisions []config.MetadataRevision, current string) {
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+
+	t.AppendHeader(table.Row{"", "Tag", "Commit", "Created"})
+	for _, revision := range revisions {
+		marker := ""
+		if revision.Tag == current {
+			marker = "*"
+		}
+		created := ""
+		if !revision.Created.IsZero() {
+			created = revision.Created.Local().Format(time.DateTime)
+		}
+		t.AppendRow(table.Row{marker, revision.Tag, revision.Revision, created})
+	}
+
+	t.Render()
+}
@@ -8,9 +8,13 @@ import (
 )
 
 var upgradeDryRun = false
+var upgradeQuery = ""
 
 func initUpgradeCmd() {
 	upgradeCmd.Flags().BoolVar(&upgradeDryRun, "dry-run", upgradeDryRun, "Show tool(s) planned for upgrade")
+	addQueryFlag(upgradeCmd, &upgradeQuery, "Upgrade only installed tools matching the query")
+	addFailurePolicyFlags(upgradeCmd)
+	addOutputFlag(upgradeCmd)
 
 	rootCmd.AddCommand(upgradeCmd)
 }
@@ -23,14 +27,20 @@ var upgradeCmd = &cobra.Command{
 	GroupID: "tool",
 	Args:    cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
-		requestedTools, err := findInstalledTools(tools)
+		requestedTools, err := newClient(nil).Status(cmd.Context())
 		if err != nil {
 			return fmt.Errorf("failed to find installed tools: %s", err)
 		}
+		if len(upgradeQuery) > 0 {
+			requestedTools, err = selectTools(requestedTools, upgradeQuery)
+			if err != nil {
+				return err
+			}
+		}
 
-		err = installTools(cmd.OutOrStdout(), requestedTools, false, upgradeDryRun, false, false, false)
+		err = installTools(cmd.Context(), textOutput(cmd), requestedTools, false, upgradeDryRun, false, false, false)
 		if err != nil {
-			return fmt.Errorf("failed to upgrade tools: %s", err)
+			return fmt.Errorf("failed to upgrade tools: %w", err)
 		}
 
 		return nil
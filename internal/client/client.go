@@ -0,0 +1,240 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"gitlab.com/uniget-org/cli/internal/config"
+	"gitlab.com/uniget-org/cli/pkg/cache"
+	"gitlab.com/uniget-org/cli/pkg/events"
+	"gitlab.com/uniget-org/cli/pkg/logging"
+	"gitlab.com/uniget-org/cli/pkg/tool"
+)
+
+// Options configure a Client. The zero value installs into the default
+// system locations without a cache and discards human readable output.
+type Options struct {
+	// Prefix is the base directory for the installation
+	Prefix string
+	// Target is the installation directory relative to Prefix
+	Target string
+	// User installs into the home directory of the current user
+	User bool
+	// AutoUpdate downloads metadata even if it is already present
+	AutoUpdate bool
+	// Cache stores tool images between installations
+	Cache cache.Cache
+	// Emitter receives progress events
+	Emitter *events.Emitter
+	// Output receives human readable output like usage messages
+	Output io.Writer
+	// FailFast stops processing tools after the first failure
+	FailFast bool
+}
+
+// Client installs, upgrades and uninstalls tools from the catalog. A Client
+// is not safe for concurrent use.
+type Client struct {
+	configuration *config.Config
+	tools         *tool.Tools
+	cache         cache.Cache
+	emitter       *events.Emitter
+	output        io.Writer
+	failFast      bool
+}
+
+// New creates a client and loads the metadata. Metadata is downloaded if
+// it is missing or if AutoUpdate is set.
+// The network settings are applied to the whole process.
+func New(ctx context.Context, opts Options) (*Client, error) {
+	configuration := config.NewDefaultConfig()
+	configuration.AutoUpdate = configuration.AutoUpdate || opts.AutoUpdate
+
+	var configOptions []config.ConfigOption
+	if len(opts.Prefix) > 0 {
+		prefix, err := filepath.Abs(opts.Prefix)
+		if err != nil {
+			return nil, fmt.Errorf("cannot determine absolute path of prefix %s: %s", opts.Prefix, err)
+		}
+		configOptions = append(configOptions, config.WithPrefix(prefix))
+	}
+	if len(opts.Target) > 0 {
+		configOptions = append(configOptions, config.WithTarget(strings.TrimLeft(opts.Target, "/")))
+	}
+	if opts.User {
+		configuration.User = true
+		configuration.SetUserConfig(configOptions...)
+	} else {
+		configuration.SetGlobalConfig(configOptions...)
+	}
+	configuration.ApplyTimeouts()
+	err := configuration.ApplyTransport()
+	if err != nil {
+		return nil, fmt.Errorf("unable to configure network: %s", err)
+	}
+
+	err = ctx.Err()
+	if err != nil {
+		return nil, err
+	}
+	if configuration.MetadataNeedsDownload() {
+		logging.Debugf("Metadata does not exist. Downloading...")
+		err = configuration.DownloadMetadata(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error downloading metadata: %s", err)
+		}
+	}
+	tools, err := configuration.LoadMetadata(configuration.GetMetadataFile())
+	if err != nil {
+		return nil, fmt.Errorf("error loading metadata: %s", err)
+	}
+
+	return NewFromConfig(configuration, tools, opts), nil
+}
+
+// NewFromConfig creates a client for an existing configuration and catalog.
+// Prefix, Target, User and AutoUpdate are taken from the configuration.
+func NewFromConfig(configuration *config.Config, tools *tool.Tools, opts Options) *Client {
+	c := &Client{
+		configuration: configuration,
+		tools:         tools,
+		cache:         opts.Cache,
+		emitter:       opts.Emitter,
+		output:        opts.Output,
+		failFast:      opts.FailFast,
+	}
+	if c.cache == nil {
+		c.cache = cache.NewNoneCache()
+	}
+	if c.emitter == nil {
+		c.emitter = events.NewEmitter(io.Discard, events.FormatText)
+	}
+	if c.output == nil {
+		c.output = io.Discard
+	}
+
+	return c
+}
+
+// Tools returns the catalog of the client
+func (c *Client) Tools() *tool.Tools {
+	return c.tools
+}
+
+// Status populates the status of all tools in the catalog and returns the
+// tools which are installed
+func (c *Client) Status(ctx context.Context) (*tool.Tools, error) {
+	var installedTools = &tool.Tools{}
+	for index, t := range c.tools.Tools {
+		err := ctx.Err()
+		if err != nil {
+			return nil, err
+		}
+
+		logging.Debugf("Getting status for requested tool %s", t.Name)
+		err = c.updateStatus(&c.tools.Tools[index])
+		if err != nil {
+			return nil, fmt.Errorf("failed to update status for tool %s: %s", t.Name, err)
+		}
+
+		if c.tools.Tools[index].IsInstalled() {
+			logging.Debugf("Adding %s to requested tools", t.Name)
+			installedTools.Tools = append(installedTools.Tools, c.tools.Tools[index])
+		}
+	}
+
+	return installedTools, nil
+}
+
+// MetadataUpdate describes the result of UpdateMetadata
+type MetadataUpdate struct {
+	Revision    string
+	OldRevision string
+	Updated     bool
+}
+
+// UpdateMetadata downloads new metadata if a new revision is available and
+// replaces the catalog of the client
+func (c *Client) UpdateMetadata(ctx context.Context) (*MetadataUpdate, error) {
+	update := &MetadataUpdate{
+		OldRevision: c.tools.Revision,
+	}
+
+	newRevisionAvailable, err := c.configuration.HasMetadataUpdate(ctx, c.tools.Revision)
+	if err != nil {
+		return nil, fmt.Errorf("error checking for metadata update: %s", err)
+	}
+	err = ctx.Err()
+	if err != nil {
+		return nil, err
+	}
+	if newRevisionAvailable {
+		err = c.configuration.DownloadMetadata(ctx)
+		if err != nil {
+			c.emitter.Emit(events.Event{
+				Type:        events.TypeFailed,
+				OldRevision: c.tools.Revision,
+				Error:       err.Error(),
+			})
+			return nil, fmt.Errorf("error downloading metadata: %s", err)
+		}
+
+	} else {
+		c.emitter.Emit(events.Event{
+			Type:     events.TypeMetadataCurrent,
+			Revision: c.tools.Revision,
+			Message:  "Metadata is up to date",
+		})
+	}
+
+	newTools, err := c.configuration.LoadMetadata(c.configuration.GetMetadataFile())
+	if err != nil {
+		return nil, fmt.Errorf("error loading metadata: %s", err)
+	}
+	if newRevisionAvailable {
+		c.emitter.Emit(events.Event{
+			Type:        events.TypeMetadataUpdated,
+			Revision:    newTools.Revision,
+			OldRevision: c.tools.Revision,
+			Message:     fmt.Sprintf("Loaded %d tools from metadata", len(newTools.Tools)),
+		})
+	} else {
+		logging.Debugf("Loaded %d tools from metadata", len(newTools.Tools))
+	}
+
+	c.tools = newTools
+	update.Revision = newTools.Revision
+	update.Updated = newRevisionAvailable
+	return update, nil
+}
+
+func (c *Client) updateStatus(t *tool.Tool) error {
+	return t.UpdateStatus(
+		c.configuration.Prefix,
+		c.configuration.Target,
+		c.configuration.GetCacheDirectory(),
+		c.configuration.Arch,
+		c.configuration.AltArch,
+	)
+}
+
+func (c *Client) manifestFile(toolName string, extension string) string {
+	return c.configuration.GetLibDirectory() + "/manifests/" + toolName + extension
+}
+
+// installDirectory returns the absolute directory which paths in the
+// image are relative to
+func (c *Client) installDirectory() (string, error) {
+	installDir := c.configuration.Prefix
+	if len(installDir) == 0 {
+		installDir = "/"
+	}
+	dir, err := filepath.Abs(installDir)
+	if err != nil {
+		return "", fmt.Errorf("error resolving directory %s: %s", installDir, err)
+	}
+	return dir, nil
+}
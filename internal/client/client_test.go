@@ -0,0 +1,247 @@
+package client
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"gitlab.com/uniget-org/cli/internal/config"
+	"gitlab.com/uniget-org/cli/pkg/tool"
+)
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+
+	configuration := config.NewDefaultConfig()
+	configuration.Prefix = t.TempDir()
+	configuration.SetGlobalConfig()
+	catalog := &tool.Tools{
+		Revision: "abc",
+		Tools: []tool.Tool{
+			{Name: "kubectl", Version: "1.30.0", Binary: "${target}/bin/kubectl"},
+			{Name: "helm", Version: "3.15.0", Binary: "${target}/bin/helm", RuntimeDependencies: []string{"kubectl"}},
+			{Name: "jq", Version: "1.7.1", Binary: "${target}/bin/jq", ConflictsWith: []string{"gojq"}},
+			{Name: "gojq", Version: "0.12.16", Binary: "${target}/bin/gojq", ConflictsWith: []string{"jq"}},
+			{Name: "old", Version: "1.0.0", Binary: "${target}/bin/old", Lifecycle: tool.Lifecycle{RenamedTo: "new"}},
+		},
+	}
+	return NewFromConfig(configuration, catalog, Options{})
+}
+
+func TestPlan(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	plan, err := c.Plan(ctx, []string{"helm"}, InstallOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(plan.Tools.Tools) != 2 || plan.Tools.Tools[0].Name != "kubectl" || plan.Tools.Tools[0].Status.IsRequested {
+		t.Errorf("unexpected plan: %+v", plan.Tools.GetNames())
+	}
+	if !plan.Tools.Tools[1].Status.IsRequested || plan.HasConflicts() {
+		t.Errorf("unexpected status of requested tool: %+v", plan.Tools.Tools[1].Status)
+	}
+
+	plan, err = c.Plan(ctx, []string{"jq", "gojq"}, InstallOptions{SkipConflicts: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(plan.ConflictsBetweenPlanned.Tools) != 2 || !plan.Tools.Tools[0].Status.SkipDueToConflicts {
+		t.Errorf("expected conflicts between planned tools: %+v", plan)
+	}
+
+	_, err = c.Install(ctx, []string{"jq", "gojq"}, InstallOptions{})
+	if !errors.Is(err, ErrConflicts) {
+		t.Errorf("expected conflicts but got %v", err)
+	}
+
+	_, err = c.Plan(ctx, []string{"old"}, InstallOptions{})
+	if !errors.Is(err, ErrRenamedOrRemoved) {
+		t.Errorf("expected renamed tool to be rejected but got %v", err)
+	}
+
+	_, err = c.Plan(ctx, []string{"missing"}, InstallOptions{})
+	if err == nil {
+		t.Errorf("expected error for missing tool")
+	}
+}
+
+func TestStatusAndUninstall(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	installedTools, err := c.Status(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(installedTools.Tools) != 0 {
+		t.Errorf("expected no installed tools but got %v", installedTools.GetNames())
+	}
+
+	_, err = c.Upgrade(ctx, []string{"jq"}, InstallOptions{})
+	if err == nil {
+		t.Errorf("expected error when upgrading a tool which is not installed")
+	}
+
+	result, err := c.Uninstall(ctx, []string{"jq"}, UninstallOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result.Count(ResultSkipped) != 1 || result.Err() != nil {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = c.Status(cancelled)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected cancellation but got %v", err)
+	}
+}
+
+func TestResultErr(t *testing.T) {
+	result := &Result{
+		Tools: []ToolResult{
+			{Name: "foo", Result: ResultSucceeded},
+			{Name: "bar", Result: ResultFailed},
+		},
+	}
+
+	var failedErr *FailedError
+	err := result.Err()
+	if !errors.As(err, &failedErr) || failedErr.Failed != 1 || failedErr.Succeeded != 1 {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err.Error() != "1 of 2 tool(s) failed" {
+		t.Errorf("unexpected message: %s", err)
+	}
+}
+
+func TestApplyCancelled(t *testing.T) {
+	c := newTestClient(t)
+
+	plan, err := c.Plan(context.Background(), []string{"helm"}, InstallOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err := c.Apply(ctx, plan, InstallOptions{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation but got %v", err)
+	}
+	if result.Count(ResultSkipped) != 2 || result.Count(ResultFailed) != 0 {
+		t.Errorf("expected all tools to be skipped: %+v", result)
+	}
+}
+
+func TestApplyCancelledUpgrade(t *testing.T) {
+	c := newTestClient(t)
+	prefix := c.configuration.Prefix
+
+	err := os.MkdirAll(prefix+"/usr/local/bin", 0755) // #nosec G301 -- Test directory
+	if err != nil {
+		t.Fatalf("failed to create directory: %s", err)
+	}
+	err = os.WriteFile(prefix+"/usr/local/bin/kubectl", []byte("old"), 0755) // #nosec G306 -- Test binary
+	if err != nil {
+		t.Fatalf("failed to write binary: %s", err)
+	}
+	err = c.writeInstalledFiles("kubectl", []string{"usr/local/bin/kubectl"})
+	if err != nil {
+		t.Fatalf("failed to write installed files: %s", err)
+	}
+	previous := tool.Tool{Name: "kubectl", Version: "1.29.0"}
+	err = previous.CreateMarkerFile(c.configuration.GetCacheDirectory())
+	if err != nil {
+		t.Fatalf("failed to create marker file: %s", err)
+	}
+
+	plan, err := c.Plan(context.Background(), []string{"kubectl"}, InstallOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !plan.Tools.Tools[0].IsInstalled() {
+		t.Fatalf("expected kubectl to be installed: %+v", plan.Tools.Tools[0].Status)
+	}
+
+	// The layer is served through a pipe to cancel while extracting
+	layerFile := t.TempDir() + "/kubectl.tar"
+	err = syscall.Mkfifo(layerFile, 0600)
+	if err != nil {
+		t.Fatalf("failed to create pipe: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		_, err := c.Apply(ctx, plan, InstallOptions{PathToTarMappings: map[string]string{"kubectl": layerFile}})
+		done <- err
+	}()
+
+	layer, err := os.OpenFile(layerFile, os.O_WRONLY, 0) // #nosec G304 -- Test file
+	if err != nil {
+		t.Fatalf("failed to open pipe: %s", err)
+	}
+	writer := tar.NewWriter(layer)
+	for _, name := range []string{"usr/local/bin/kubectl", "usr/local/bin/kubectl-plugin"} {
+		err = writer.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: 0755, Size: 3})
+		if err != nil {
+			t.Fatalf("failed to write header: %s", err)
+		}
+		_, err = writer.Write([]byte("new"))
+		if err != nil {
+			t.Fatalf("failed to write content: %s", err)
+		}
+	}
+	err = writer.Flush()
+	if err != nil {
+		t.Fatalf("failed to flush layer: %s", err)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		_, err := os.Stat(prefix + "/usr/local/bin/kubectl-plugin")
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for extraction")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+
+	// Extraction is waiting for the next entry which must fail
+	err = writer.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: "usr/local/bin/kubectl-other", Mode: 0755, Size: 3})
+	if err != nil {
+		t.Fatalf("failed to write header: %s", err)
+	}
+	_, _ = writer.Write([]byte("new"))
+	_ = writer.Close()
+	_ = layer.Close()
+
+	err = <-done
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation but got %v", err)
+	}
+	_, err = os.Stat(prefix + "/usr/local/bin/kubectl")
+	if err != nil {
+		t.Errorf("expected file of previous version to be kept: %s", err)
+	}
+	_, err = os.Stat(prefix + "/usr/local/bin/kubectl-plugin")
+	if !os.IsNotExist(err) {
+		t.Errorf("expected new file to be removed")
+	}
+	installedFiles, err := c.InstalledFiles("kubectl")
+	if err != nil || len(installedFiles) != 1 || installedFiles[0] != "usr/local/bin/kubectl" {
+		t.Errorf("expected manifest of previous version to be kept: %v (%v)", installedFiles, err)
+	}
+}
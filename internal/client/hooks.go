@@ -0,0 +1,122 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"gitlab.com/uniget-org/cli/pkg/events"
+	"gitlab.com/uniget-org/cli/pkg/logging"
+	myos "gitlab.com/uniget-org/cli/pkg/os"
+)
+
+const (
+	HookPreInstall    = "pre-install"
+	HookPostInstall   = "post-install"
+	HookPreUninstall  = "pre-uninstall"
+	HookPostUninstall = "post-uninstall"
+)
+
+func (c *Client) hooksDirectory(hookType string) string {
+	switch hookType {
+	case HookPreInstall:
+		return c.configuration.GetHooksPreInstallDirectory()
+	case HookPostInstall:
+		return c.configuration.GetHooksPostInstallDirectory()
+	case HookPreUninstall:
+		return c.configuration.GetHooksPreUninstallDirectory()
+	case HookPostUninstall:
+		return c.configuration.GetHooksPostUninstallDirectory()
+	}
+	return ""
+}
+
+// runHooks executes all hooks of the given type passing the tool names
+func (c *Client) runHooks(ctx context.Context, hookType string, args ...string) error {
+	if len(args) == 0 {
+		return nil
+	}
+
+	err := ProcessHooks(c.hooksDirectory(hookType), func(hookFile string) error {
+		c.emitHookRun(hookType, hookFile, args)
+		err := RunHook(ctx, hookFile, args...)
+		if err != nil {
+			return fmt.Errorf("unable to execute %s hook (%s): %s", hookType, hookFile, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("unable to run %s hooks: %s", hookType, err)
+	}
+
+	return nil
+}
+
+func (c *Client) emitHookRun(hookType string, hookFile string, args []string) {
+	c.emitter.Emit(events.Event{
+		Type:    events.TypeHookRun,
+		Hook:    hookType,
+		Tools:   args,
+		Message: fmt.Sprintf("Executing %s hook %s:", hookType, hookFile),
+	})
+}
+
+// ProcessHooks calls callback for every hook file in path. Hidden files
+// and directories are ignored and symlinks are rejected.
+func ProcessHooks(path string, callback func(file string) error) error {
+	if !myos.DirectoryExists(path) {
+		return nil
+	}
+
+	files, err := os.ReadDir(path)
+	if err != nil {
+		return fmt.Errorf("unable to read directory %s: %w", path, err)
+	}
+
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		if strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+
+		logging.Debugf("processing hook file %s/%s", path, file.Name())
+
+		hookFile := path + "/" + file.Name()
+		hookFileInfo, err := os.Lstat(hookFile)
+		if err != nil {
+			return fmt.Errorf("unable to stat hook file %s: %w", hookFile, err)
+		}
+		if hookFileInfo.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("hook file %s is a symlink, which is not allowed for security reasons", hookFile)
+		}
+
+		err = callback(hookFile)
+		if err != nil {
+			return fmt.Errorf("error processing hook file %s: %w", hookFile, err)
+		}
+	}
+
+	return nil
+}
+
+// RunHook executes a single hook file
+func RunHook(ctx context.Context, hookFile string, args ...string) error {
+	if !myos.FileExists(hookFile) {
+		return fmt.Errorf("hook does not exist: %s", hookFile)
+	}
+
+	logging.Debugf("running hook in file %s (args: %s)", hookFile, args)
+	command := exec.CommandContext(ctx, hookFile, args...) // #nosec G204 -- Tool images are a trusted source
+	command.Stdout = logging.OutputWriter
+	command.Stderr = logging.ErrorWriter
+	err := command.Run()
+	if err != nil {
+		return fmt.Errorf("unable to execute hook (%s): %s", hookFile, err)
+	}
+
+	return nil
+}
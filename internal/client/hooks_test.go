@@ -1,9 +1,16 @@
-package main
+package client
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"testing"
+
+	"gitlab.com/uniget-org/cli/internal/config"
+	"gitlab.com/uniget-org/cli/pkg/events"
+	"gitlab.com/uniget-org/cli/pkg/tool"
 )
 
 func TestRunHook(t *testing.T) {
@@ -17,7 +24,7 @@ func TestRunHook(t *testing.T) {
 		t.Fatalf("Failed to create hook file: %v", err)
 	}
 
-	_, err = runHook(hookFile)
+	err = RunHook(context.Background(), hookFile)
 	if err != nil {
 		t.Fatalf("Failed to run hook: %s", err)
 	}
@@ -38,9 +45,8 @@ func TestProcessHooks(t *testing.T) {
 		t.Fatalf("Failed to create hook file: %v", err)
 	}
 
-	err = processHooks(dir, func(file string) error {
-		_, err := runHook(file)
-		return err
+	err = ProcessHooks(dir, func(file string) error {
+		return RunHook(context.Background(), file)
 	})
 	if err != nil {
 		t.Fatalf("Failed to process hooks: %s", err)
@@ -50,3 +56,20 @@ func TestProcessHooks(t *testing.T) {
 		t.Fatalf("Expected file %s to exist, but it does not", lockFile)
 	}
 }
+
+func TestEmitHookRunJSON(t *testing.T) {
+	var buf bytes.Buffer
+	c := NewFromConfig(config.NewDefaultConfig(), &tool.Tools{}, Options{
+		Emitter: events.NewEmitter(&buf, events.FormatJSON),
+	})
+	c.emitHookRun(HookPostInstall, "/etc/uniget/hooks/post-install/test.sh", []string{"jq"})
+
+	var event events.Event
+	err := json.Unmarshal(buf.Bytes(), &event)
+	if err != nil {
+		t.Fatalf("failed to unmarshal event %q: %v", buf.String(), err)
+	}
+	if event.Type != events.TypeHookRun || event.Hook != HookPostInstall || len(event.Tools) != 1 {
+		t.Errorf("unexpected event: %+v", event)
+	}
+}
@@ -0,0 +1,487 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"gitlab.com/uniget-org/cli/internal/common"
+	"gitlab.com/uniget-org/cli/internal/config"
+	"gitlab.com/uniget-org/cli/internal/constants"
+	"gitlab.com/uniget-org/cli/pkg/archive"
+	"gitlab.com/uniget-org/cli/pkg/containers"
+	"gitlab.com/uniget-org/cli/pkg/events"
+	"gitlab.com/uniget-org/cli/pkg/logging"
+	myos "gitlab.com/uniget-org/cli/pkg/os"
+	"gitlab.com/uniget-org/cli/pkg/tool"
+	"gitlab.com/uniget-org/cli/pkg/tui"
+)
+
+// Install plans and installs the given tools including their dependencies
+func (c *Client) Install(ctx context.Context, names []string, opts InstallOptions) (*Result, error) {
+	plan, err := c.Plan(ctx, names, opts)
+	if err != nil {
+		return nil, err
+	}
+	if plan.HasConflicts() && !opts.SkipConflicts {
+		return nil, ErrConflicts
+	}
+
+	return c.Apply(ctx, plan, opts)
+}
+
+// Upgrade installs new versions of the given tools. All installed tools are
+// upgraded if no names are given.
+func (c *Client) Upgrade(ctx context.Context, names []string, opts InstallOptions) (*Result, error) {
+	installedTools, err := c.Status(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find installed tools: %s", err)
+	}
+	for _, name := range names {
+		if !installedTools.Contains(name) {
+			return nil, fmt.Errorf("tool %s is not installed", name)
+		}
+	}
+	if len(names) == 0 {
+		names = installedTools.GetNames()
+	}
+
+	return c.Install(ctx, names, opts)
+}
+
+// Apply installs all tools of a plan which are missing or outdated. The
+// result contains an entry for every processed tool. A FailedError is
+// returned if any tool failed.
+func (c *Client) Apply(ctx context.Context, plan *Plan, opts InstallOptions) (*Result, error) {
+	if len(opts.PathToTarMappings) > 0 {
+		logging.Debugf("Using path-to-tar-mappings for installation: %+v", opts.PathToTarMappings)
+	}
+
+	c.configuration.AssertWritableTarget()
+	c.configuration.AssertLibDirectory()
+	summary := newToolSummary(c.emitter)
+
+	err := c.runHooks(ctx, HookPreInstall, plan.Tools.GetNames()...)
+	if err != nil {
+		return summary.result, fmt.Errorf("unable to run pre-install hooks: %s", err)
+	}
+	var postHookTools tool.Tools
+	for _, plannedTool := range plan.Tools.Tools {
+
+		if plannedTool.Status.VersionMatches && !opts.Reinstall {
+			continue
+		}
+		if ctx.Err() != nil {
+			summary.Skipped(plannedTool, "the operation was cancelled")
+			continue
+		}
+		if c.failFast && summary.result.Count(ResultFailed) > 0 {
+			summary.Skipped(plannedTool, "a previous tool failed (--fail-fast)")
+			continue
+		}
+		if plannedTool.Status.SkipDueToConflicts {
+			summary.Skipped(plannedTool, "it conflicts with another tool")
+			continue
+		}
+		if opts.SkipDependencies && !plannedTool.Status.IsRequested {
+			summary.Skipped(plannedTool, "it is a dependency (--skip-deps was specified)")
+			continue
+		}
+
+		// Installed tools are not uninstalled before the update. Extraction
+		// renames new files over the old ones so that running processes keep
+		// using the old inode. Files missing from the new version are removed
+		// after the installation succeeded.
+		started := time.Now()
+		isUpdate := plannedTool.IsInstalled()
+		var previousFiles []string
+		var previousBinary os.FileInfo
+		if isUpdate {
+			files, err := c.InstalledFiles(plannedTool.Name)
+			if err != nil {
+				summary.Failed(plannedTool, fmt.Errorf("unable to read installed files: %s", err))
+				continue
+			}
+			previousFiles = files
+
+			previousBinary, err = os.Stat(plannedTool.Binary)
+			if err != nil {
+				logging.Debugf("Unable to stat binary %s: %s", plannedTool.Binary, err)
+				previousBinary = nil
+			}
+
+			err = c.PrintUpdateMessage(c.output, plannedTool.Name)
+			if err != nil {
+				logging.Warning.Printfln("Unable to print tool update: %s", err)
+			}
+		}
+
+		if !opts.SkipDependencies {
+			err := c.checkRuntimeDependencies(&plan.Tools, plannedTool)
+			if err != nil {
+				summary.Failed(plannedTool, err)
+				continue
+			}
+		}
+
+		// Extract relative to prefix
+		// so that unpacking can ignore the target directory
+		dir, err := c.installDirectory()
+		if err != nil {
+			return summary.result, err
+		}
+		logging.Debugf("Install directory: %s", dir)
+
+		var digest string
+		var progressReader tui.ProgressReader
+		if c.emitter.EmitsProgress() {
+			progressReader = c.emitter.NewProgressReader(plannedTool.Name, plannedTool.Version, func(d string) { digest = d })
+		} else {
+			progressReader = common.CreateProgressReader(fmt.Sprintf("%s %s", plannedTool.Name, plannedTool.Version), c.configuration.Debug || c.configuration.Trace)
+			progressReader.OnDigest(func(d string) { digest = d })
+		}
+		startedEvent := events.Event{
+			Type:       events.TypeStarted,
+			Tool:       plannedTool.Name,
+			Version:    plannedTool.Version,
+			OldVersion: plannedTool.Status.Version,
+		}
+		if progressReader.IsQuiet() {
+			startedEvent.Message = fmt.Sprintf("Installing %s %s", plannedTool.Name, plannedTool.Version)
+		}
+		c.emitter.Emit(startedEvent)
+
+		// Extraction is aborted when the context is cancelled or the timeout
+		// is exceeded. Partially installed files are removed below.
+		toolCtx, cancel := config.WithTimeout(ctx, c.configuration.InstallTimeout)
+		var installedFiles []string
+		extractReport := archive.NewExtractReport()
+		extractOptions := c.configuration.GetExtractOptions(extractReport)
+		extractOptions.Directory = dir
+		installTool := func(plannedTool tool.Tool, layer io.ReadCloser) error {
+			installedFiles, err = plannedTool.Install(c.output, myos.NewContextReader(toolCtx, layer), c.configuration.PathRewriteRules, c.createPatchFileCallback(plannedTool, dir), extractOptions)
+			if err != nil {
+				// Files of the previous version were already replaced but are
+				// still listed in its manifest and must be kept
+				partialFiles := installedFiles
+				if isUpdate {
+					partialFiles = findStaleFiles(installedFiles, previousFiles)
+				}
+				logging.Warning.Printfln("Removing partial installation of %s", plannedTool.Name)
+				uninstallErr := c.uninstallFiles(partialFiles)
+				if uninstallErr != nil {
+					logging.Warning.Printfln("Unable to remove partial installation: %s", uninstallErr)
+				}
+				return fmt.Errorf("unable to install files: %s", err)
+			}
+
+			return nil
+		}
+		pathToTar, ok := opts.PathToTarMappings[plannedTool.Name]
+		if ok {
+			logging.Debugf("Using tar file mappings for installation")
+			var fileInfo os.FileInfo
+			var layer io.ReadCloser
+			if fileInfo, err = os.Stat(pathToTar); os.IsNotExist(err) {
+				cancel()
+				return summary.result, fmt.Errorf("tar file %s does not exist", pathToTar)
+			}
+			layer, err = os.Open(pathToTar) // #nosec G304 -- Location supplied by user
+			if err != nil {
+				cancel()
+				return summary.result, fmt.Errorf("unable to read tar file %s: %s", pathToTar, err)
+			}
+			//nolint:errcheck
+			defer layer.Close()
+
+			progressReader.SetTotal(fileInfo.Size())
+			progressReader.SetReader(layer)
+			err = installTool(plannedTool, progressReader)
+
+		} else {
+			logging.Debugf("Using default behaviour for installation")
+			err = c.installToolFromImage(toolCtx, plannedTool, progressReader, installTool)
+		}
+		if err != nil && errors.Is(toolCtx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("timed out after %d second(s): %s", c.configuration.InstallTimeout, err)
+		}
+		cancel()
+		if err != nil {
+			summary.Failed(plannedTool, err)
+			continue
+		}
+
+		for _, issue := range extractReport.Issues {
+			logging.Warning.Printfln("%s: %s", plannedTool.Name, issue)
+		}
+		extractedEvent := events.Event{
+			Type:    events.TypeExtracted,
+			Tool:    plannedTool.Name,
+			Version: plannedTool.Version,
+			Digest:  digest,
+			Files:   len(installedFiles),
+			Message: fmt.Sprintf("Extracted %d file(s) for %s", len(installedFiles), plannedTool.Name),
+		}
+		extractedEvent.SetDuration(time.Since(started))
+		c.emitter.Emit(extractedEvent)
+
+		if isUpdate {
+			err = c.uninstallFiles(findStaleFiles(previousFiles, installedFiles))
+			if err != nil {
+				logging.Warning.Printfln("Unable to remove files of previous version of %s: %s", plannedTool.Name, err)
+			}
+			err = c.removeMarkerFiles(plannedTool.Name)
+			if err != nil {
+				logging.Warning.Printfln("Unable to remove marker files of previous version of %s: %s", plannedTool.Name, err)
+			}
+			if previousBinary != nil {
+				reportProcessesRunningPreviousBinary(plannedTool.Name, previousBinary)
+			}
+		}
+
+		installedFiles = append(installedFiles, c.configuration.RunPostInstallIntegrations(ctx, plannedTool, installedFiles)...)
+
+		logging.Debugf("Installed files: %d", len(installedFiles))
+		logging.Tracef("Installed files: %v", installedFiles)
+		err = c.writeManifests(plannedTool, installedFiles)
+		if err != nil {
+			summary.Failed(plannedTool, err)
+			continue
+		}
+
+		err = plannedTool.CreateMarkerFile(c.configuration.GetCacheDirectory())
+		if err != nil {
+			summary.Failed(plannedTool, fmt.Errorf("unable to create marker file: %s", err))
+			continue
+		}
+
+		c.applySystemdPolicy(plannedTool.Name, previousFiles, installedFiles, isUpdate)
+		summary.Succeeded(plannedTool, digest, time.Since(started))
+
+		err = c.PrintUsageMessage(c.output, plannedTool.Name)
+		if err != nil {
+			logging.Warning.Printfln("Unable to print tool usage: %s", err)
+		}
+
+		postHookTools.Tools = append(postHookTools.Tools, plannedTool)
+	}
+
+	err = c.InstallProfileDShim()
+	if err != nil {
+		return summary.result, fmt.Errorf("unable to install profile.d shim: %s", err)
+	}
+
+	// Hooks of installed tools must run even if the operation was cancelled
+	if len(postHookTools.Tools) > 0 {
+		err = c.runHooks(context.WithoutCancel(ctx), HookPostInstall, postHookTools.GetNames()...)
+		if err != nil {
+			return summary.result, fmt.Errorf("unable to run post-install hooks: %s", err)
+		}
+	}
+
+	if ctx.Err() != nil {
+		return summary.result, fmt.Errorf("installation was interrupted: %w", ctx.Err())
+	}
+
+	return summary.result, summary.result.Err()
+}
+
+func (c *Client) installToolFromImage(ctx context.Context, plannedTool tool.Tool, progressReader tui.ProgressReader, installTool func(plannedTool tool.Tool, layer io.ReadCloser) error) error {
+	registries, repositories := plannedTool.GetSourcesWithFallback(constants.Registry, constants.ImageRepository)
+	ref, err := containers.FindToolRef(ctx, registries, repositories, plannedTool.Name, "latest")
+	if err != nil {
+		return fmt.Errorf("error finding tool %s:%s: %s", plannedTool.Name, plannedTool.Version, err)
+	}
+
+	logging.Debugf("Getting image %s", ref)
+	err = c.cache.Get(ctx, ref, progressReader, func(reader io.ReadCloser) error { return nil })
+	if err != nil {
+		return fmt.Errorf("unable to get image: %s", err)
+	}
+	err = c.cache.Get(ctx, ref, progressReader, func(reader io.ReadCloser) error {
+		return installTool(plannedTool, reader)
+	})
+	if err != nil {
+		return fmt.Errorf("unable to install from image: %s", err)
+	}
+
+	return nil
+}
+
+func (c *Client) writeManifests(plannedTool tool.Tool, installedFiles []string) error {
+	err := c.writeInstalledFiles(plannedTool.Name, installedFiles)
+	if err != nil {
+		return fmt.Errorf("unable to write installed files: %s", err)
+	}
+
+	plannedToolJson, err := json.MarshalIndent(plannedTool, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to marshal tool: %s", err)
+	}
+	manifestFilename := c.manifestFile(plannedTool.Name, ".json")
+	err = os.WriteFile(manifestFilename, []byte(plannedToolJson), 0644) // #nosec G306 -- File must be world-readable
+	if err != nil {
+		return fmt.Errorf("unable to write manifest file: %s", err)
+	}
+
+	return nil
+}
+
+func (c *Client) checkRuntimeDependencies(plannedTools *tool.Tools, plannedTool tool.Tool) error {
+	for _, depName := range plannedTool.RuntimeDependencies {
+		dep, err := plannedTools.GetByName(depName)
+		if err != nil {
+			return fmt.Errorf("unable to find dependency %s", depName)
+		}
+
+		err = dep.GetBinaryStatus()
+		if err != nil {
+			return fmt.Errorf("unable to get binary status of dependency %s: %s", depName, err)
+		}
+		err = dep.GetMarkerFileStatus(c.configuration.GetCacheDirectory())
+		if err != nil {
+			return fmt.Errorf("unable to get marker file status of dependency %s: %s", depName, err)
+		}
+		err = dep.GetVersionStatus()
+		if err != nil {
+			return fmt.Errorf("unable to get version status of dependency %s: %s", depName, err)
+		}
+
+		if dep.Status.BinaryPresent || dep.Status.MarkerFilePresent {
+			continue
+		}
+		return fmt.Errorf("dependency %s is missing", depName)
+	}
+
+	return nil
+}
+
+func findStaleFiles(previousFiles []string, installedFiles []string) []string {
+	staleFiles := []string{}
+	for _, file := range previousFiles {
+		if len(file) > 0 && !slices.Contains(installedFiles, file) {
+			staleFiles = append(staleFiles, file)
+		}
+	}
+
+	return staleFiles
+}
+
+func reportProcessesRunningPreviousBinary(toolName string, previousBinary os.FileInfo) {
+	pids, err := myos.FindProcessesRunningFile(previousBinary)
+	if err != nil {
+		logging.Debugf("Unable to find processes running previous binary of %s: %s", toolName, err)
+		return
+	}
+	if len(pids) == 0 {
+		return
+	}
+
+	pidStrings := make([]string, 0, len(pids))
+	for _, pid := range pids {
+		pidStrings = append(pidStrings, strconv.Itoa(pid))
+	}
+	logging.Warning.Printfln("%s: Processes still running the previous version (PID %s)", toolName, strings.Join(pidStrings, ", "))
+}
+
+func (c *Client) createPatchFileCallback(tool tool.Tool, installDir string) func(path string) string {
+	var patchFile = func(templatePath string) string {
+		if strings.HasSuffix(templatePath, ".go-template") {
+		} else {
+			logging.Debugf("Skipping file %s. Will not patch.", templatePath)
+			return templatePath
+		}
+
+		fullTemplatePath := filepath.Join(installDir, templatePath)
+
+		values := make(map[string]any)
+		values["Target"] = c.configuration.Target
+		values["RelativeTarget"] = c.configuration.Target
+		values["Prefix"] = c.configuration.Prefix
+		values["Name"] = tool.Name
+		values["Version"] = tool.Version
+
+		if c.configuration.User {
+			values["Target"] = c.configuration.Prefix + "/" + c.configuration.Target
+		} else {
+			values["Target"] = "/" + c.configuration.Target
+		}
+
+		if len(tool.RuntimeDependencies) > 0 {
+			for _, depName := range tool.RuntimeDependencies {
+				depTool, err := c.tools.GetByName(depName)
+				if err != nil {
+					logging.Warning.Printfln("Unable to find dependency %s: %s", depName, err)
+				}
+				camelCaseDepName := depTool.GetCamelCaseName()
+				values[fmt.Sprintf("%sVersion", camelCaseDepName)] = depTool.Version
+			}
+		}
+		logging.Debugf("Patching file %s with values: %+v", fullTemplatePath, values)
+
+		filePath := strings.TrimSuffix(fullTemplatePath, ".go-template")
+		logging.Debugf("Patching file %s <- %s", filePath, fullTemplatePath)
+		logging.Debugf("values = %v", values)
+
+		templathPathInfo, err := os.Stat(fullTemplatePath)
+		if err != nil {
+			logging.Error.Printfln("Unable to get file info: %s", err)
+			return templatePath
+		}
+
+		file, err := os.Create(filePath) // #nosec G304 -- File path was checked before callback
+		if err != nil {
+			logging.Error.Printfln("Unable to create file: %s", err)
+			return templatePath
+		}
+		defer func() {
+			err := file.Close()
+			if err != nil {
+				logging.Warning.Printfln("Unable to close file: %s", err)
+			}
+		}()
+		if stat, ok := templathPathInfo.Sys().(*syscall.Stat_t); ok {
+			err = file.Chown(int(stat.Uid), int(stat.Gid))
+			if err != nil {
+				logging.Error.Printfln("Unable to set file ownership: %s", err)
+				return templatePath
+			}
+		}
+		err = file.Chmod(templathPathInfo.Mode())
+		if err != nil {
+			logging.Error.Printfln("Unable to set file permissions: %s", err)
+			return templatePath
+		}
+
+		tmpl, err := template.ParseFiles(fullTemplatePath)
+		if err != nil {
+			logging.Error.Printfln("Unable to parse template file: %s", err)
+			return templatePath
+		}
+		err = tmpl.Execute(file, values)
+		if err != nil {
+			logging.Error.Printfln("Unable to execute template: %s", err)
+			return templatePath
+		}
+
+		err = os.Remove(fullTemplatePath)
+		if err != nil {
+			logging.Error.Printfln("Unable to remove template file: %s", err)
+			return templatePath
+		}
+
+		return filePath
+	}
+
+	return patchFile
+}
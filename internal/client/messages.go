@@ -0,0 +1,78 @@
+package client
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+
+	"github.com/pterm/pterm"
+
+	"gitlab.com/uniget-org/cli/pkg/tool"
+)
+
+func (c *Client) templateVariables(t *tool.Tool) map[string]any {
+	values := make(map[string]any)
+	values["Target"] = fmt.Sprintf("%s/%s", c.configuration.Prefix, c.configuration.Target)
+	values["Name"] = t.Name
+	values["Version"] = t.Version
+
+	return values
+}
+
+func (c *Client) printMessage(w io.Writer, toolName string, title string, color pterm.Color, render func(t *tool.Tool) string) error {
+	t, err := c.tools.GetByName(toolName)
+	if err != nil {
+		return fmt.Errorf("failed to get tool: %s", err)
+	}
+
+	output := render(t)
+	if output == "" {
+		return nil
+	}
+
+	prefix := pterm.NewStyle(pterm.FgWhite, color, pterm.Bold)
+	suffix := pterm.NewStyle(pterm.FgWhite)
+	prefix.Println()
+	prefix.Print(" " + title + " ")
+	suffix.Printfln(" for %s:", t.Name)
+	tmpl, err := template.New(title).Parse(output)
+	if err != nil {
+		return fmt.Errorf("failed to parse template: %s", err)
+	}
+	err = tmpl.Execute(w, c.templateVariables(t))
+	if err != nil {
+		return fmt.Errorf("failed to execute template: %s", err)
+	}
+
+	return nil
+}
+
+// PrintInternalsMessage writes the internals message of a tool to w
+func (c *Client) PrintInternalsMessage(w io.Writer, toolName string) error {
+	return c.printMessage(w, toolName, "Internals", pterm.BgBlue, func(t *tool.Tool) string {
+		if t.Messages.Internals == "" {
+			return ""
+		}
+		return t.ShowInternals(2)
+	})
+}
+
+// PrintUsageMessage writes the usage message of a tool to w
+func (c *Client) PrintUsageMessage(w io.Writer, toolName string) error {
+	return c.printMessage(w, toolName, "Usage", pterm.BgGreen, func(t *tool.Tool) string {
+		if t.Messages.Usage == "" {
+			return ""
+		}
+		return t.ShowUsage(2)
+	})
+}
+
+// PrintUpdateMessage writes the update message of a tool to w
+func (c *Client) PrintUpdateMessage(w io.Writer, toolName string) error {
+	return c.printMessage(w, toolName, "Update", pterm.BgYellow, func(t *tool.Tool) string {
+		if t.Messages.Update == "" {
+			return ""
+		}
+		return t.ShowUpdate(2)
+	})
+}
@@ -0,0 +1,126 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"gitlab.com/uniget-org/cli/pkg/events"
+	"gitlab.com/uniget-org/cli/pkg/logging"
+	"gitlab.com/uniget-org/cli/pkg/tool"
+)
+
+// InstallOptions control how tools are planned and installed
+type InstallOptions struct {
+	// Reinstall installs tools even if the version matches
+	Reinstall bool
+	// SkipDependencies only installs the requested tools
+	SkipDependencies bool
+	// SkipConflicts skips conflicting tools instead of failing
+	SkipConflicts bool
+	// PathToTarMappings installs tools from local tar files instead of
+	// images (for debugging purposes)
+	PathToTarMappings map[string]string
+}
+
+// Plan contains the tools in installation order with their status
+type Plan struct {
+	Tools                   tool.Tools
+	ConflictsWithInstalled  tool.Tools
+	ConflictsBetweenPlanned tool.Tools
+}
+
+func (p *Plan) HasConflicts() bool {
+	return len(p.ConflictsWithInstalled.Tools) > 0 || len(p.ConflictsBetweenPlanned.Tools) > 0
+}
+
+// Plan resolves the dependencies of the requested tools and populates the
+// status of all planned tools in installation order
+func (c *Client) Plan(ctx context.Context, names []string, opts InstallOptions) (*Plan, error) {
+	plan := &Plan{}
+
+	// Add dependencies of requested tools
+	// Set installation order
+	for _, name := range names {
+		err := c.tools.ResolveDependencies(&plan.Tools, name)
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve dependencies for %s: %s", name, err)
+		}
+	}
+	for _, name := range names {
+		t, err := plan.Tools.GetByName(name)
+		if err != nil {
+			return nil, fmt.Errorf("unable to find %s in planned tools", name)
+		}
+		t.Status.IsRequested = true
+	}
+	c.emitter.Emit(events.Event{
+		Type:    events.TypePlanComputed,
+		Tools:   plan.Tools.GetNames(),
+		Message: fmt.Sprintf("Planned %d tool(s)", len(plan.Tools.Tools)),
+	})
+
+	renamedTools := make(map[string]string, 0)
+	removedTools := make(map[string]string, 0)
+	for _, plannedTool := range plan.Tools.Tools {
+		if len(plannedTool.Lifecycle.RenamedTo) > 0 {
+			renamedTools[plannedTool.Name] = plannedTool.Lifecycle.RenamedTo
+
+		} else if len(plannedTool.Lifecycle.RemovedWithReason) > 0 {
+			removedTools[plannedTool.Name] = plannedTool.Lifecycle.RemovedWithReason
+		}
+	}
+	for oldName, newName := range renamedTools {
+		logging.Warning.Printfln("%s was renamed. Please uninstall %s and install %s manually and try again.",
+			oldName, oldName, newName)
+	}
+	for oldName, reason := range removedTools {
+		logging.Warning.Printfln("%s was removed: %s. Please uninstall %s manually and try again.",
+			oldName, reason, oldName)
+	}
+	if len(renamedTools) > 0 || len(removedTools) > 0 {
+		return nil, ErrRenamedOrRemoved
+	}
+
+	// Populate status of planned tools
+	for index, t := range plan.Tools.Tools {
+		if opts.SkipDependencies && !t.Status.IsRequested {
+			continue
+		}
+		err := ctx.Err()
+		if err != nil {
+			return nil, err
+		}
+
+		logging.Debugf("Getting status for requested tool %s", t.Name)
+		err = c.updateStatus(&plan.Tools.Tools[index])
+		if err != nil {
+			return nil, fmt.Errorf("failed to update status for tool %s: %s", t.Name, err)
+		}
+
+		logging.Tracef("Tool %s: %+v", t.Name, plan.Tools.Tools[index])
+	}
+
+	// Check for conflicts
+	for index, t := range plan.Tools.Tools {
+		if t.Status.BinaryPresent || len(t.ConflictsWith) == 0 {
+			continue
+		}
+		for _, conflict := range t.ConflictsWith {
+			conflictTool, err := plan.Tools.GetByName(conflict)
+			if err != nil {
+				continue
+			}
+			if conflictTool.Status.BinaryPresent {
+				plan.ConflictsWithInstalled.Tools = append(plan.ConflictsWithInstalled.Tools, t)
+			} else {
+				plan.ConflictsBetweenPlanned.Tools = append(plan.ConflictsBetweenPlanned.Tools, t)
+			}
+
+			if opts.SkipConflicts {
+				plan.Tools.Tools[index].Status.SkipDueToConflicts = true
+			}
+		}
+	}
+
+	return plan, nil
+}
@@ -0,0 +1,134 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gitlab.com/uniget-org/cli/pkg/events"
+	"gitlab.com/uniget-org/cli/pkg/tool"
+)
+
+const (
+	ResultSucceeded = "succeeded"
+	ResultSkipped   = "skipped"
+	ResultFailed    = "failed"
+)
+
+var (
+	ErrConflicts        = errors.New("conflicts detected")
+	ErrRenamedOrRemoved = errors.New("renamed or removed tools require your attention")
+)
+
+// ToolResult is the outcome of processing a single tool
+type ToolResult struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Result  string `json:"result"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// Result collects the outcome of all tools processed by an operation
+type Result struct {
+	Tools []ToolResult `json:"tools"`
+}
+
+func (r *Result) Count(result string) int {
+	count := 0
+	for _, t := range r.Tools {
+		if t.Result == result {
+			count++
+		}
+	}
+	return count
+}
+
+// Err returns a FailedError if at least one tool failed
+func (r *Result) Err() error {
+	failed := r.Count(ResultFailed)
+	if failed == 0 {
+		return nil
+	}
+
+	return &FailedError{
+		Failed:    failed,
+		Succeeded: r.Count(ResultSucceeded),
+	}
+}
+
+// FailedError is returned if some or all tools of an operation failed
+type FailedError struct {
+	Failed    int
+	Succeeded int
+}
+
+func (e *FailedError) Error() string {
+	if e.Succeeded == 0 {
+		return fmt.Sprintf("%d tool(s) failed", e.Failed)
+	}
+	return fmt.Sprintf("%d of %d tool(s) failed", e.Failed, e.Failed+e.Succeeded)
+}
+
+// toolSummary records results and emits the matching events
+type toolSummary struct {
+	result  *Result
+	emitter *events.Emitter
+}
+
+func newToolSummary(emitter *events.Emitter) *toolSummary {
+	return &toolSummary{
+		result: &Result{
+			Tools: make([]ToolResult, 0),
+		},
+		emitter: emitter,
+	}
+}
+
+func (s *toolSummary) add(t tool.Tool, result string, reason string) {
+	s.result.Tools = append(s.result.Tools, ToolResult{
+		Name:    t.Name,
+		Version: t.Version,
+		Result:  result,
+		Reason:  reason,
+	})
+}
+
+func (s *toolSummary) Succeeded(t tool.Tool, digest string, duration time.Duration) {
+	s.add(t, ResultSucceeded, "")
+
+	event := events.Event{
+		Type:       events.TypeSucceeded,
+		Tool:       t.Name,
+		Version:    t.Version,
+		OldVersion: t.Status.Version,
+		Digest:     digest,
+		Message:    fmt.Sprintf("%s %s", t.Name, t.Version),
+	}
+	event.SetDuration(duration)
+	s.emitter.Emit(event)
+}
+
+func (s *toolSummary) Skipped(t tool.Tool, reason string) {
+	s.add(t, ResultSkipped, reason)
+
+	s.emitter.Emit(events.Event{
+		Type:    events.TypeSkipped,
+		Tool:    t.Name,
+		Version: t.Version,
+		Reason:  reason,
+		Message: fmt.Sprintf("Skipping %s because %s", t.Name, reason),
+	})
+}
+
+func (s *toolSummary) Failed(t tool.Tool, err error) {
+	s.add(t, ResultFailed, err.Error())
+
+	s.emitter.Emit(events.Event{
+		Type:       events.TypeFailed,
+		Tool:       t.Name,
+		Version:    t.Version,
+		OldVersion: t.Status.Version,
+		Error:      err.Error(),
+		Message:    fmt.Sprintf("Unable to install %s: %s", t.Name, err),
+	})
+}
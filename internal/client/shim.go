@@ -0,0 +1,71 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"gitlab.com/uniget-org/cli/pkg/logging"
+	myos "gitlab.com/uniget-org/cli/pkg/os"
+)
+
+var profileDShim = `
+SCRIPTS="$( find "${target}/etc/profile.d" -type f )"
+for SCRIPT in ${SCRIPTS}; do
+	source "${SCRIPT}"
+done
+`
+
+// InstallProfileDShim updates the script in profile.d which sources the
+// profile.d scripts of installed tools
+func (c *Client) InstallProfileDShim() error {
+	profileDShimFile := c.configuration.GetProfileDDirectory() + "/uniget-profile.d.sh"
+	profileDScript := strings.ReplaceAll(profileDShim, "${target}", "/"+c.configuration.Target)
+
+	if c.configuration.User {
+		profileDShimFile = c.configuration.GetProfileDDirectory() + "/uniget-profile.d.sh"
+		profileDScript = strings.ReplaceAll(profileDShim, "${target}", c.configuration.Prefix+"/"+c.configuration.Target)
+	}
+
+	if myos.FileExists(profileDShimFile) {
+		file, err := os.ReadFile(profileDShimFile) // #nosec G304 -- Filename constructed from configuration
+		if err != nil {
+			return fmt.Errorf("cannot read profile.d shim: %w", err)
+		}
+
+		h := sha256.New()
+		_, err = h.Write(file)
+		if err != nil {
+			return fmt.Errorf("cannot hash profile.d shim: %w", err)
+		}
+		fileSha256 := hex.EncodeToString(h.Sum(nil))
+
+		h = sha256.New()
+		_, err = h.Write([]byte(profileDScript))
+		if err != nil {
+			return fmt.Errorf("cannot hash profile.d shim: %w", err)
+		}
+		profileDScriptSha256 := hex.EncodeToString(h.Sum(nil))
+
+		if fileSha256 == profileDScriptSha256 {
+			logging.Info.Printfln("Profile.d shim is up to date")
+			return nil
+		}
+
+		logging.Info.Printfln("Installing shim for profile.d in %s", profileDShimFile)
+		if myos.DirectoryIsWritable(profileDShimFile) {
+			err := os.WriteFile(
+				profileDShimFile,
+				[]byte(profileDScript),
+				0644,
+			) // #nosec G306 -- File must be world-readable
+			if err != nil {
+				return fmt.Errorf("cannot write profile.d shim: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
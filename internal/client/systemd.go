@@ -0,0 +1,116 @@
+package client
+
+import (
+	"slices"
+
+	"gitlab.com/uniget-org/cli/pkg/logging"
+	"gitlab.com/uniget-org/cli/pkg/systemd"
+)
+
+// getSystemctl returns nil if units must not be managed, e.g. when
+// installing into a chroot or if systemd is not running
+func (c *Client) getSystemctl() *systemd.Systemctl {
+	if !c.configuration.IsIntegrationEnabled("systemd") {
+		return nil
+	}
+	if !c.configuration.User && c.configuration.Prefix != "/" {
+		logging.Debugf("Not managing units when installing to prefix %s", c.configuration.Prefix)
+		return nil
+	}
+
+	systemctl := systemd.NewSystemctl(c.configuration.User)
+	if !systemctl.IsAvailable() {
+		logging.Debugf("systemd is not available")
+		return nil
+	}
+	return systemctl
+}
+
+func startableUnits(units []string) []string {
+	return slices.DeleteFunc(slices.Clone(units), systemd.IsTemplateUnit)
+}
+
+// applySystemdPolicy reloads systemd after the units of a tool were changed
+// and enables, starts or restarts them according to the policy of the tool
+func (c *Client) applySystemdPolicy(toolName string, previousFiles []string, installedFiles []string, isUpdate bool) {
+	units := systemd.FindUnits(installedFiles)
+	if len(units) == 0 && len(systemd.FindUnits(previousFiles)) == 0 {
+		return
+	}
+
+	systemctl := c.getSystemctl()
+	if systemctl == nil {
+		return
+	}
+
+	err := systemctl.DaemonReload()
+	if err != nil {
+		logging.Warning.Printfln("Unable to reload systemd: %s", err)
+		return
+	}
+
+	policies, err := systemd.LoadPolicies(c.configuration.GetSystemdPoliciesFile())
+	if err != nil {
+		logging.Warning.Printfln("Unable to load systemd policies: %s", err)
+		return
+	}
+	policy := policies[toolName]
+	units = startableUnits(units)
+	if len(units) == 0 {
+		return
+	}
+
+	if policy.Enable {
+		err = systemctl.Enable(units...)
+		if err != nil {
+			logging.Warning.Printfln("Unable to enable units of %s: %s", toolName, err)
+		}
+	}
+	if isUpdate && policy.RestartOnUpgrade {
+		err = systemctl.TryRestart(units...)
+		if err != nil {
+			logging.Warning.Printfln("Unable to restart units of %s: %s", toolName, err)
+		}
+	}
+	if policy.Start {
+		err = systemctl.Start(units...)
+		if err != nil {
+			logging.Warning.Printfln("Unable to start units of %s: %s", toolName, err)
+		}
+	}
+}
+
+// stopSystemdUnits stops and disables the units of a tool before
+// its files are removed
+func (c *Client) stopSystemdUnits(toolName string, installedFiles []string) bool {
+	units := systemd.FindUnits(installedFiles)
+	if len(units) == 0 {
+		return false
+	}
+
+	systemctl := c.getSystemctl()
+	if systemctl == nil {
+		return false
+	}
+
+	units = startableUnits(units)
+	if len(units) > 0 {
+		err := systemctl.DisableNow(units...)
+		if err != nil {
+			logging.Warning.Printfln("Unable to stop units of %s: %s", toolName, err)
+		}
+	}
+	return true
+}
+
+func (c *Client) reloadSystemd() {
+	systemctl := c.getSystemctl()
+	if systemctl == nil {
+		return
+	}
+
+	err := systemctl.DaemonReload()
+	if err != nil {
+		logging.Warning.Printfln("Unable to reload systemd: %s", err)
+	}
+}
@@ -0,0 +1,262 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pterm/pterm"
+
+	"gitlab.com/uniget-org/cli/pkg/events"
+	"gitlab.com/uniget-org/cli/pkg/history"
+	"gitlab.com/uniget-org/cli/pkg/logging"
+	myos "gitlab.com/uniget-org/cli/pkg/os"
+)
+
+// UninstallOptions control how tools are uninstalled
+type UninstallOptions struct {
+	// Force removes tools even if they do not appear to be installed
+	Force bool
+}
+
+// Uninstall runs the uninstall hooks and removes the given tools. It stops
+// at the first tool which cannot be removed.
+func (c *Client) Uninstall(ctx context.Context, names []string, opts UninstallOptions) (*Result, error) {
+	c.configuration.AssertWritableTarget()
+	c.configuration.AssertLibDirectory()
+	summary := newToolSummary(c.emitter)
+
+	err := c.runHooks(ctx, HookPreUninstall, names...)
+	if err != nil {
+		return summary.result, fmt.Errorf("unable to run pre-uninstall hooks: %s", err)
+	}
+
+	for _, toolName := range names {
+		err := ctx.Err()
+		if err != nil {
+			return summary.result, err
+		}
+
+		tool, err := c.tools.GetByName(toolName)
+		if err != nil {
+			return summary.result, fmt.Errorf("unable to find tool %s: %s", toolName, err)
+		}
+
+		err = c.updateStatus(tool)
+		if err != nil {
+			return summary.result, fmt.Errorf("failed to update status for tool %s: %s", tool.Name, err)
+		}
+
+		if !opts.Force && !tool.IsInstalled() {
+			logging.Warning.Printfln("Tool %s is not installed", toolName)
+			summary.add(*tool, ResultSkipped, "it is not installed")
+			continue
+		}
+
+		started := time.Now()
+		var uninstallSpinner *pterm.SpinnerPrinter
+		startedEvent := events.Event{
+			Type:    events.TypeStarted,
+			Tool:    tool.Name,
+			Version: tool.Status.Version,
+			Reason:  history.ActionUninstall,
+		}
+		uninstallMessage := fmt.Sprintf("Uninstalling %s", tool.Name)
+		if c.configuration.LogLevel == "warning" && !c.emitter.IsJSON() {
+			uninstallSpinner, _ = pterm.DefaultSpinner.Start(uninstallMessage)
+		} else {
+			startedEvent.Message = uninstallMessage
+		}
+		c.emitter.Emit(startedEvent)
+
+		err = c.uninstallTool(toolName)
+		if err != nil {
+			if uninstallSpinner != nil {
+				uninstallSpinner.Fail()
+			}
+			c.emitter.Emit(events.Event{
+				Type:    events.TypeFailed,
+				Tool:    tool.Name,
+				Version: tool.Status.Version,
+				Reason:  history.ActionUninstall,
+				Error:   err.Error(),
+			})
+			summary.add(*tool, ResultFailed, err.Error())
+			return summary.result, fmt.Errorf("unable to uninstall tool %s: %s", toolName, err)
+		}
+
+		if uninstallSpinner != nil {
+			uninstallSpinner.Success()
+		}
+		succeededEvent := events.Event{
+			Type:    events.TypeSucceeded,
+			Tool:    tool.Name,
+			Version: tool.Status.Version,
+			Reason:  history.ActionUninstall,
+		}
+		succeededEvent.SetDuration(time.Since(started))
+		c.emitter.Emit(succeededEvent)
+		summary.add(*tool, ResultSucceeded, "")
+	}
+
+	err = c.runHooks(ctx, HookPostUninstall, names...)
+	if err != nil {
+		return summary.result, fmt.Errorf("unable to run post-uninstall hooks: %s", err)
+	}
+
+	return summary.result, nil
+}
+
+func (c *Client) uninstallTool(toolName string) error {
+	tool, err := c.tools.GetByName(toolName)
+	if err != nil {
+		return fmt.Errorf("unable to find tool %s: %s", toolName, err)
+	}
+
+	installedFiles, err := c.InstalledFiles(tool.Name)
+	if err != nil {
+		return fmt.Errorf("unable to read installed files: %s", err)
+	}
+	if installedFiles != nil {
+		unitsStopped := c.stopSystemdUnits(tool.Name, installedFiles)
+
+		err = c.uninstallFiles(installedFiles)
+		if err != nil {
+			return fmt.Errorf("unable to uninstall files: %s", err)
+		}
+
+		if unitsStopped {
+			c.reloadSystemd()
+		}
+
+	} else {
+		logging.Warning.Printfln("Unable to find manifest for %s", tool.Name)
+	}
+
+	err = c.removeMarkerFiles(tool.Name)
+	if err != nil {
+		return fmt.Errorf("unable to remove marker files: %s", err)
+	}
+
+	for _, manifestFile := range []string{c.manifestFile(tool.Name, ".json"), c.manifestFile(tool.Name, ".txt")} {
+		if myos.FileExists(manifestFile) {
+			err = os.Remove(manifestFile)
+			if err != nil {
+				return fmt.Errorf("unable to remove %s: %s", manifestFile, err)
+			}
+		}
+	}
+
+	err = tool.RemoveMarkerFile(c.configuration.GetCacheDirectory())
+	if os.IsNotExist(err) {
+		logging.Debugf("unable to remove marker file because it does not exist")
+	} else if err != nil {
+		return fmt.Errorf("unable to remove marker file: %s", err)
+	}
+
+	return nil
+}
+
+// InstalledFiles returns the files recorded in the manifest of a tool or
+// nil if the tool has no manifest
+func (c *Client) InstalledFiles(toolName string) ([]string, error) {
+	fileListFilename := c.manifestFile(toolName, ".txt")
+	logging.Tracef("Looking for manifest file for tool %s at %s", toolName, fileListFilename)
+	if !myos.FileExists(fileListFilename) {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(fileListFilename) // #nosec G304 -- Path is built from configuration
+	if err != nil {
+		return nil, fmt.Errorf("unable to read file %s: %s", fileListFilename, err)
+	}
+
+	return strings.Split(string(data), "\n"), nil
+}
+
+func (c *Client) writeInstalledFiles(toolName string, installedFiles []string) error {
+	fileListDirectory := c.configuration.GetLibDirectory() + "/manifests"
+	fileListFilename := c.manifestFile(toolName, ".txt")
+	err := os.MkdirAll(fileListDirectory, 0755) // #nosec G301 -- Directory must be accessible by all users
+	if err != nil {
+		return fmt.Errorf("unable to create directory %s: %s", fileListDirectory, err)
+	}
+
+	err = os.WriteFile(fileListFilename, []byte(strings.Join(installedFiles, "\n")), 0644) // #nosec G306 -- File must be world-readable
+	if err != nil {
+		return fmt.Errorf("unable to open %s: %s", fileListFilename, err)
+	}
+
+	return nil
+}
+
+func (c *Client) removeMarkerFiles(toolName string) error {
+	markerDirectory := c.configuration.GetCacheDirectory() + "/" + toolName
+	if myos.DirectoryExists(markerDirectory) {
+		entries, err := os.ReadDir(markerDirectory)
+		if err != nil {
+			return fmt.Errorf("failed to read cache directory for %s: %s", toolName, err)
+		}
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err != nil {
+				return fmt.Errorf("unable to get info for %s: %s", entry.Name(), err)
+			}
+
+			err = os.Remove(markerDirectory + "/" + info.Name())
+			if err != nil {
+				return fmt.Errorf("unable to remove %s: %s", info.Name(), err)
+			}
+
+			if myos.IsDirectoryEmpty(markerDirectory) {
+				err = os.Remove(markerDirectory)
+				if err != nil {
+					return fmt.Errorf("unable to remove empty directory %s: %s", markerDirectory, err)
+				}
+				logging.Debugf("Removed empty directory %s", markerDirectory)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) uninstallFiles(installedFiles []string) error {
+	logging.Debugf("Working relative to parent directory %s", c.configuration.Prefix)
+	root, err := os.OpenRoot(c.configuration.Prefix)
+	if err != nil {
+		return fmt.Errorf("unable to open root directory %s: %s", c.configuration.Prefix, err)
+	}
+	//nolint:errcheck
+	defer root.Close()
+
+	for _, file := range installedFiles {
+		logging.Debugf("processing %s", file)
+
+		if file == "" {
+			continue
+		}
+
+		if strings.HasPrefix(file, "/") {
+			if !strings.HasPrefix(file, c.configuration.Prefix+"/"+c.configuration.Target) {
+				logging.Warning.Printfln("Skipping %s because it is not safe to remove", file)
+				continue
+			}
+		}
+
+		_, err = root.Lstat(file) // #nosec G703 - Path is checked for correct prefix
+		if err != nil {
+			logging.Debugf("Unable to stat %s: %s", file, err)
+			continue
+		}
+
+		err = root.Remove(file) // #nosec G703 - Path is checked for correct prefix
+		if err != nil {
+			return fmt.Errorf("unable to remove %s: %s", file, err)
+		}
+	}
+
+	return nil
+}
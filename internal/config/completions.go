@@ -0,0 +1,104 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"gitlab.com/uniget-org/cli/pkg/logging"
+	"gitlab.com/uniget-org/cli/pkg/tool"
+)
+
+const completionTimeout = 10 * time.Second
+
+var completionShells = []string{"bash", "zsh", "fish"}
+
+func completionFilename(shell string, name string) string {
+	switch shell {
+	case "zsh":
+		return "_" + name
+	case "fish":
+		return name + ".fish"
+	}
+	return name
+}
+
+// looksLikeCompletion protects against tools which print their usage
+// instead of failing for an unknown completion command
+func looksLikeCompletion(shell string, output []byte) bool {
+	switch shell {
+	case "zsh":
+		return bytes.Contains(output, []byte("#compdef"))
+	default:
+		return bytes.Contains(output, []byte("complete "))
+	}
+}
+
+func hasCompletion(installedFiles []string, directory string, filename string) bool {
+	for _, file := range installedFiles {
+		if filepath.Dir(file) == directory && filepath.Base(file) == filename {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateCompletionFiles runs "<binary> completion <shell>" for every shell
+// which the tool does not ship completions for. It returns the generated
+// files relative to the prefix.
+func (c *Config) GenerateCompletionFiles(ctx context.Context, plannedTool tool.Tool, installedFiles []string) []string {
+	generatedFiles := make([]string, 0)
+	if plannedTool.Binary == "false" || len(plannedTool.Binary) == 0 {
+		return generatedFiles
+	}
+	name := filepath.Base(plannedTool.Binary)
+
+	root, err := os.OpenRoot(c.Prefix)
+	if err != nil {
+		logging.Warning.Printfln("Unable to open root directory %s: %s", c.Prefix, err)
+		return generatedFiles
+	}
+	//nolint:errcheck
+	defer root.Close()
+
+	for _, shell := range completionShells {
+		directory := c.GetCompletionDirectory(shell)
+		if hasCompletion(installedFiles, directory, completionFilename(shell, name)) {
+			logging.Debugf("%s ships completion for %s", plannedTool.Name, shell)
+			continue
+		}
+
+		completionCtx, cancel := context.WithTimeout(ctx, completionTimeout)
+		cmd := exec.CommandContext(completionCtx, plannedTool.Binary, "completion", shell) // #nosec G204 -- Binary of installed tool
+		cmd.Env = append(os.Environ(), "NO_COLOR=1")
+		output, err := cmd.Output()
+		cancel()
+		if err != nil {
+			logging.Debugf("Unable to generate %s completion for %s: %s", shell, plannedTool.Name, err)
+			continue
+		}
+		if !looksLikeCompletion(shell, output) {
+			logging.Debugf("Output of %s completion %s does not look like a completion", plannedTool.Name, shell)
+			continue
+		}
+
+		err = root.MkdirAll(directory, 0755) // #nosec G301 -- Directory must be accessible by all users
+		if err != nil {
+			logging.Warning.Printfln("Unable to create directory %s: %s", directory, err)
+			continue
+		}
+		completionFile := directory + "/" + completionFilename(shell, name)
+		err = root.WriteFile(completionFile, output, 0644) // #nosec G306 -- File must be world-readable
+		if err != nil {
+			logging.Warning.Printfln("Unable to write %s completion for %s: %s", shell, plannedTool.Name, err)
+			continue
+		}
+		logging.Debugf("Generated %s completion for %s in %s", shell, plannedTool.Name, completionFile)
+		generatedFiles = append(generatedFiles, completionFile)
+	}
+
+	return generatedFiles
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"context"
+	"os"
+	"slices"
+	"testing"
+
+	"gitlab.com/uniget-org/cli/pkg/tool"
+)
+
+func TestGenerateCompletions(t *testing.T) {
+	prefix := t.TempDir()
+	c := NewDefaultConfig(WithPrefix(prefix))
+	c.SetGlobalConfig()
+
+	binary := prefix + "/foo"
+	script := `#!/bin/sh
+case "$2" in
+	bash) echo "complete -F _foo foo" ;;
+	zsh) echo "#compdef foo" ;;
+	*) echo "usage: foo" ;;
+esac
+`
+	err := os.WriteFile(binary, []byte(script), 0700) // #nosec G306 -- Test binary
+	if err != nil {
+		t.Fatalf("failed to write test binary: %v", err)
+	}
+
+	installedFiles := []string{"usr/local/share/zsh/site-functions/_foo"}
+	generatedFiles := c.GenerateCompletionFiles(context.Background(), tool.Tool{Name: "foo", Binary: binary}, installedFiles)
+
+	expected := []string{"usr/local/share/bash-completion/completions/foo"}
+	if !slices.Equal(generatedFiles, expected) {
+		t.Fatalf("expected %v, got %v", expected, generatedFiles)
+	}
+	data, err := os.ReadFile(prefix + "/" + expected[0])
+	if err != nil {
+		t.Fatalf("failed to read generated completion: %v", err)
+	}
+	if string(data) != "complete -F _foo foo\n" {
+		t.Errorf("unexpected completion: %q", string(data))
+	}
+}
+
+func TestHasCompletion(t *testing.T) {
+	directory := "usr/local/share/bash-completion/completions"
+	tests := []struct {
+		file     string
+		expected bool
+	}{
+		{file: directory + "/foo", expected: true},
+		{file: directory + "/foobar", expected: false},
+		{file: directory + "/_foo", expected: false},
+		{file: "usr/local/share/zsh/site-functions/foo", expected: false},
+	}
+	for _, tt := range tests {
+		if hasCompletion([]string{tt.file}, directory, "foo") != tt.expected {
+			t.Errorf("expected %v for %s", tt.expected, tt.file)
+		}
+	}
+}
+
+func TestGenerateCompletionsCancelled(t *testing.T) {
+	prefix := t.TempDir()
+	c := NewDefaultConfig(WithPrefix(prefix))
+	c.SetGlobalConfig()
+
+	binary := prefix + "/foo"
+	err := os.WriteFile(binary, []byte("#!/bin/sh\necho \"complete -F _foo foo\"\n"), 0700) // #nosec G306 -- Test binary
+	if err != nil {
+		t.Fatalf("failed to write test binary: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	generatedFiles := c.GenerateCompletionFiles(ctx, tool.Tool{Name: "foo", Binary: binary}, []string{})
+	if len(generatedFiles) != 0 {
+		t.Errorf("expected no completions after cancellation, got %v", generatedFiles)
+	}
+}
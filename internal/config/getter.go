@@ -1,6 +1,13 @@
 package config
 
-import "gitlab.com/uniget-org/cli/internal/constants"
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gitlab.com/uniget-org/cli/internal/constants"
+	"gitlab.com/uniget-org/cli/pkg/logging"
+)
 
 func (c *Config) GetCacheDirectory() string {
 	return c.Prefix + "/" + c.CacheRoot + "/" + constants.ProjectName
@@ -18,6 +25,76 @@ func (c *Config) GetProfileDDirectory() string {
 	return c.Prefix + "/" + c.ConfigRoot + "/profile.d"
 }
 
+// GetDataDirectory returns the directory for shared data relative to the prefix
+func (c *Config) GetDataDirectory() string {
+	if c.User {
+		return userDataRoot(os.Getenv("HOME"), os.Getenv("XDG_DATA_HOME"))
+	}
+	return strings.Trim(c.Target, "/") + "/share"
+}
+
+// userDataRoot returns XDG_DATA_HOME relative to HOME. The default is used
+// if XDG_DATA_HOME is not set or outside of HOME because files are
+// installed below the prefix.
+func userDataRoot(home string, dataHome string) string {
+	const defaultDataRoot = ".local/share"
+
+	if len(dataHome) == 0 || !filepath.IsAbs(dataHome) {
+		return defaultDataRoot
+	}
+	dataRoot, err := filepath.Rel(home, dataHome)
+	if err != nil || dataRoot == "." || dataRoot == ".." || strings.HasPrefix(dataRoot, "../") {
+		logging.Debugf("Ignoring XDG_DATA_HOME %s outside of %s", dataHome, home)
+		return defaultDataRoot
+	}
+	return dataRoot
+}
+
+// GetCompletionDirectory returns the directory relative to the prefix
+// where the given shell looks for completions
+func (c *Config) GetCompletionDirectory(shell string) string {
+	switch shell {
+	case "bash":
+		return c.GetDataDirectory() + "/bash-completion/completions"
+	case "zsh":
+		return c.GetDataDirectory() + "/zsh/site-functions"
+	case "fish":
+		if c.User {
+			return c.ConfigRoot + "/fish/completions"
+		}
+		return c.GetDataDirectory() + "/fish/vendor_completions.d"
+	}
+	return ""
+}
+
+func (c *Config) GetManDirectory() string {
+	return c.GetDataDirectory() + "/man"
+}
+
+func (c *Config) GetSystemdPoliciesFile() string {
+	return c.GetConfigDirectory() + "/" + constants.SystemdPoliciesFileName
+}
+
+func (c *Config) GetHistoryFile() string {
+	return c.GetLibDirectory() + "/" + constants.HistoryFileName
+}
+
+func (c *Config) GetMcpAuditFile() string {
+	return c.GetLibDirectory() + "/" + constants.McpAuditFileName
+}
+
+func (c *Config) GetPreviousMetadataFile() string {
+	return c.GetCacheDirectory() + "/" + constants.PreviousMetadataFileName
+}
+
+func (c *Config) GetMetadataRevisionFile() string {
+	return c.GetConfigDirectory() + "/" + constants.MetadataRevisionFileName
+}
+
+func (c *Config) GetRegistriesFile() string {
+	return c.GetConfigDirectory() + "/" + constants.RegistriesFileName
+}
+
 func (c *Config) GetMetadataFile() string {
 	return c.GetCacheDirectory() + "/" + constants.MetadataFileName
 }
@@ -0,0 +1,24 @@
+package config
+
+import "testing"
+
+func TestUserDataRoot(t *testing.T) {
+	tests := []struct {
+		dataHome string
+		expected string
+	}{
+		{dataHome: "", expected: ".local/share"},
+		{dataHome: "/home/foo/.data", expected: ".data"},
+		{dataHome: "/home/foo/.local/share/", expected: ".local/share"},
+		{dataHome: "/home/foo", expected: ".local/share"},
+		{dataHome: "/home/foobar/.data", expected: ".local/share"},
+		{dataHome: "/srv/data", expected: ".local/share"},
+		{dataHome: "relative/data", expected: ".local/share"},
+	}
+	for _, tt := range tests {
+		dataRoot := userDataRoot("/home/foo", tt.dataHome)
+		if dataRoot != tt.expected {
+			t.Errorf("expected %s for %q, got %s", tt.expected, tt.dataHome, dataRoot)
+		}
+	}
+}
@@ -3,11 +3,21 @@ package config
 import (
 	"strings"
 
+	"gitlab.com/uniget-org/cli/pkg/archive"
 	"gitlab.com/uniget-org/cli/pkg/tool"
 )
 
+func (c *Config) applyIntegrateAll() {
+	if c.IntegrateAll {
+		c.IntegrateProfileD = true
+		c.IntegrateEtc = true
+		c.IntegrateCompletion = true
+	}
+}
+
 func (c *Config) setDefaultPathRewriteRules() {
-	rules := []tool.PathRewrite{
+	rules := c.getIntegrationPathRewriteRules(true)
+	rules = append(rules, []tool.PathRewrite{
 		{
 			Source:    "usr/local/",
 			Target:    "",
@@ -25,7 +35,7 @@ func (c *Config) setDefaultPathRewriteRules() {
 			Operation: "REPLACE",
 			Abort:     true,
 		},
-	}
+	}...)
 
 	if len(c.Target) > 0 {
 		targetPath := c.Target
@@ -41,3 +51,12 @@ func (c *Config) setDefaultPathRewriteRules() {
 
 	c.PathRewriteRules = rules
 }
+
+func (c *Config) GetExtractOptions(report *archive.ExtractReport) archive.ExtractOptions {
+	return archive.ExtractOptions{
+		PreserveOwnership:    c.PreserveOwnership,
+		PreserveXattrs:       c.PreserveXattrs,
+		PreserveCapabilities: c.PreserveCapabilities,
+		Report:               report,
+	}
+}
@@ -0,0 +1,336 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"gitlab.com/uniget-org/cli/internal/constants"
+	"gitlab.com/uniget-org/cli/pkg/logging"
+	"gitlab.com/uniget-org/cli/pkg/tool"
+)
+
+const (
+	ScopeGlobal = "global"
+	ScopeUser   = "user"
+)
+
+type Integration struct {
+	Name        string
+	Description string
+	Scopes      []string
+	// Default integrations are enabled unless disabled explicitly
+	Default bool
+	// Rules are matched against paths in the image before the target
+	// is prepended if BeforeTarget is set
+	BeforeTarget bool
+	Rules        func(c *Config) []tool.PathRewrite
+	// PostInstall is called after the files of a tool were extracted
+	// and returns additional files to be tracked in the manifest
+	PostInstall func(ctx context.Context, c *Config, t tool.Tool, installedFiles []string) []string
+}
+
+func (i Integration) SupportsScope(scope string) bool {
+	return slices.Contains(i.Scopes, scope)
+}
+
+var Integrations = []Integration{
+	{
+		Name:        "systemd",
+		Description: "Install systemd units where systemd looks for them",
+		Scopes:      []string{ScopeGlobal, ScopeUser},
+		Default:     true,
+		Rules: func(c *Config) []tool.PathRewrite {
+			if c.User {
+				return []tool.PathRewrite{
+					{
+						Source:    "etc/systemd/user/",
+						Target:    ".config/systemd/user/",
+						Operation: "REPLACE",
+						Abort:     true,
+					},
+				}
+			}
+			return []tool.PathRewrite{
+				{
+					Source:    "etc/systemd/",
+					Target:    "/etc/systemd/",
+					Operation: "REPLACE",
+				},
+			}
+		},
+	},
+	{
+		Name:        "docker-cli-plugins",
+		Description: "Install Docker CLI plugins in ~/.docker/cli-plugins",
+		Scopes:      []string{ScopeUser},
+		Default:     true,
+		Rules: func(c *Config) []tool.PathRewrite {
+			return []tool.PathRewrite{
+				{
+					Source:    "libexec/docker/cli-plugins/",
+					Target:    ".docker/cli-plugins/",
+					Operation: "REPLACE",
+					Abort:     true,
+				},
+			}
+		},
+	},
+	{
+		Name:        "terminfo",
+		Description: "Install terminfo entries in ~/.terminfo",
+		Scopes:      []string{ScopeUser},
+		Default:     true,
+		Rules: func(c *Config) []tool.PathRewrite {
+			return []tool.PathRewrite{
+				{
+					Source:    ".local/share/terminfo/",
+					Target:    ".terminfo/",
+					Operation: "REPLACE",
+					Abort:     true,
+				},
+			}
+		},
+	},
+	{
+		Name:        "profile.d",
+		Description: "Install profile.d scripts where login shells source them",
+		Scopes:      []string{ScopeGlobal, ScopeUser},
+		Rules: func(c *Config) []tool.PathRewrite {
+			if c.User {
+				return []tool.PathRewrite{
+					{
+						Source:    "etc/profile.d/",
+						Target:    ".config/profile.d/",
+						Operation: "REPLACE",
+						Abort:     true,
+					},
+				}
+			}
+			return []tool.PathRewrite{
+				{
+					Source:    "etc/profile.d/",
+					Target:    "/etc/profile.d/",
+					Operation: "REPLACE",
+				},
+			}
+		},
+	},
+	{
+		Name:        "etc",
+		Description: "Install configuration files from /etc in ~/.config",
+		Scopes:      []string{ScopeUser},
+		Rules: func(c *Config) []tool.PathRewrite {
+			return []tool.PathRewrite{
+				{
+					Source:    "etc/",
+					Target:    ".config/",
+					Operation: "REPLACE",
+					Abort:     true,
+				},
+			}
+		},
+	},
+	{
+		Name:         "completions",
+		Description:  "Install shell completions where bash, zsh and fish look for them",
+		Scopes:       []string{ScopeGlobal, ScopeUser},
+		BeforeTarget: true,
+		Rules: func(c *Config) []tool.PathRewrite {
+			return replaceRules([][2]string{
+				{"usr/local/share/bash-completion/completions/", c.GetCompletionDirectory("bash")},
+				{"usr/local/etc/bash_completion.d/", c.GetCompletionDirectory("bash")},
+				{"etc/bash_completion.d/", c.GetCompletionDirectory("bash")},
+				{"usr/local/share/zsh/site-functions/", c.GetCompletionDirectory("zsh")},
+				{"usr/local/share/fish/vendor_completions.d/", c.GetCompletionDirectory("fish")},
+			})
+		},
+		PostInstall: func(ctx context.Context, c *Config, t tool.Tool, installedFiles []string) []string {
+			if !c.GenerateCompletions {
+				return nil
+			}
+			return c.GenerateCompletionFiles(ctx, t, installedFiles)
+		},
+	},
+	{
+		Name:         "man",
+		Description:  "Install man pages where man(1) looks for them",
+		Scopes:       []string{ScopeGlobal, ScopeUser},
+		BeforeTarget: true,
+		Rules: func(c *Config) []tool.PathRewrite {
+			return replaceRules([][2]string{
+				{"usr/local/share/man/", c.GetManDirectory()},
+				{"usr/local/man/", c.GetManDirectory()},
+			})
+		},
+	},
+}
+
+func replaceRules(mappings [][2]string) []tool.PathRewrite {
+	rules := make([]tool.PathRewrite, 0, len(mappings))
+	for _, mapping := range mappings {
+		rules = append(rules, tool.PathRewrite{
+			Source:    mapping[0],
+			Target:    mapping[1] + "/",
+			Operation: "REPLACE",
+			Abort:     true,
+		})
+	}
+	return rules
+}
+
+func GetIntegration(name string) (Integration, error) {
+	for _, integration := range Integrations {
+		if integration.Name == name {
+			return integration, nil
+		}
+	}
+	return Integration{}, fmt.Errorf("unknown integration %s", name)
+}
+
+// IntegrationState records integrations which were enabled or disabled
+// explicitly for a scope
+type IntegrationState struct {
+	Enabled  []string `json:"enabled"`
+	Disabled []string `json:"disabled"`
+}
+
+func (s *IntegrationState) Enable(name string) {
+	s.Disabled = slices.DeleteFunc(s.Disabled, func(n string) bool { return n == name })
+	if !slices.Contains(s.Enabled, name) {
+		s.Enabled = append(s.Enabled, name)
+	}
+}
+
+func (s *IntegrationState) Disable(name string) {
+	s.Enabled = slices.DeleteFunc(s.Enabled, func(n string) bool { return n == name })
+	if !slices.Contains(s.Disabled, name) {
+		s.Disabled = append(s.Disabled, name)
+	}
+}
+
+func (c *Config) GetScope() string {
+	if c.User {
+		return ScopeUser
+	}
+	return ScopeGlobal
+}
+
+func (c *Config) GetIntegrationsFile() string {
+	return c.GetConfigDirectory() + "/" + constants.IntegrationsFileName
+}
+
+func (c *Config) LoadIntegrationState() (IntegrationState, error) {
+	state := IntegrationState{
+		Enabled:  make([]string, 0),
+		Disabled: make([]string, 0),
+	}
+
+	data, err := os.ReadFile(c.GetIntegrationsFile())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return state, fmt.Errorf("unable to read %s: %s", c.GetIntegrationsFile(), err)
+	}
+
+	err = json.Unmarshal(data, &state)
+	if err != nil {
+		return state, fmt.Errorf("unable to parse %s: %s", c.GetIntegrationsFile(), err)
+	}
+	return state, nil
+}
+
+func (c *Config) SaveIntegrationState(state IntegrationState) error {
+	err := os.MkdirAll(filepath.Dir(c.GetIntegrationsFile()), 0755) // #nosec G301 -- Directory must be accessible by all users
+	if err != nil {
+		return fmt.Errorf("unable to create directory for %s: %s", c.GetIntegrationsFile(), err)
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to marshal integrations: %s", err)
+	}
+	err = os.WriteFile(c.GetIntegrationsFile(), data, 0644) // #nosec G306 -- File must be world-readable
+	if err != nil {
+		return fmt.Errorf("unable to write %s: %s", c.GetIntegrationsFile(), err)
+	}
+	return nil
+}
+
+// setEnabledIntegrations combines defaults, the persisted state and
+// the integration flags for the current scope
+func (c *Config) setEnabledIntegrations() {
+	state, err := c.LoadIntegrationState()
+	if err != nil {
+		logging.Warning.Printfln("Unable to load integrations: %s", err)
+	}
+
+	requested := make([]string, 0)
+	if c.IntegrateProfileD {
+		requested = append(requested, "profile.d")
+	}
+	if c.IntegrateEtc {
+		requested = append(requested, "etc")
+	}
+	if c.IntegrateCompletion {
+		requested = append(requested, "completions", "man")
+	}
+
+	c.EnabledIntegrations = make([]string, 0)
+	for _, integration := range Integrations {
+		if !integration.SupportsScope(c.GetScope()) {
+			continue
+		}
+
+		enabled := integration.Default && !slices.Contains(state.Disabled, integration.Name)
+		if slices.Contains(state.Enabled, integration.Name) ||
+			slices.Contains(requested, integration.Name) ||
+			c.IntegrateAll {
+			enabled = true
+		}
+		if enabled {
+			c.EnabledIntegrations = append(c.EnabledIntegrations, integration.Name)
+		}
+	}
+}
+
+func (c *Config) IsIntegrationEnabled(name string) bool {
+	return slices.Contains(c.EnabledIntegrations, name)
+}
+
+func (c *Config) getIntegrationPathRewriteRules(beforeTarget bool) []tool.PathRewrite {
+	rules := make([]tool.PathRewrite, 0)
+	for _, integration := range Integrations {
+		if integration.BeforeTarget != beforeTarget || !c.IsIntegrationEnabled(integration.Name) {
+			continue
+		}
+		rules = append(rules, integration.Rules(c)...)
+	}
+	return rules
+}
+
+// ApplyIntegrations determines the enabled integrations and creates the
+// path rewrite rules accordingly
+func (c *Config) ApplyIntegrations() {
+	c.setEnabledIntegrations()
+
+	c.setDefaultPathRewriteRules()
+	c.PathRewriteRules = append(c.PathRewriteRules, c.getIntegrationPathRewriteRules(false)...)
+}
+
+// RunPostInstallIntegrations runs the post-install actions of all enabled
+// integrations and returns additional files to be tracked in the manifest
+func (c *Config) RunPostInstallIntegrations(ctx context.Context, t tool.Tool, installedFiles []string) []string {
+	files := make([]string, 0)
+	for _, integration := range Integrations {
+		if integration.PostInstall == nil || !c.IsIntegrationEnabled(integration.Name) {
+			continue
+		}
+		files = append(files, integration.PostInstall(ctx, c, t, installedFiles)...)
+	}
+	return files
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestIntegrationsDefaults(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	c := NewDefaultConfig()
+	c.User = true
+	c.SetUserConfig()
+
+	expected := []string{"systemd", "docker-cli-plugins", "terminfo"}
+	if !slices.Equal(c.EnabledIntegrations, expected) {
+		t.Errorf("expected %v, got %v", expected, c.EnabledIntegrations)
+	}
+}
+
+func TestIntegrationsState(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	c := NewDefaultConfig()
+	c.User = true
+	c.SetUserConfig()
+
+	state, err := c.LoadIntegrationState()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	state.Disable("terminfo")
+	state.Enable("completions")
+	err = c.SaveIntegrationState(state)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.ApplyIntegrations()
+
+	if c.IsIntegrationEnabled("terminfo") {
+		t.Errorf("expected terminfo to be disabled")
+	}
+	if !c.IsIntegrationEnabled("completions") {
+		t.Errorf("expected completions to be enabled")
+	}
+	if c.PathRewriteRules[0].Source != "usr/local/share/bash-completion/completions/" {
+		t.Errorf("expected completion rules to be applied first, got %v", c.PathRewriteRules[0])
+	}
+	for _, rule := range c.PathRewriteRules {
+		if rule.Source == ".local/share/terminfo/" {
+			t.Errorf("unexpected rule for disabled integration: %v", rule)
+		}
+	}
+}
@@ -50,9 +50,16 @@ func WithIntegrateEtc(integrate bool) ConfigOption {
 	}
 }
 
+func WithIntegrateCompletion(integrate bool) ConfigOption {
+	return func(c *Config) {
+		c.IntegrateCompletion = integrate
+	}
+}
+
 func WithIntegrateAll(integrate bool) ConfigOption {
 	return func(c *Config) {
 		c.IntegrateProfileD = integrate
 		c.IntegrateEtc = integrate
+		c.IntegrateCompletion = integrate
 	}
 }
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"gitlab.com/uniget-org/cli/pkg/containers"
+	"gitlab.com/uniget-org/cli/pkg/logging"
+)
+
+// registryTokensEnv contains bearer tokens as comma-separated list of
+// registry=token. It is not part of Config to keep tokens out of the
+// debug output.
+const registryTokensEnv = "UNIGET_REGISTRYTOKENS"
+
+type RegistriesFile struct {
+	Registries []containers.RegistryCredential `json:"registries"`
+}
+
+func (c *Config) LoadRegistryCredentials() ([]containers.RegistryCredential, error) {
+	credentials := make([]containers.RegistryCredential, 0)
+
+	registriesFile := c.GetRegistriesFile()
+	data, err := os.ReadFile(registriesFile) // #nosec G304 -- Path is derived from the configuration directory
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("unable to read %s: %s", registriesFile, err)
+	}
+	if err == nil {
+		stat, err := os.Stat(registriesFile)
+		if err == nil && stat.Mode().Perm()&0o077 != 0 {
+			logging.Warning.Printfln("%s contains credentials and should only be accessible by its owner (chmod 0600)", registriesFile)
+		}
+
+		var file RegistriesFile
+		err = json.Unmarshal(data, &file)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse %s: %s", registriesFile, err)
+		}
+		credentials = append(credentials, file.Registries...)
+	}
+
+	tokens, err := parseRegistryTokens(os.Getenv(registryTokensEnv))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %s", registryTokensEnv, err)
+	}
+	for _, token := range tokens {
+		found := false
+		for i := range credentials {
+			if credentials[i].Registry == token.Registry {
+				credentials[i].Token = token.Token
+				found = true
+			}
+		}
+		if !found {
+			credentials = append(credentials, token)
+		}
+	}
+
+	return credentials, nil
+}
+
+func parseRegistryTokens(value string) ([]containers.RegistryCredential, error) {
+	credentials := make([]containers.RegistryCredential, 0)
+	for entry := range strings.SplitSeq(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if len(entry) == 0 {
+			continue
+		}
+		registry, token, ok := strings.Cut(entry, "=")
+		if !ok || len(registry) == 0 || len(token) == 0 {
+			return nil, fmt.Errorf("entries must have the format registry=token")
+		}
+		credentials = append(credentials, containers.RegistryCredential{
+			Registry: registry,
+			Token:    token,
+		})
+	}
+	return credentials, nil
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseRegistryTokens(t *testing.T) {
+	tt := []struct {
+		value    string
+		expected map[string]string
+		fails    bool
+	}{
+		{value: "", expected: map[string]string{}},
+		{value: "registry.example.com=abc", expected: map[string]string{"registry.example.com": "abc"}},
+		{value: "a.example.com=abc, b.example.com:5000=def=", expected: map[string]string{"a.example.com": "abc", "b.example.com:5000": "def="}},
+		{value: "registry.example.com", fails: true},
+		{value: "=abc", fails: true},
+		{value: "registry.example.com=", fails: true},
+	}
+
+	for _, tc := range tt {
+		credentials, err := parseRegistryTokens(tc.value)
+		if tc.fails {
+			if err == nil {
+				t.Errorf("expected error for %s", tc.value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error for %s: %v", tc.value, err)
+			continue
+		}
+		if len(credentials) != len(tc.expected) {
+			t.Errorf("expected %d credentials for %s, got %d", len(tc.expected), tc.value, len(credentials))
+			continue
+		}
+		for _, credential := range credentials {
+			if tc.expected[credential.Registry] != credential.Token {
+				t.Errorf("expected token %s for %s, got %s", tc.expected[credential.Registry], credential.Registry, credential.Token)
+			}
+		}
+	}
+}
+
+func TestLoadRegistryCredentials(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("UNIGET_REGISTRYTOKENS", "")
+
+	c := NewDefaultConfig()
+	c.User = true
+	c.SetUserConfig()
+
+	credentials, err := c.LoadRegistryCredentials()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(credentials) != 0 {
+		t.Errorf("expected no credentials, got %d", len(credentials))
+	}
+
+	err = os.MkdirAll(filepath.Dir(c.GetRegistriesFile()), 0700)
+	if err != nil {
+		t.Fatalf("unable to create config directory: %v", err)
+	}
+	err = os.WriteFile(c.GetRegistriesFile(), []byte(`{"registries":[
+		{"registry":"registry.example.com","username":"user","password":"secret"},
+		{"registry":"mirror.example.com","credHelper":"pass","tls":"insecure"}
+	]}`), 0600)
+	if err != nil {
+		t.Fatalf("unable to write registries file: %v", err)
+	}
+	t.Setenv("UNIGET_REGISTRYTOKENS", "mirror.example.com=abc,ghcr.io=def")
+
+	credentials, err = c.LoadRegistryCredentials()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(credentials) != 3 {
+		t.Fatalf("expected 3 credentials, got %d", len(credentials))
+	}
+	if credentials[0].Username != "user" || credentials[0].Password != "secret" {
+		t.Errorf("expected basic auth for %s", credentials[0].Registry)
+	}
+	if credentials[1].CredHelper != "pass" || credentials[1].TLS != "insecure" || credentials[1].Token != "abc" {
+		t.Errorf("expected credential helper, TLS and token for %s", credentials[1].Registry)
+	}
+	if credentials[2].Registry != "ghcr.io" || credentials[2].Token != "def" {
+		t.Errorf("expected token for ghcr.io, got %s for %s", credentials[2].Token, credentials[2].Registry)
+	}
+
+	err = os.WriteFile(c.GetRegistriesFile(), []byte(`{"registries":`), 0600)
+	if err != nil {
+		t.Fatalf("unable to write registries file: %v", err)
+	}
+	_, err = c.LoadRegistryCredentials()
+	if err == nil {
+		t.Errorf("expected error for invalid registries file")
+	}
+}
@@ -0,0 +1,177 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gitlab.com/uniget-org/cli/internal/constants"
+	"gitlab.com/uniget-org/cli/pkg/containers"
+	"gitlab.com/uniget-org/cli/pkg/logging"
+)
+
+const (
+	labelRevision = "org.opencontainers.image.revision"
+	labelCreated  = "org.opencontainers.image.created"
+)
+
+var gitShaPattern = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+// MetadataRevision is a tag of the metadata image together with the git
+// commit and the time it was built from
+type MetadataRevision struct {
+	Tag      string    `json:"tag"`
+	Revision string    `json:"revision"`
+	Created  time.Time `json:"created"`
+}
+
+// GetMetadataImageTag returns the tag of the metadata image to download.
+// It defaults to the moving tag unless a revision was pinned.
+func (c *Config) GetMetadataImageTag() string {
+	if len(c.MetadataRevision) > 0 {
+		return c.MetadataRevision
+	}
+	return constants.MetadataImageTag
+}
+
+func (c *Config) IsMetadataRevisionPinned() bool {
+	return len(c.MetadataRevision) > 0
+}
+
+// loadMetadataRevision reads the pinned revision unless it was set in
+// the environment
+func (c *Config) loadMetadataRevision() {
+	if len(c.MetadataRevision) > 0 {
+		return
+	}
+
+	data, err := os.ReadFile(c.GetMetadataRevisionFile())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logging.Warning.Printfln("Unable to read %s: %s", c.GetMetadataRevisionFile(), err)
+		}
+		return
+	}
+	c.MetadataRevision = strings.TrimSpace(string(data))
+}
+
+// SaveMetadataRevision pins the metadata to the given tag. An empty tag
+// removes the pin.
+func (c *Config) SaveMetadataRevision(tag string) error {
+	c.MetadataRevision = tag
+
+	if len(tag) == 0 {
+		err := os.Remove(c.GetMetadataRevisionFile())
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("unable to remove %s: %s", c.GetMetadataRevisionFile(), err)
+		}
+		return nil
+	}
+
+	err := os.MkdirAll(filepath.Dir(c.GetMetadataRevisionFile()), 0755) // #nosec G301 -- Directory must be accessible by all users
+	if err != nil {
+		return fmt.Errorf("unable to create directory for %s: %s", c.GetMetadataRevisionFile(), err)
+	}
+	err = os.WriteFile(c.GetMetadataRevisionFile(), []byte(tag+"\n"), 0644) // #nosec G306 -- File must be world-readable
+	if err != nil {
+		return fmt.Errorf("unable to write %s: %s", c.GetMetadataRevisionFile(), err)
+	}
+	return nil
+}
+
+// ListMetadataRevisions returns all tags of the metadata image, newest first
+func (c *Config) ListMetadataRevisions(ctx context.Context) ([]MetadataRevision, error) {
+	ctx, cancel := WithTimeout(ctx, c.MetadataTimeout)
+	defer cancel()
+
+	t, err := containers.FindToolRef(ctx, []string{constants.Registry}, []string{constants.ImageRepository}, "metadata", constants.MetadataImageTag)
+	if err != nil {
+		return nil, fmt.Errorf("error finding metadata: %s", err)
+	}
+
+	tags, err := containers.GetImageTags(ctx, t)
+	if err != nil {
+		return nil, fmt.Errorf("error getting tags of metadata image: %s", err)
+	}
+
+	revisions := make([]MetadataRevision, 0, len(tags))
+	for _, tag := range tags {
+		logging.Tracef("Getting labels for metadata:%s", tag)
+		labels, err := containers.GetImageLabels(ctx, containers.NewToolRef(t.Registry, t.Repository, t.Tool, tag))
+		if err != nil {
+			logging.Warning.Printfln("Unable to get labels for metadata:%s: %s", tag, err)
+			continue
+		}
+
+		revision := MetadataRevision{
+			Tag:      tag,
+			Revision: labels[labelRevision],
+		}
+		created, err := time.Parse(time.RFC3339, labels[labelCreated])
+		if err == nil {
+			revision.Created = created
+		}
+		revisions = append(revisions, revision)
+	}
+
+	sort.SliceStable(revisions, func(i, j int) bool {
+		return revisions[i].Created.After(revisions[j].Created)
+	})
+	return revisions, nil
+}
+
+// ResolveMetadataRevision finds a revision by tag, by (abbreviated) git
+// commit or by date. A date selects the newest revision created on or
+// before that day.
+func ResolveMetadataRevision(revisions []MetadataRevision, spec string) (MetadataRevision, error) {
+	for _, revision := range revisions {
+		if revision.Tag == spec {
+			return revision, nil
+		}
+	}
+
+	if gitShaPattern.MatchString(spec) {
+		var matches []MetadataRevision
+		for _, revision := range revisions {
+			if strings.HasPrefix(revision.Revision, spec) {
+				matches = append(matches, revision)
+			}
+		}
+		switch len(matches) {
+		case 0:
+			return MetadataRevision{}, fmt.Errorf("no metadata found for commit %s", spec)
+		case 1:
+			return matches[0], nil
+		default:
+			return MetadataRevision{}, fmt.Errorf("commit %s is ambiguous", spec)
+		}
+	}
+
+	until, err := time.Parse(time.RFC3339, spec)
+	if err != nil {
+		day, err := time.Parse(time.DateOnly, spec)
+		if err != nil {
+			return MetadataRevision{}, fmt.Errorf("revision %s must be a tag, git commit or date", spec)
+		}
+		until = day.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	var selected *MetadataRevision
+	for index, revision := range revisions {
+		if revision.Created.IsZero() || revision.Created.After(until) {
+			continue
+		}
+		if selected == nil || revision.Created.After(selected.Created) {
+			selected = &revisions[index]
+		}
+	}
+	if selected == nil {
+		return MetadataRevision{}, fmt.Errorf("no metadata found before %s", spec)
+	}
+	return *selected, nil
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestResolveMetadataRevision(t *testing.T) {
+	revisions := []MetadataRevision{
+		{Tag: "2024-03-02", Revision: "c3a1f0e9d8b7a6c5b4a3f2e1d0c9b8a7f6e5d4c3", Created: time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC)},
+		{Tag: "2024-03-01", Revision: "b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1", Created: time.Date(2024, 3, 1, 23, 0, 0, 0, time.UTC)},
+		{Tag: "2024-02-20", Revision: "b2c3aaaaf6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1", Created: time.Date(2024, 2, 20, 8, 0, 0, 0, time.UTC)},
+	}
+
+	tt := []struct {
+		spec     string
+		expected string
+		fails    bool
+	}{
+		{spec: "2024-02-20", expected: "2024-02-20"},
+		{spec: "c3a1f0e", expected: "2024-03-02"},
+		{spec: "b2c3d4e5", expected: "2024-03-01"},
+		{spec: "b2c3", fails: true},
+		{spec: "b2c3aaa", expected: "2024-02-20"},
+		{spec: "2024-02-25", expected: "2024-02-20"},
+		{spec: "2024-03-01T12:00:00Z", expected: "2024-02-20"},
+		{spec: "2024-01-01", fails: true},
+		{spec: "fffffff", fails: true},
+		{spec: "yesterday", fails: true},
+	}
+
+	for _, tc := range tt {
+		revision, err := ResolveMetadataRevision(revisions, tc.spec)
+		if tc.fails {
+			if err == nil {
+				t.Errorf("expected error for %s but got %s", tc.spec, revision.Tag)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error for %s: %v", tc.spec, err)
+			continue
+		}
+		if revision.Tag != tc.expected {
+			t.Errorf("expected %s for %s, got %s", tc.expected, tc.spec, revision.Tag)
+		}
+	}
+}
+
+func TestMetadataRevisionPin(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("UNIGET_METADATAREVISION", "")
+
+	c := NewDefaultConfig()
+	c.User = true
+	c.SetUserConfig()
+	if c.GetMetadataImageTag() != "main" {
+		t.Errorf("expected main, got %s", c.GetMetadataImageTag())
+	}
+
+	err := c.SaveMetadataRevision("2024-03-01")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c = NewDefaultConfig()
+	c.User = true
+	c.SetUserConfig()
+	if c.GetMetadataImageTag() != "2024-03-01" {
+		t.Errorf("expected 2024-03-01, got %s", c.GetMetadataImageTag())
+	}
+
+	err = c.SaveMetadataRevision("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c = NewDefaultConfig()
+	c.User = true
+	c.SetUserConfig()
+	if c.IsMetadataRevisionPinned() {
+		t.Errorf("expected revision to be unpinned, got %s", c.MetadataRevision)
+	}
+}
+
+func TestMetadataRevision(t *testing.T) {
+	directory := t.TempDir()
+	tests := []struct {
+		content  string
+		expected string
+	}{
+		{content: `{"revision":"abc123","Tools":[]}`, expected: "abc123"},
+		{content: `{"Tools":[]}`, expected: ""},
+		{content: `not json`, expected: ""},
+	}
+	for _, tt := range tests {
+		filename := directory + "/metadata.json"
+		err := os.WriteFile(filename, []byte(tt.content), 0600)
+		if err != nil {
+			t.Fatalf("failed to write metadata: %v", err)
+		}
+		revision := metadataRevision(filename)
+		if revision != tt.expected {
+			t.Errorf("expected revision %q for %s, got %q", tt.expected, tt.content, revision)
+		}
+	}
+	if revision := metadataRevision(directory + "/missing.json"); revision != "" {
+		t.Errorf("expected empty revision for missing file, got %q", revision)
+	}
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"context"
+	"time"
+
+	"gitlab.com/uniget-org/cli/pkg/containers"
+)
+
+// WithTimeout returns a context which is cancelled after the given number
+// of seconds. A value of zero or less disables the timeout.
+func WithTimeout(ctx context.Context, seconds int) (context.Context, context.CancelFunc) {
+	if seconds <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, time.Duration(seconds)*time.Second)
+}
+
+// ApplyTimeouts passes timeouts to packages which do not receive the
+// configuration
+func (c *Config) ApplyTimeouts() {
+	if c.ManifestTimeout > 0 {
+		containers.ManifestTimeout = time.Duration(c.ManifestTimeout) * time.Second
+	}
+}
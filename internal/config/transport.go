@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"gitlab.com/uniget-org/cli/pkg/containers"
+	"gitlab.com/uniget-org/cli/pkg/transport"
+)
+
+func parseTLSHosts(value string) (map[string]string, error) {
+	hosts := make(map[string]string)
+	for entry := range strings.SplitSeq(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if len(entry) == 0 {
+			continue
+		}
+		host, mode, ok := strings.Cut(entry, "=")
+		if !ok || len(host) == 0 {
+			return nil, fmt.Errorf("entries must have the format host=enabled|insecure|disabled")
+		}
+		if mode != transport.TLSEnabled && mode != transport.TLSInsecure && mode != transport.TLSDisabled {
+			return nil, fmt.Errorf("invalid TLS setting %s for host %s", mode, host)
+		}
+		hosts[host] = mode
+	}
+	return hosts, nil
+}
+
+// parseMirrors returns the mirrors per registry in the order of value
+func parseMirrors(value string) (map[string][]string, error) {
+	mirrors := make(map[string][]string)
+	for entry := range strings.SplitSeq(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if len(entry) == 0 {
+			continue
+		}
+		registry, mirror, ok := strings.Cut(entry, "=")
+		if !ok || len(registry) == 0 || len(mirror) == 0 {
+			return nil, fmt.Errorf("entries must have the format registry=mirror")
+		}
+		mirrors[registry] = append(mirrors[registry], mirror)
+	}
+	return mirrors, nil
+}
+
+// ApplyTransport configures proxies, certificates, TLS settings, retries
+// and registry credentials for all network requests
+func (c *Config) ApplyTransport() error {
+	credentials, err := c.LoadRegistryCredentials()
+	if err != nil {
+		return err
+	}
+
+	mirrors, err := parseMirrors(c.Mirrors)
+	if err != nil {
+		return fmt.Errorf("unable to parse mirrors: %s", err)
+	}
+	registries := slices.Sorted(maps.Keys(mirrors))
+	for _, registry := range registries {
+		index := slices.IndexFunc(credentials, func(credential containers.RegistryCredential) bool {
+			return credential.Registry == registry
+		})
+		if index < 0 {
+			credentials = append(credentials, containers.RegistryCredential{Registry: registry})
+			index = len(credentials) - 1
+		}
+		credentials[index].Mirrors = append(credentials[index].Mirrors, mirrors[registry]...)
+	}
+
+	hosts := make(map[string]string)
+	for _, credential := range credentials {
+		if len(credential.TLS) > 0 {
+			hosts[credential.Registry] = credential.TLS
+		}
+	}
+	tlsHosts, err := parseTLSHosts(c.TLSHosts)
+	if err != nil {
+		return fmt.Errorf("unable to parse TLS hosts: %s", err)
+	}
+	for host, mode := range tlsHosts {
+		hosts[host] = mode
+	}
+
+	err = transport.Configure(transport.Options{
+		CACertificates: c.CACertificates,
+		Hosts:          hosts,
+		Retries:        c.HTTPRetries,
+		RetryDelay:     time.Second,
+	})
+	if err != nil {
+		return err
+	}
+
+	containers.UseDockerCredentials = c.DockerCredentials
+	return containers.SetRegistryCredentials(credentials)
+}
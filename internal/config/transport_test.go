@@ -0,0 +1,76 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTLSHosts(t *testing.T) {
+	tt := []struct {
+		value    string
+		expected map[string]string
+		fails    bool
+	}{
+		{value: "", expected: map[string]string{}},
+		{value: "127.0.0.1:5000=disabled", expected: map[string]string{"127.0.0.1:5000": "disabled"}},
+		{value: "mirror.example.com=insecure, registry.example.com=enabled", expected: map[string]string{"mirror.example.com": "insecure", "registry.example.com": "enabled"}},
+		{value: "mirror.example.com", fails: true},
+		{value: "=insecure", fails: true},
+		{value: "mirror.example.com=maybe", fails: true},
+	}
+
+	for _, tc := range tt {
+		hosts, err := parseTLSHosts(tc.value)
+		if tc.fails {
+			if err == nil {
+				t.Errorf("expected error for %s", tc.value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error for %s: %v", tc.value, err)
+			continue
+		}
+		if len(hosts) != len(tc.expected) {
+			t.Errorf("expected %d hosts for %s, got %d", len(tc.expected), tc.value, len(hosts))
+			continue
+		}
+		for host, mode := range tc.expected {
+			if hosts[host] != mode {
+				t.Errorf("expected %s for %s, got %s", mode, host, hosts[host])
+			}
+		}
+	}
+}
+
+func TestParseMirrors(t *testing.T) {
+	tt := []struct {
+		value    string
+		expected map[string][]string
+		fails    bool
+	}{
+		{value: "", expected: map[string][]string{}},
+		{value: "ghcr.io=cache.example.com:5000", expected: map[string][]string{"ghcr.io": {"cache.example.com:5000"}}},
+		{value: "ghcr.io=cache1.example.com, ghcr.io=cache2.example.com", expected: map[string][]string{"ghcr.io": {"cache1.example.com", "cache2.example.com"}}},
+		{value: "ghcr.io", fails: true},
+		{value: "=cache.example.com", fails: true},
+		{value: "ghcr.io=", fails: true},
+	}
+
+	for _, tc := range tt {
+		mirrors, err := parseMirrors(tc.value)
+		if tc.fails {
+			if err == nil {
+				t.Errorf("expected error for %s", tc.value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error for %s: %v", tc.value, err)
+			continue
+		}
+		if !reflect.DeepEqual(mirrors, tc.expected) {
+			t.Errorf("expected %v for %s, got %v", tc.expected, tc.value, mirrors)
+		}
+	}
+}
@@ -27,7 +27,9 @@ type Config struct {
 	ConfigRoot                  string
 	IntegrateProfileD           bool `env:"UNIGET_INTEGRATEPROFILED"`
 	IntegrateEtc                bool `env:"UNIGET_INTEGRATEETC"`
+	IntegrateCompletion         bool `env:"UNIGET_INTEGRATECOMPLETION"`
 	IntegrateAll                bool `env:"UNIGET_INTEGRATEALL"`
+	EnabledIntegrations         []string
 	PathRewriteRules            []tool.PathRewrite
 	HooksPreInstallDirectory    string
 	HooksPostInstallDirectory   string
@@ -36,6 +38,22 @@ type Config struct {
 	Cache                       string `env:"UNIGET_CACHE"`
 	FileCacheRetention          int    `env:"UNIGET_CACHERETENTION"`
 	FileCacheDirectoryName      string `env:"UNIGET_CACHEDIRECTORY"`
+	PreserveOwnership           bool   `env:"UNIGET_PRESERVEOWNERSHIP"`
+	PreserveXattrs              bool   `env:"UNIGET_PRESERVEXATTRS"`
+	PreserveCapabilities        bool   `env:"UNIGET_PRESERVECAPABILITIES"`
+	HistorySyslog               bool   `env:"UNIGET_HISTORYSYSLOG"`
+	GenerateCompletions         bool   `env:"UNIGET_GENERATECOMPLETIONS"`
+	Channel                     string `env:"UNIGET_CHANNEL"`
+	MetadataRevision            string `env:"UNIGET_METADATAREVISION"`
+	McpAllowMutations           bool   `env:"UNIGET_MCPALLOWMUTATIONS"`
+	MetadataTimeout             int    `env:"UNIGET_METADATATIMEOUT"`
+	ManifestTimeout             int    `env:"UNIGET_MANIFESTTIMEOUT"`
+	InstallTimeout              int    `env:"UNIGET_INSTALLTIMEOUT"`
+	DockerCredentials           bool   `env:"UNIGET_DOCKERCREDENTIALS"`
+	CACertificates              string `env:"UNIGET_CACERTIFICATES"`
+	TLSHosts                    string `env:"UNIGET_TLSHOSTS"`
+	HTTPRetries                 int    `env:"UNIGET_HTTPRETRIES"`
+	Mirrors                     string `env:"UNIGET_MIRRORS"`
 }
 
 func NewDefaultConfig(opts ...ConfigOption) *Config {
@@ -50,10 +68,23 @@ func NewDefaultConfig(opts ...ConfigOption) *Config {
 		Target:                 "usr/local",
 		IntegrateProfileD:      false,
 		IntegrateEtc:           false,
+		IntegrateCompletion:    false,
 		IntegrateAll:           false,
 		Cache:                  "none",
 		FileCacheRetention:     24 * 60 * 60,
 		FileCacheDirectoryName: "downloads",
+		PreserveOwnership:      false,
+		PreserveXattrs:         false,
+		PreserveCapabilities:   false,
+		HistorySyslog:          false,
+		GenerateCompletions:    false,
+		Channel:                "latest",
+		McpAllowMutations:      false,
+		MetadataTimeout:        5 * 60,
+		ManifestTimeout:        60,
+		InstallTimeout:         0,
+		DockerCredentials:      true,
+		HTTPRetries:            3,
 	}
 	for _, opt := range opts {
 		opt(config)
@@ -86,10 +117,7 @@ func NewDefaultConfig(opts ...ConfigOption) *Config {
 		}
 	}
 
-	if config.IntegrateAll {
-		config.IntegrateProfileD = true
-		config.IntegrateEtc = true
-	}
+	config.applyIntegrateAll()
 
 	switch config.AltArch {
 	case "amd64":
@@ -112,20 +140,9 @@ func (c *Config) SetGlobalConfig(opts ...ConfigOption) {
 	for _, opt := range opts {
 		opt(c)
 	}
-
-	c.setDefaultPathRewriteRules()
-	c.PathRewriteRules = append(c.PathRewriteRules, tool.PathRewrite{
-		Source:    "etc/systemd/",
-		Target:    "/etc/systemd/",
-		Operation: "REPLACE",
-	})
-	if c.IntegrateProfileD {
-		c.PathRewriteRules = append(c.PathRewriteRules, tool.PathRewrite{
-			Source:    "etc/profile.d/",
-			Target:    "/etc/profile.d/",
-			Operation: "REPLACE",
-		})
-	}
+	c.applyIntegrateAll()
+	c.ApplyIntegrations()
+	c.loadMetadataRevision()
 }
 
 func (c *Config) SetUserConfig(opts ...ConfigOption) {
@@ -148,44 +165,9 @@ func (c *Config) SetUserConfig(opts ...ConfigOption) {
 	if c.ConfigRoot == "" {
 		c.ConfigRoot = ".config"
 	}
-
-	c.setDefaultPathRewriteRules()
-	c.PathRewriteRules = append(c.PathRewriteRules, []tool.PathRewrite{
-		{
-			Source:    "libexec/docker/cli-plugins/",
-			Target:    ".docker/cli-plugins/",
-			Operation: "REPLACE",
-			Abort:     true,
-		},
-		{
-			Source:    "etc/systemd/user/",
-			Target:    ".config/systemd/user/",
-			Operation: "REPLACE",
-			Abort:     true,
-		},
-		{
-			Source:    ".local/share/terminfo/",
-			Target:    ".terminfo/",
-			Operation: "REPLACE",
-			Abort:     true,
-		},
-	}...)
-	if c.IntegrateProfileD {
-		c.PathRewriteRules = append(c.PathRewriteRules, tool.PathRewrite{
-			Source:    "etc/profile.d/",
-			Target:    ".config/profile.d/",
-			Operation: "REPLACE",
-			Abort:     true,
-		})
-	}
-	if c.IntegrateEtc {
-		c.PathRewriteRules = append(c.PathRewriteRules, tool.PathRewrite{
-			Source:    "etc/",
-			Target:    ".config/",
-			Operation: "REPLACE",
-			Abort:     true,
-		})
-	}
+	c.applyIntegrateAll()
+	c.ApplyIntegrations()
+	c.loadMetadataRevision()
 }
 
 func (c *Config) String() string {
@@ -204,14 +186,33 @@ func (c *Config) String() string {
 		"  ConfigRoot: " + c.ConfigRoot + ", " + "\n" +
 		"  IntegrateProfileD: " + strconv.FormatBool(c.IntegrateProfileD) + ", " + "\n" +
 		"  IntegrateEtc: " + strconv.FormatBool(c.IntegrateEtc) + ", " + "\n" +
+		"  IntegrateCompletion: " + strconv.FormatBool(c.IntegrateCompletion) + ", " + "\n" +
 		"  IntegrateAll: " + strconv.FormatBool(c.IntegrateAll) + ", " + "\n" +
+		"  EnabledIntegrations: " + strings.Join(c.EnabledIntegrations, ",") + ", " + "\n" +
 		"  Cache: " + c.Cache + ", " + "\n" +
 		"  FileCacheRetention: " + strconv.Itoa(c.FileCacheRetention) + ", " + "\n" +
 		"  FileCacheDirectoryName: " + c.FileCacheDirectoryName + "\n" +
+		"  PreserveOwnership: " + strconv.FormatBool(c.PreserveOwnership) + ", " + "\n" +
+		"  PreserveXattrs: " + strconv.FormatBool(c.PreserveXattrs) + ", " + "\n" +
+		"  PreserveCapabilities: " + strconv.FormatBool(c.PreserveCapabilities) + ", " + "\n" +
+		"  HistorySyslog: " + strconv.FormatBool(c.HistorySyslog) + ", " + "\n" +
+		"  GenerateCompletions: " + strconv.FormatBool(c.GenerateCompletions) + ", " + "\n" +
+		"  Channel: " + c.Channel + ", " + "\n" +
+		"  MetadataRevision: " + c.MetadataRevision + ", " + "\n" +
+		"  McpAllowMutations: " + strconv.FormatBool(c.McpAllowMutations) + ", " + "\n" +
+		"  MetadataTimeout: " + strconv.Itoa(c.MetadataTimeout) + ", " + "\n" +
+		"  ManifestTimeout: " + strconv.Itoa(c.ManifestTimeout) + ", " + "\n" +
+		"  InstallTimeout: " + strconv.Itoa(c.InstallTimeout) + ", " + "\n" +
+		"  DockerCredentials: " + strconv.FormatBool(c.DockerCredentials) + ", " + "\n" +
+		"  CACertificates: " + c.CACertificates + ", " + "\n" +
+		"  TLSHosts: " + c.TLSHosts + ", " + "\n" +
+		"  HTTPRetries: " + strconv.Itoa(c.HTTPRetries) + ", " + "\n" +
+		"  Mirrors: " + c.Mirrors + ", " + "\n" +
 		"  CacheDirectory: " + c.GetCacheDirectory() + ", " + "\n" +
 		"  LibDirectory: " + c.GetLibDirectory() + ", " + "\n" +
 		"  ConfigDirectory: " + c.GetConfigDirectory() + ", " + "\n" +
 		"  ProfileDDirectory: " + c.GetProfileDDirectory() + ", " + "\n" +
+		"  DataDirectory: " + c.GetDataDirectory() + ", " + "\n" +
 		"  MetadataFile: " + c.GetMetadataFile() + "\n" +
 		"  HooksPreInstallDirectory: " + c.GetHooksPreInstallDirectory() + ", " + "\n" +
 		"  HooksPostInstallDirectory: " + c.GetHooksPostInstallDirectory() + ", " + "\n" +
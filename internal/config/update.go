@@ -2,6 +2,7 @@ package config
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -13,17 +14,21 @@ import (
 	"gitlab.com/uniget-org/cli/pkg/archive"
 	"gitlab.com/uniget-org/cli/pkg/containers"
 	"gitlab.com/uniget-org/cli/pkg/logging"
+	myos "gitlab.com/uniget-org/cli/pkg/os"
 	"gitlab.com/uniget-org/cli/pkg/security"
 	"gitlab.com/uniget-org/cli/pkg/tool"
 )
 
-func (c *Config) HasMetadataUpdate(revision string) (bool, error) {
-	t, err := containers.FindToolRef([]string{constants.Registry}, []string{constants.ImageRepository}, "metadata", constants.MetadataImageTag)
+func (c *Config) HasMetadataUpdate(ctx context.Context, revision string) (bool, error) {
+	ctx, cancel := WithTimeout(ctx, c.MetadataTimeout)
+	defer cancel()
+
+	t, err := containers.FindToolRef(ctx, []string{constants.Registry}, []string{constants.ImageRepository}, "metadata", c.GetMetadataImageTag())
 	if err != nil {
 		return false, fmt.Errorf("error finding metadata: %s", err)
 	}
 
-	labels, err := containers.GetImageLabels(t)
+	labels, err := containers.GetImageLabels(ctx, t)
 	if err != nil {
 		return false, fmt.Errorf("error getting image labels: %s", err)
 	}
@@ -34,31 +39,119 @@ func (c *Config) HasMetadataUpdate(revision string) (bool, error) {
 	return true, nil
 }
 
-func (c *Config) DownloadMetadata() error {
+// MetadataNeedsDownload returns true if the metadata or its signature is
+// missing or if metadata must be updated automatically
+func (c *Config) MetadataNeedsDownload() bool {
+	return !myos.FileExists(c.GetMetadataFile()) ||
+		c.AutoUpdate ||
+		(len(os.Getenv("UNIGET_IGNORE_METADATA_SIGNATURE")) == 0 &&
+			!myos.FileExists(c.GetMetadataFile()+".sigstore.json"))
+}
+
+func (c *Config) DownloadMetadata(ctx context.Context) error {
 	c.AssertCacheDirectory()
-	t, err := containers.FindToolRef([]string{constants.Registry}, []string{constants.ImageRepository}, "metadata", constants.MetadataImageTag)
+
+	// Download next to the current metadata so that it is only replaced
+	// after a successful download
+	directory, err := os.MkdirTemp(c.GetCacheDirectory(), ".download-*")
 	if err != nil {
-		return fmt.Errorf("error finding metadata: %s", err)
+		return fmt.Errorf("error creating temporary directory: %s", err)
 	}
-	rc := containers.GetRegclient()
 	defer func() {
-		err := rc.Close(context.Background(), t.GetRef())
+		err := os.RemoveAll(directory)
 		if err != nil {
-			logging.Warning.Printfln("error closing registry client: %s", err)
+			logging.Warning.Printfln("Unable to remove %s: %s", directory, err)
 		}
 	}()
 
-	logging.Debugf("Changing directory to %s", c.GetCacheDirectory())
-	err = os.Chdir(c.GetCacheDirectory())
+	err = c.DownloadMetadataRevision(ctx, c.GetMetadataImageTag(), directory)
 	if err != nil {
-		return fmt.Errorf("error changing directory to %s: %s", c.GetCacheDirectory(), err)
+		return err
 	}
 
+	if metadataRevision(c.GetMetadataFile()) != metadataRevision(directory+"/"+constants.MetadataFileName) {
+		err = c.keepPreviousMetadata()
+		if err != nil {
+			logging.Warning.Printfln("Unable to keep previous metadata: %s", err)
+		}
+	}
+
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %s", directory, err)
+	}
+	for _, entry := range entries {
+		err = os.Rename(directory+"/"+entry.Name(), c.GetCacheDirectory()+"/"+entry.Name())
+		if err != nil {
+			return fmt.Errorf("error replacing %s: %s", entry.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// metadataRevision returns the revision of the metadata file or an empty
+// string if the file cannot be read
+func metadataRevision(filename string) string {
+	data, err := os.ReadFile(filename) // #nosec G304 -- Filename is built from configuration
+	if err != nil {
+		return ""
+	}
+	var metadata struct {
+		Revision string `json:"revision"`
+	}
+	err = json.Unmarshal(data, &metadata)
+	if err != nil {
+		return ""
+	}
+	return metadata.Revision
+}
+
+// DownloadMetadataRevision extracts the metadata image with the given tag
+// into directory without touching the current metadata
+func (c *Config) DownloadMetadataRevision(ctx context.Context, tag string, directory string) error {
+	return c.extractMetadata(ctx, tag, directory)
+}
+
+// keepPreviousMetadata copies the metadata and its signature before they
+// are replaced by a different revision so that changes can be reviewed
+// afterwards
+func (c *Config) keepPreviousMetadata() error {
+	if !myos.FileExists(c.GetMetadataFile()) {
+		return nil
+	}
+
+	for _, suffix := range []string{"", ".sigstore.json"} {
+		err := myos.CopyFile(c.GetMetadataFile()+suffix, c.GetPreviousMetadataFile()+suffix)
+		if err != nil {
+			return fmt.Errorf("error copying %s: %s", c.GetMetadataFile()+suffix, err)
+		}
+	}
+	return nil
+}
+
+func (c *Config) extractMetadata(ctx context.Context, tag string, directory string) error {
+	ctx, cancel := WithTimeout(ctx, c.MetadataTimeout)
+	defer cancel()
+
+	t, err := containers.FindToolRef(ctx, []string{constants.Registry}, []string{constants.ImageRepository}, "metadata", tag)
+	if err != nil {
+		return fmt.Errorf("error finding metadata: %s", err)
+	}
+	rc := containers.GetRegclient()
+	defer func() {
+		err := rc.Close(ctx, t.GetRef())
+		if err != nil {
+			logging.Warning.Printfln("error closing registry client: %s", err)
+		}
+	}()
+
 	progressReader := common.CreateProgressReader("Downloading metadata", c.Debug || c.Trace)
-	logging.Debugf("Extracting archive to %s", c.GetCacheDirectory())
-	err = containers.GetFirstLayerFromRegistry(context.Background(), rc, t.GetRef(), progressReader, func(reader io.ReadCloser) error {
+	logging.Debugf("Extracting metadata:%s", tag)
+	err = containers.GetFirstLayerFromRegistry(ctx, rc, t.GetRef(), progressReader, func(reader io.ReadCloser) error {
+		extractTarItem := archive.NewExtractTarItemCallback(archive.ExtractOptions{Directory: directory})
 		err := archive.ProcessTarContents(reader, func(reader *tar.Reader, header *tar.Header) error {
-			err := archive.CallbackExtractTarItem(reader, header)
+			err := extractTarItem(reader, header)
 			if err != nil {
 				return fmt.Errorf("error extracting tar item: %s", err)
 			}
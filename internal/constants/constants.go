@@ -12,7 +12,14 @@ var (
 const (
 	ProjectName                 = "uniget"
 	MetadataFileName            = "metadata.json"
+	PreviousMetadataFileName    = "metadata.previous.json"
 	MetadataImageTag            = "main"
+	HistoryFileName             = "history.jsonl"
+	McpAuditFileName            = "mcp-audit.jsonl"
+	IntegrationsFileName        = "integrations.json"
+	SystemdPoliciesFileName     = "systemd.json"
+	MetadataRevisionFileName    = "metadata-revision"
+	RegistriesFileName          = "registries.json"
 	HooksPreInstallDirectory    = "hooks/pre-install.d"
 	HooksPostInstallDirectory   = "hooks/post-install.d"
 	HooksPreUninstallDirectory  = "hooks/pre-uninstall.d"
@@ -22,4 +29,6 @@ const (
 	ImageRepository             = Organization + "/tools"
 	ToolSeparator               = "/"
 	RegistryImagePrefix         = Registry + "/" + ImageRepository + ToolSeparator
+	ReleaseOIDCIssuer           = "https://gitlab.com"
+	ReleaseSANRegex             = "^https://gitlab\\.com/uniget-org/cli//\\.gitlab-ci\\.yml@refs/tags/v.+$"
 )
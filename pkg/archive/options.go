@@ -0,0 +1,61 @@
+package archive
+
+import (
+	"fmt"
+
+	"gitlab.com/uniget-org/cli/pkg/logging"
+)
+
+const (
+	xattrPAXPrefix  = "SCHILY.xattr."
+	capabilityXattr = "security.capability"
+)
+
+type ExtractOptions struct {
+	// Directory is the target of the extraction (defaults to the working directory)
+	Directory            string
+	PreserveOwnership    bool
+	PreserveXattrs       bool
+	PreserveCapabilities bool
+	Report               *ExtractReport
+}
+
+type ExtractIssue struct {
+	Path      string
+	Attribute string
+	Reason    string
+}
+
+func (i ExtractIssue) String() string {
+	return fmt.Sprintf("%s: %s was not preserved (%s)", i.Path, i.Attribute, i.Reason)
+}
+
+type ExtractReport struct {
+	Issues []ExtractIssue
+}
+
+func NewExtractReport() *ExtractReport {
+	return &ExtractReport{
+		Issues: make([]ExtractIssue, 0),
+	}
+}
+
+func (r *ExtractReport) Add(path string, attribute string, reason string) {
+	issue := ExtractIssue{
+		Path:      path,
+		Attribute: attribute,
+		Reason:    reason,
+	}
+
+	if r == nil {
+		logging.Warning.Println(issue.String())
+		return
+	}
+
+	logging.Debugf("Extract issue: %s", issue)
+	r.Issues = append(r.Issues, issue)
+}
+
+func (r *ExtractReport) HasIssues() bool {
+	return r != nil && len(r.Issues) > 0
+}
@@ -1,16 +1,19 @@
 package archive
 
 import (
+	"crypto/rand"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/google/safearchive/tar"
-	"github.com/google/safeopen"
 
 	"gitlab.com/uniget-org/cli/pkg/logging"
 	myos "gitlab.com/uniget-org/cli/pkg/os"
+	"golang.org/x/sys/unix"
 )
 
 func ProcessTarContents(reader io.ReadCloser, callback func(tar *tar.Reader, header *tar.Header) error) error {
@@ -45,13 +48,20 @@ func CallbackDisplayTarItem(reader *tar.Reader, header *tar.Header) error {
 		}
 		//nolint:errcheck
 		fmt.Fprintf(logging.OutputWriter, "-%s %s\n", mode, header.Name)
-	case tar.TypeSymlink, tar.TypeLink:
+	case tar.TypeSymlink:
 		mode, err := myos.ConvertFileModeToString(header.Mode)
 		if err != nil {
 			return fmt.Errorf("unable to convert mode: %s", err)
 		}
 		//nolint:errcheck
 		fmt.Fprintf(logging.OutputWriter, "l%s %s -> %s\n", mode, header.Name, header.Linkname)
+	case tar.TypeLink:
+		mode, err := myos.ConvertFileModeToString(header.Mode)
+		if err != nil {
+			return fmt.Errorf("unable to convert mode: %s", err)
+		}
+		//nolint:errcheck
+		fmt.Fprintf(logging.OutputWriter, "h%s %s => %s\n", mode, header.Name, header.Linkname)
 	default:
 		//nolint:errcheck
 		fmt.Fprintf(logging.ErrorWriter, "Unknown: %s\n", header.Name)
@@ -60,15 +70,29 @@ func CallbackDisplayTarItem(reader *tar.Reader, header *tar.Header) error {
 }
 
 func CallbackExtractTarItem(reader *tar.Reader, header *tar.Header) error {
+	return ExtractTarItem(reader, header, ExtractOptions{})
+}
+
+func NewExtractTarItemCallback(opts ExtractOptions) func(reader *tar.Reader, header *tar.Header) error {
+	return func(reader *tar.Reader, header *tar.Header) error {
+		return ExtractTarItem(reader, header, opts)
+	}
+}
+
+func ExtractTarItem(reader *tar.Reader, header *tar.Header, opts ExtractOptions) error {
 	if header.Typeflag == tar.TypeDir {
 		return nil
 	}
 
-	workDir, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("error getting working directory")
+	workDir := opts.Directory
+	if len(workDir) == 0 {
+		var err error
+		workDir, err = os.Getwd()
+		if err != nil {
+			return fmt.Errorf("error getting working directory")
+		}
 	}
-	logging.Debugf("Current directory: %s", workDir)
+	logging.Debugf("Extracting into %s", workDir)
 
 	switch header.Typeflag {
 
@@ -77,22 +101,32 @@ func CallbackExtractTarItem(reader *tar.Reader, header *tar.Header) error {
 
 		path := filepath.Clean(header.Name)
 
-		dir := filepath.Dir(path)
+		dir := filepath.Join(workDir, filepath.Dir(path))
 		err := os.MkdirAll(dir, 0755) // #nosec G301 -- Tools must be world readable
 		if err != nil {
 			return fmt.Errorf("ExtractTarGz: MkdirAll() failed for %s: %s", dir, err.Error())
 		}
 
-		err = ExtractFileFromTar(workDir, path, reader, header)
+		err = ExtractFileFromTarWithOptions(workDir, path, reader, header, opts)
 		if err != nil {
 			return fmt.Errorf("ExtractTarGz: ExtractFileFromTar() failed for %s: %s", path, err.Error())
 		}
 
-	case tar.TypeSymlink, tar.TypeLink:
+	case tar.TypeLink:
+		logging.Tracef("Untarring hard link %s => %s", header.Name, header.Linkname)
+
+		err := ExtractHardLinkFromTar(workDir, header)
+		if err != nil {
+			return fmt.Errorf("ExtractTarGz: ExtractHardLinkFromTar() failed for %s => %s: %s", header.Name, header.Linkname, err.Error())
+		}
+
+	case tar.TypeSymlink:
 		logging.Tracef("Untarring (sym)link %s -> %s", header.Name, header.Linkname)
 
+		linkPath := filepath.Join(workDir, header.Name)
+
 		// Check if (sym)link already exists
-		_, err := os.Lstat(header.Name)
+		_, err := os.Lstat(linkPath)
 		if err == nil {
 			logging.Debugf("Symlink %s already exists", header.Name)
 		}
@@ -101,7 +135,7 @@ func CallbackExtractTarItem(reader *tar.Reader, header *tar.Header) error {
 			logging.Debugf("Symlink %s does not exist", header.Name)
 
 			// Create directories for (sym)link
-			dir := filepath.Dir(header.Name)
+			dir := filepath.Dir(linkPath)
 			logging.Tracef("Creating directory %s", dir)
 			err := os.MkdirAll(dir, 0755) // #nosec G301 -- Tools must be world readable
 			if err != nil {
@@ -109,7 +143,7 @@ func CallbackExtractTarItem(reader *tar.Reader, header *tar.Header) error {
 			}
 
 			// Create (sym)link
-			err = os.Symlink(header.Linkname, header.Name)
+			err = os.Symlink(header.Linkname, linkPath)
 			if err != nil {
 				return fmt.Errorf("ExtractTarGz: Symlink() failed for %s -> %s: %s", header.Linkname, header.Name, err.Error())
 			}
@@ -122,33 +156,204 @@ func CallbackExtractTarItem(reader *tar.Reader, header *tar.Header) error {
 }
 
 func ExtractFileFromTar(workDir string, path string, reader *tar.Reader, header *tar.Header) error {
+	return ExtractFileFromTarWithOptions(workDir, path, reader, header, ExtractOptions{})
+}
+
+func ExtractFileFromTarWithOptions(workDir string, path string, reader *tar.Reader, header *tar.Header, opts ExtractOptions) error {
 	err := myos.CreateSubdirectoriesForPath(workDir, path)
 	if err != nil {
 		return fmt.Errorf("ExtractTarGz: CreateSubdirectoriesForPath() failed for %s in %s: %s", path, workDir, err.Error())
 	}
-	outFile, err := safeopen.CreateBeneath(workDir, path)
+
+	root, err := os.OpenRoot(workDir)
+	if err != nil {
+		return fmt.Errorf("ExtractTarGz: OpenRoot() failed for %s: %s", workDir, err.Error())
+	}
+	//nolint:errcheck
+	defer root.Close()
+
+	name, err := pathBeneath(workDir, path)
 	if err != nil {
-		return fmt.Errorf("ExtractTarGz: Create() failed for %s in %s: %s", path, workDir, err.Error())
+		return fmt.Errorf("ExtractTarGz: unable to resolve %s: %s", path, err.Error())
 	}
+
+	// Write to a temporary file in the same directory and rename it over the
+	// existing file afterwards. This replaces binaries of running processes
+	// which cannot be opened for writing (ETXTBSY).
+	tempName := filepath.Join(filepath.Dir(name), "."+filepath.Base(name)+".uniget-"+rand.Text()[:8])
+	outFile, err := root.OpenFile(tempName, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("ExtractTarGz: Create() failed for %s in %s: %s", tempName, workDir, err.Error())
+	}
+	renamed := false
 	defer func() {
-		err := outFile.Close()
-		if err != nil {
-			logging.Warning.Printfln("failed to close file: %s", err)
+		if !renamed {
+			err := root.Remove(tempName)
+			if err != nil && !os.IsNotExist(err) {
+				logging.Warning.Printfln("failed to remove temporary file %s: %s", tempName, err)
+			}
 		}
 	}()
+
+	err = writeFileFromTar(outFile, path, reader, header, opts)
+	closeErr := outFile.Close()
+	if err != nil {
+		return err
+	}
+	if closeErr != nil {
+		return fmt.Errorf("ExtractTarGz: Close() failed for %s: %s", path, closeErr.Error())
+	}
+
+	err = root.Rename(tempName, name)
+	if err != nil {
+		return fmt.Errorf("ExtractTarGz: Rename() failed for %s: %s", path, err.Error())
+	}
+	renamed = true
+
+	return nil
+}
+
+func writeFileFromTar(outFile *os.File, path string, reader *tar.Reader, header *tar.Header, opts ExtractOptions) error {
 	if _, err := io.Copy(outFile, reader); err != nil {
 		return fmt.Errorf("ExtractTarGz: Copy() failed for %s: %s", path, err.Error())
 	}
 
-	mode := os.FileMode(header.Mode) // #nosec G115 -- Must be addressed upstream
-	Setuid := mode &^ 0777
-	if (mode & Setuid) != 0 {
-		logging.Warning.Printfln("Setuid bit cannot be set for %s", path)
+	// Ownership must be changed before applying the mode because
+	// chown(2) clears the setuid and setgid bits
+	ownershipPreserved := false
+	if opts.PreserveOwnership {
+		if os.Geteuid() != 0 {
+			opts.Report.Add(path, "ownership", "changing ownership requires root")
+		} else {
+			err := outFile.Chown(header.Uid, header.Gid)
+			if err != nil {
+				opts.Report.Add(path, "ownership", err.Error())
+			} else {
+				ownershipPreserved = true
+			}
+		}
+	}
+
+	mode := header.FileInfo().Mode()
+	fileMode := mode.Perm()
+	specialBits := mode & (os.ModeSetuid | os.ModeSetgid | os.ModeSticky)
+	if specialBits != 0 {
+		if ownershipPreserved {
+			fileMode |= specialBits
+		} else {
+			opts.Report.Add(path, "setuid/setgid/sticky bits", "special bits are only applied when preserving ownership as root")
+		}
 	}
-	err = outFile.Chmod(mode)
+	err := outFile.Chmod(fileMode)
 	if err != nil {
 		return fmt.Errorf("ExtractTarGz: Chmod() failed for %s: %s", path, err.Error())
 	}
 
+	// Extended attributes are applied last because chown(2) drops file capabilities
+	applyXattrsFromTar(outFile, path, header, opts)
+
 	return nil
 }
+
+func applyXattrsFromTar(file *os.File, path string, header *tar.Header, opts ExtractOptions) {
+	xattrs := make(map[string]string)
+	for key, value := range header.PAXRecords {
+		name, ok := strings.CutPrefix(key, xattrPAXPrefix)
+		if ok {
+			xattrs[name] = value
+		}
+	}
+	names := make([]string, 0, len(xattrs))
+	for name := range xattrs {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		if name == capabilityXattr {
+			if !opts.PreserveCapabilities {
+				opts.Report.Add(path, "file capabilities", "preserving capabilities is disabled")
+				continue
+			}
+			if os.Geteuid() != 0 {
+				opts.Report.Add(path, "file capabilities", "setting capabilities requires root")
+				continue
+			}
+
+		} else if !opts.PreserveXattrs {
+			opts.Report.Add(path, "xattr "+name, "preserving extended attributes is disabled")
+			continue
+		}
+
+		logging.Tracef("Setting xattr %s on %s", name, path)
+		err := unix.Fsetxattr(int(file.Fd()), name, []byte(xattrs[name]), 0) // #nosec G115 -- File descriptors fit into int
+		if err != nil {
+			opts.Report.Add(path, "xattr "+name, err.Error())
+		}
+	}
+}
+
+func ExtractHardLinkFromTar(workDir string, header *tar.Header) error {
+	root, err := os.OpenRoot(workDir)
+	if err != nil {
+		return fmt.Errorf("unable to open root directory %s: %s", workDir, err)
+	}
+	//nolint:errcheck
+	defer root.Close()
+
+	name, err := pathBeneath(workDir, header.Name)
+	if err != nil {
+		return fmt.Errorf("unable to resolve %s: %s", header.Name, err)
+	}
+	target, err := pathBeneath(workDir, header.Linkname)
+	if err != nil {
+		return fmt.Errorf("unable to resolve %s: %s", header.Linkname, err)
+	}
+
+	targetInfo, err := root.Lstat(target)
+	if err != nil {
+		return fmt.Errorf("hard link target %s does not exist: %s", target, err)
+	}
+	if !targetInfo.Mode().IsRegular() {
+		return fmt.Errorf("hard link target %s is not a regular file", target)
+	}
+
+	existingInfo, err := root.Lstat(name)
+	if err == nil {
+		if os.SameFile(existingInfo, targetInfo) {
+			logging.Debugf("Hard link %s already exists", name)
+			return nil
+		}
+
+		logging.Debugf("Replacing %s with hard link to %s", name, target)
+		err = root.Remove(name)
+		if err != nil {
+			return fmt.Errorf("unable to remove %s: %s", name, err)
+		}
+	}
+
+	err = root.MkdirAll(filepath.Dir(name), 0755) // #nosec G301 -- Tools must be world readable
+	if err != nil {
+		return fmt.Errorf("unable to create directory for %s: %s", name, err)
+	}
+
+	err = root.Link(target, name)
+	if err != nil {
+		return fmt.Errorf("unable to create hard link %s => %s: %s", name, target, err)
+	}
+
+	return nil
+}
+
+func pathBeneath(workDir string, path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		return filepath.Clean(path), nil
+	}
+
+	rel, err := filepath.Rel(workDir, path)
+	if err != nil {
+		return "", fmt.Errorf("unable to make %s relative to %s: %s", path, workDir, err)
+	}
+
+	return rel, nil
+}
@@ -1,13 +1,17 @@
 package archive
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"io"
 	"os"
+	"path/filepath"
 	"slices"
 	"testing"
 
 	"github.com/google/safearchive/tar"
+	"golang.org/x/sys/unix"
 
 	"gitlab.com/uniget-org/cli/pkg/containers"
 	"gitlab.com/uniget-org/cli/pkg/tui"
@@ -120,3 +124,241 @@ func TestProcessTarContentsExtract(t *testing.T) {
 		t.Errorf("failed to change directory to %s: %v", curDir, err)
 	}
 }
+
+type testTarEntry struct {
+	header  *tar.Header
+	content string
+}
+
+func extractTestTar(t *testing.T, workDir string, entries []testTarEntry, opts ExtractOptions) {
+	var buffer bytes.Buffer
+	writer := tar.NewWriter(&buffer)
+	for _, entry := range entries {
+		entry.header.Size = int64(len(entry.content))
+		err := writer.WriteHeader(entry.header)
+		if err != nil {
+			t.Fatalf("failed to write header for %s: %v", entry.header.Name, err)
+		}
+		_, err = writer.Write([]byte(entry.content))
+		if err != nil {
+			t.Fatalf("failed to write content for %s: %v", entry.header.Name, err)
+		}
+	}
+	err := writer.Close()
+	if err != nil {
+		t.Fatalf("failed to close tar writer: %v", err)
+	}
+
+	t.Chdir(workDir)
+	err = ProcessTarContents(io.NopCloser(&buffer), NewExtractTarItemCallback(opts))
+	if err != nil {
+		t.Fatalf("failed to extract tar contents: %v", err)
+	}
+}
+
+func TestExtractHardLink(t *testing.T) {
+	workDir := t.TempDir()
+	extractTestTar(t, workDir, []testTarEntry{
+		{header: &tar.Header{Typeflag: tar.TypeReg, Name: "bin/foo", Mode: 0755}, content: "foo"},
+		{header: &tar.Header{Typeflag: tar.TypeLink, Name: "libexec/bar", Linkname: "bin/foo"}},
+	}, ExtractOptions{})
+
+	target, err := os.Stat(filepath.Join(workDir, "bin/foo"))
+	if err != nil {
+		t.Fatalf("failed to stat hard link target: %v", err)
+	}
+	link, err := os.Lstat(filepath.Join(workDir, "libexec/bar"))
+	if err != nil {
+		t.Fatalf("failed to stat hard link: %v", err)
+	}
+	if !link.Mode().IsRegular() {
+		t.Errorf("expected hard link to be a regular file but got %s", link.Mode())
+	}
+	if !os.SameFile(target, link) {
+		t.Errorf("expected libexec/bar to be a hard link to bin/foo")
+	}
+}
+
+func TestExtractIntoDirectory(t *testing.T) {
+	workDir := t.TempDir()
+	targetDir := t.TempDir()
+	extractTestTar(t, workDir, []testTarEntry{
+		{header: &tar.Header{Typeflag: tar.TypeReg, Name: "bin/foo", Mode: 0755}, content: "foo"},
+		{header: &tar.Header{Typeflag: tar.TypeSymlink, Name: "libexec/bar", Linkname: "../bin/foo"}},
+	}, ExtractOptions{Directory: targetDir})
+
+	for _, name := range []string{"bin/foo", "libexec/bar"} {
+		_, err := os.Lstat(filepath.Join(targetDir, name))
+		if err != nil {
+			t.Errorf("expected %s in target directory: %v", name, err)
+		}
+		_, err = os.Lstat(filepath.Join(workDir, name))
+		if !os.IsNotExist(err) {
+			t.Errorf("expected %s not to be extracted into working directory", name)
+		}
+	}
+}
+
+func TestExtractHardLinkMissingTarget(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := tar.NewWriter(&buffer)
+	err := writer.WriteHeader(&tar.Header{Typeflag: tar.TypeLink, Name: "bin/bar", Linkname: "bin/foo"})
+	if err != nil {
+		t.Fatalf("failed to write header: %v", err)
+	}
+	err = writer.Close()
+	if err != nil {
+		t.Fatalf("failed to close tar writer: %v", err)
+	}
+
+	t.Chdir(t.TempDir())
+	err = ProcessTarContents(io.NopCloser(&buffer), CallbackExtractTarItem)
+	if err == nil {
+		t.Errorf("expected error for hard link with missing target")
+	}
+}
+
+func TestExtractSpecialBitsReported(t *testing.T) {
+	report := NewExtractReport()
+	workDir := t.TempDir()
+	extractTestTar(t, workDir, []testTarEntry{
+		{header: &tar.Header{Typeflag: tar.TypeReg, Name: "bin/foo", Mode: 04755}, content: "foo"},
+	}, ExtractOptions{Report: report})
+
+	info, err := os.Stat(filepath.Join(workDir, "bin/foo"))
+	if err != nil {
+		t.Fatalf("failed to stat file: %v", err)
+	}
+	if info.Mode()&os.ModeSetuid != 0 {
+		t.Errorf("expected setuid bit to be dropped without preserving ownership")
+	}
+	if info.Mode().Perm() != 0755 {
+		t.Errorf("expected permissions 0755 but got %s", info.Mode().Perm())
+	}
+	if !report.HasIssues() {
+		t.Errorf("expected dropped setuid bit to be reported")
+	}
+}
+
+func TestExtractOwnership(t *testing.T) {
+	report := NewExtractReport()
+	workDir := t.TempDir()
+	extractTestTar(t, workDir, []testTarEntry{
+		{header: &tar.Header{Typeflag: tar.TypeReg, Name: "bin/foo", Mode: 04755, Uid: 0, Gid: 0}, content: "foo"},
+	}, ExtractOptions{PreserveOwnership: true, Report: report})
+
+	info, err := os.Stat(filepath.Join(workDir, "bin/foo"))
+	if err != nil {
+		t.Fatalf("failed to stat file: %v", err)
+	}
+	if os.Geteuid() == 0 {
+		if info.Mode()&os.ModeSetuid == 0 {
+			t.Errorf("expected setuid bit to be preserved as root")
+		}
+		if report.HasIssues() {
+			t.Errorf("expected no issues but got %v", report.Issues)
+		}
+	} else {
+		if info.Mode()&os.ModeSetuid != 0 {
+			t.Errorf("expected setuid bit to be dropped as non-root")
+		}
+		if !report.HasIssues() {
+			t.Errorf("expected ownership to be reported as not preserved")
+		}
+	}
+}
+
+func TestExtractXattrs(t *testing.T) {
+	header := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     "bin/foo",
+		Mode:     0755,
+		Format:   tar.FormatPAX,
+		PAXRecords: map[string]string{
+			xattrPAXPrefix + "user.uniget": "test",
+		},
+	}
+
+	report := NewExtractReport()
+	extractTestTar(t, t.TempDir(), []testTarEntry{{header: header, content: "foo"}}, ExtractOptions{Report: report})
+	if len(report.Issues) != 1 || report.Issues[0].Attribute != "xattr user.uniget" {
+		t.Errorf("expected disabled xattr to be reported but got %v", report.Issues)
+	}
+
+	report = NewExtractReport()
+	workDir := t.TempDir()
+	extractTestTar(t, workDir, []testTarEntry{{header: header, content: "foo"}}, ExtractOptions{PreserveXattrs: true, Report: report})
+	value := make([]byte, 64)
+	size, err := unix.Getxattr(filepath.Join(workDir, "bin/foo"), "user.uniget", value)
+	if err != nil {
+		if errors.Is(err, unix.ENOTSUP) {
+			t.Skipf("filesystem does not support user xattrs")
+		}
+		t.Fatalf("failed to read xattr: %v", err)
+	}
+	if string(value[:size]) != "test" {
+		t.Errorf("expected xattr value test but got %s", string(value[:size]))
+	}
+	if report.HasIssues() {
+		t.Errorf("expected no issues but got %v", report.Issues)
+	}
+}
+
+func TestExtractReplacesExistingFile(t *testing.T) {
+	workDir := t.TempDir()
+	t.Chdir(workDir)
+	err := os.MkdirAll("bin", 0755)
+	if err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	err = os.WriteFile("bin/foo", []byte("old"), 0500)
+	if err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	// Keep the old file open like a running process would
+	oldFile, err := os.Open("bin/foo")
+	if err != nil {
+		t.Fatalf("failed to open file: %v", err)
+	}
+	//nolint:errcheck
+	defer oldFile.Close()
+	oldInfo, err := oldFile.Stat()
+	if err != nil {
+		t.Fatalf("failed to stat file: %v", err)
+	}
+
+	extractTestTar(t, workDir, []testTarEntry{
+		{header: &tar.Header{Typeflag: tar.TypeReg, Name: "bin/foo", Mode: 0755}, content: "new"},
+	}, ExtractOptions{})
+
+	newInfo, err := os.Stat(filepath.Join(workDir, "bin/foo"))
+	if err != nil {
+		t.Fatalf("failed to stat file: %v", err)
+	}
+	if os.SameFile(oldInfo, newInfo) {
+		t.Errorf("expected bin/foo to be replaced with a new inode")
+	}
+	data, err := os.ReadFile(filepath.Join(workDir, "bin/foo"))
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	if string(data) != "new" {
+		t.Errorf("expected new content but got %s", string(data))
+	}
+	oldData, err := io.ReadAll(oldFile)
+	if err != nil {
+		t.Fatalf("failed to read old file: %v", err)
+	}
+	if string(oldData) != "old" {
+		t.Errorf("expected old content to be unchanged but got %s", string(oldData))
+	}
+
+	entries, err := os.ReadDir(filepath.Join(workDir, "bin"))
+	if err != nil {
+		t.Fatalf("failed to read directory: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected no temporary files to be left behind but got %d entries", len(entries))
+	}
+}
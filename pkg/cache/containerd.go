@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"context"
 	"fmt"
 	"io"
 
@@ -28,8 +29,8 @@ func NewContainerdCache(namespace string) (*ContainerdCache, error) {
 	}, nil
 }
 
-func (c *ContainerdCache) Get(tool *containers.ToolRef, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
-	err := containers.GetFirstLayerFromContainerdImage(c.client, tool, p, func(reader io.ReadCloser) error {
+func (c *ContainerdCache) Get(ctx context.Context, tool *containers.ToolRef, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
+	err := containers.GetFirstLayerFromContainerdImage(ctx, c.client, tool, p, func(reader io.ReadCloser) error {
 		err := callback(reader)
 		if err != nil {
 			return fmt.Errorf("failed to execute callback: %w", err)
@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"context"
 	"fmt"
 	"io"
 
@@ -26,9 +27,9 @@ func NewDockerCache() (*DockerCache, error) {
 	}, nil
 }
 
-func (c *DockerCache) Get(tool *containers.ToolRef, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
+func (c *DockerCache) Get(ctx context.Context, tool *containers.ToolRef, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
 	logging.Debugf("DockerCache: Pulling %s", tool)
-	err := containers.GetFirstLayerFromDockerImage(c.cli, tool, p, func(reader io.ReadCloser) error {
+	err := containers.GetFirstLayerFromDockerImage(ctx, c.cli, tool, p, func(reader io.ReadCloser) error {
 		err := callback(reader)
 		if err != nil {
 			return fmt.Errorf("failed to execute callback: %w", err)
@@ -3,6 +3,7 @@
 package cache
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"testing"
@@ -28,7 +29,7 @@ func TestDockerCacheGet(t *testing.T) {
 	}
 
 	toolRef := containers.NewToolRef("ghcr.io", "uniget-org/tools", "uniget", "latest")
-	err = cache.Get(toolRef, tui.NewProgressReader(nil, nil), func(reader io.ReadCloser) error {
+	err = cache.Get(context.Background(), toolRef, tui.NewProgressReader(nil, nil), func(reader io.ReadCloser) error {
 		foundUniget := false
 		err = archive.ProcessTarContents(reader, func(reader *tar.Reader, header *tar.Header) error {
 			if header.Typeflag == tar.TypeReg && header.Name == "bin/uniget" {
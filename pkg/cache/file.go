@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -54,6 +55,11 @@ func (c *FileCache) writeDataToCache(reader io.ReadCloser, ref string) error {
 
 	_, err = io.Copy(file, reader)
 	if err != nil {
+		// Do not leave a truncated entry behind which would be used later
+		removeErr := os.Remove(fmt.Sprintf("%s/%s", c.cacheDirectory, ref))
+		if removeErr != nil {
+			logging.Warning.Printfln("Failed to remove partial cache entry for ref %s: %s", ref, removeErr)
+		}
 		return fmt.Errorf("failed to write data for ref %s to cache: %s", ref, err)
 	}
 
@@ -95,11 +101,11 @@ func (c *FileCache) readDataFromCache(ref string, callback func(reader io.ReadCl
 	return callback(fileReader)
 }
 
-func (c *FileCache) Get(tool *containers.ToolRef, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
+func (c *FileCache) Get(ctx context.Context, tool *containers.ToolRef, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
 	cacheKey := tool.Key()
 	if !c.checkDataInCache(tool.String()) {
 		logging.Debugf("FileCache: Cache miss for %s", tool.String())
-		err := c.n.Get(tool, p, func(reader io.ReadCloser) error {
+		err := c.n.Get(ctx, tool, p, func(reader io.ReadCloser) error {
 			logging.Debugf("FileCache: Caching %s", tool.String())
 			err := c.writeDataToCache(reader, cacheKey)
 			if err != nil {
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"os"
 	"testing"
@@ -87,7 +88,7 @@ func TestFileCacheGet(t *testing.T) {
 		t.Errorf("unexpected cache hit")
 	}
 
-	err := cache.Get(toolRef, tui.NewProgressReader(nil, nil), func(reader io.ReadCloser) error {
+	err := cache.Get(context.Background(), toolRef, tui.NewProgressReader(nil, nil), func(reader io.ReadCloser) error {
 		data, err := io.ReadAll(reader)
 		if err != nil {
 			t.Errorf("failed to read data for key %s after cache hit: %v", toolRef.Key(), err)
@@ -105,7 +106,7 @@ func TestFileCacheGet(t *testing.T) {
 		t.Errorf("failed to get data from cache: %v", err)
 	}
 
-	err = cache.Get(toolRef, tui.NewProgressReader(nil, nil), func(reader io.ReadCloser) error {
+	err = cache.Get(context.Background(), toolRef, tui.NewProgressReader(nil, nil), func(reader io.ReadCloser) error {
 		data, err := io.ReadAll(reader)
 		if err != nil {
 			t.Errorf("failed to read data for key %s after cache hit: %v", toolRef.Key(), err)
@@ -7,6 +7,7 @@ import (
 
 	"github.com/regclient/regclient/types/ref"
 	"gitlab.com/uniget-org/cli/pkg/containers"
+	"gitlab.com/uniget-org/cli/pkg/transport"
 )
 
 var (
@@ -45,6 +46,12 @@ func addTestData(registryAddress, registryRepository, registryImage, registryTag
 
 func TestMain(m *testing.M) {
 	var registryAddress = "127.0.0.1:5000"
+	err := transport.Configure(transport.Options{
+		Hosts: map[string]string{registryAddress: transport.TLSDisabled},
+	})
+	if err != nil {
+		panic(err)
+	}
 	containers.StartRegistryWithCallback(registryAddress, func() {
 		err := addTestData(registryAddress, "uniget-org/tools", "jq", "1.7.1")
 		if err != nil {
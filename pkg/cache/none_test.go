@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"io"
@@ -18,7 +19,7 @@ func TestNewNoneCache(t *testing.T) {
 
 func TestNoneCacheGet(t *testing.T) {
 	c := NewNoneCache()
-	err := c.Get(toolRef, tui.NewProgressReader(nil, nil), func(reader io.ReadCloser) error {
+	err := c.Get(context.Background(), toolRef, tui.NewProgressReader(nil, nil), func(reader io.ReadCloser) error {
 		image, err := io.ReadAll(reader)
 		if err != nil {
 			t.Errorf("Error reading image: %v", err)
@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"context"
 	"io"
 
 	"gitlab.com/uniget-org/cli/pkg/containers"
@@ -8,5 +9,5 @@ import (
 )
 
 type Cache interface {
-	Get(tool *containers.ToolRef, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error
+	Get(ctx context.Context, tool *containers.ToolRef, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error
 }
@@ -0,0 +1,127 @@
+package containers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/regclient/regclient"
+	"github.com/regclient/regclient/config"
+	"github.com/regclient/regclient/scheme/reg"
+
+	"gitlab.com/uniget-org/cli/pkg/transport"
+)
+
+const credHelperPrefix = "docker-credential-"
+
+// RegistryCredential configures authentication for a single registry.
+// Username and Password are used for basic auth and token requests.
+// CredHelper names a credential helper like docker does, e.g. "pass" for
+// docker-credential-pass. Token is sent as a bearer token with every
+// request to the registry. Mirrors are tried before the registry itself,
+// e.g. a pull-through cache in the local network.
+type RegistryCredential struct {
+	Registry   string `json:"registry"`
+	Username   string `json:"username,omitempty"`
+	Password   string `json:"password,omitempty"`
+	CredHelper string `json:"credHelper,omitempty"`
+	Token      string `json:"token,omitempty"`
+	// TLS is one of enabled (default), insecure or disabled
+	TLS     string   `json:"tls,omitempty"`
+	Mirrors []string `json:"mirrors,omitempty"`
+}
+
+var (
+	// UseDockerCredentials enables reading credentials and credential
+	// helpers from the Docker configuration (~/.docker/config.json or
+	// $DOCKER_CONFIG/config.json)
+	UseDockerCredentials = true
+
+	registryCredentials []RegistryCredential
+)
+
+// SetRegistryCredentials replaces the credentials used by GetRegclient
+func SetRegistryCredentials(credentials []RegistryCredential) error {
+	for _, credential := range credentials {
+		if len(credential.Registry) == 0 {
+			return fmt.Errorf("credentials must specify a registry")
+		}
+		var tls config.TLSConf
+		if len(credential.TLS) > 0 {
+			err := tls.UnmarshalText([]byte(credential.TLS))
+			if err != nil {
+				return fmt.Errorf("invalid TLS setting for registry %s: %s", credential.Registry, err)
+			}
+		}
+	}
+
+	registryCredentials = credentials
+	return nil
+}
+
+func (c RegistryCredential) host() config.Host {
+	host := config.Host{
+		Name:    c.Registry,
+		User:    c.Username,
+		Pass:    c.Password,
+		Mirrors: c.Mirrors,
+	}
+	if len(c.CredHelper) > 0 {
+		host.CredHelper = c.CredHelper
+		if !strings.HasPrefix(host.CredHelper, credHelperPrefix) {
+			host.CredHelper = credHelperPrefix + host.CredHelper
+		}
+	}
+	if len(c.TLS) > 0 {
+		// Validated by SetRegistryCredentials
+		_ = host.TLS.UnmarshalText([]byte(c.TLS))
+	}
+	return host
+}
+
+func registryOptions() []regclient.Opt {
+	rcOpts := []regclient.Opt{}
+	if UseDockerCredentials {
+		rcOpts = append(rcOpts, regclient.WithDockerCreds())
+	} else {
+		rcOpts = append(rcOpts, regclient.WithDockerCredsFile("i_do_not_exist"))
+	}
+
+	tokens := map[string]string{}
+	for _, credential := range registryCredentials {
+		rcOpts = append(rcOpts, regclient.WithConfigHost(credential.host()))
+		if len(credential.Token) > 0 {
+			tokens[credential.Registry] = credential.Token
+		}
+	}
+
+	// regclient retries requests on its own
+	rt := transport.BaseTransport()
+	if len(tokens) > 0 {
+		rt = &bearerTransport{
+			tokens: tokens,
+			base:   rt,
+		}
+	}
+	rcOpts = append(rcOpts, regclient.WithRegOpts(reg.WithHTTPClient(&http.Client{
+		Transport: rt,
+	})))
+
+	return rcOpts
+}
+
+// bearerTransport adds a static bearer token to requests for registries
+// which have a token configured
+type bearerTransport struct {
+	tokens map[string]string
+	base   http.RoundTripper
+}
+
+func (t *bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, ok := t.tokens[req.URL.Host]
+	if ok && len(req.Header.Get("Authorization")) == 0 {
+		req = req.Clone(req.Context())
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return t.base.RoundTrip(req)
+}
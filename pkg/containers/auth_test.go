@@ -0,0 +1,165 @@
+package containers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/regclient/regclient/types/ref"
+)
+
+func pingRegistry(t *testing.T, registryAddress string) error {
+	t.Helper()
+
+	r, err := ref.New(registryAddress + "/uniget-org/tools/jq:latest")
+	if err != nil {
+		t.Fatalf("failed to parse reference: %s", err)
+	}
+	rc := GetRegclient()
+	//nolint:errcheck
+	defer rc.Close(context.Background(), r)
+
+	_, err = rc.Ping(context.Background(), r)
+	return err
+}
+
+func resetRegistryCredentials(t *testing.T) {
+	t.Helper()
+
+	useDockerCredentials := UseDockerCredentials
+	UseDockerCredentials = false
+	t.Cleanup(func() {
+		UseDockerCredentials = useDockerCredentials
+		registryCredentials = nil
+	})
+}
+
+func TestRegistryBasicAuth(t *testing.T) {
+	resetRegistryCredentials(t)
+	var registryAddress = "127.0.0.1:5002"
+
+	htpasswdFile := filepath.Join(t.TempDir(), "htpasswd")
+	err := WriteHtpasswdFile(htpasswdFile, "uniget", "secret")
+	if err != nil {
+		t.Fatalf("failed to write htpasswd file: %s", err)
+	}
+	registry, err := CreateRegistryWithAuth(registryAddress, htpasswdFile)
+	if err != nil {
+		t.Fatalf("failed to create registry: %s", err)
+	}
+	go func() {
+		_ = registry.ListenAndServe()
+	}()
+	defer func() {
+		_ = registry.Shutdown(context.Background())
+	}()
+
+	started := false
+	for range 50 {
+		res, err := http.Get("http://" + registryAddress + "/v2/") // #nosec G107 -- This is only a test and registryAddress is hardcoded
+		if err == nil {
+			_ = res.Body.Close()
+			if res.StatusCode == http.StatusUnauthorized {
+				started = true
+				break
+			}
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if !started {
+		t.Fatalf("registry did not start")
+	}
+
+	err = pingRegistry(t, registryAddress)
+	if err == nil {
+		t.Errorf("expected ping without credentials to fail")
+	}
+
+	err = SetRegistryCredentials([]RegistryCredential{
+		{Registry: registryAddress, Username: "uniget", Password: "wrong", TLS: "disabled"},
+	})
+	if err != nil {
+		t.Fatalf("failed to set credentials: %s", err)
+	}
+	err = pingRegistry(t, registryAddress)
+	if err == nil {
+		t.Errorf("expected ping with wrong password to fail")
+	}
+
+	err = SetRegistryCredentials([]RegistryCredential{
+		{Registry: registryAddress, Username: "uniget", Password: "secret", TLS: "disabled"},
+	})
+	if err != nil {
+		t.Fatalf("failed to set credentials: %s", err)
+	}
+	err = pingRegistry(t, registryAddress)
+	if err != nil {
+		t.Errorf("expected ping with credentials to succeed: %s", err)
+	}
+}
+
+func TestRegistryBearerToken(t *testing.T) {
+	resetRegistryCredentials(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	registryAddress := strings.TrimPrefix(srv.URL, "http://")
+
+	err := SetRegistryCredentials([]RegistryCredential{
+		{Registry: registryAddress, TLS: "disabled"},
+	})
+	if err != nil {
+		t.Fatalf("failed to set credentials: %s", err)
+	}
+	err = pingRegistry(t, registryAddress)
+	if err == nil {
+		t.Errorf("expected ping without token to fail")
+	}
+
+	err = SetRegistryCredentials([]RegistryCredential{
+		{Registry: registryAddress, Token: "secret", TLS: "disabled"},
+	})
+	if err != nil {
+		t.Fatalf("failed to set credentials: %s", err)
+	}
+	err = pingRegistry(t, registryAddress)
+	if err != nil {
+		t.Errorf("expected ping with token to succeed: %s", err)
+	}
+}
+
+func TestSetRegistryCredentialsInvalid(t *testing.T) {
+	resetRegistryCredentials(t)
+
+	err := SetRegistryCredentials([]RegistryCredential{{Username: "uniget"}})
+	if err == nil {
+		t.Errorf("expected error for missing registry")
+	}
+
+	err = SetRegistryCredentials([]RegistryCredential{{Registry: "registry.example.com", TLS: "maybe"}})
+	if err == nil {
+		t.Errorf("expected error for invalid TLS setting")
+	}
+}
+
+func TestRegistryCredentialHost(t *testing.T) {
+	host := RegistryCredential{Registry: "registry.example.com", CredHelper: "pass"}.host()
+	if host.CredHelper != "docker-credential-pass" {
+		t.Errorf("expected credential helper docker-credential-pass but got %s", host.CredHelper)
+	}
+
+	host = RegistryCredential{Registry: "registry.example.com", CredHelper: "docker-credential-gcr"}.host()
+	if host.CredHelper != "docker-credential-gcr" {
+		t.Errorf("expected credential helper docker-credential-gcr but got %s", host.CredHelper)
+	}
+}
@@ -0,0 +1,282 @@
+package containers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/distribution/distribution/v3/configuration"
+	"github.com/distribution/distribution/v3/registry/handlers"
+	_ "github.com/distribution/distribution/v3/registry/storage/driver/filesystem"
+	"github.com/dustin/go-humanize"
+	"github.com/opencontainers/go-digest"
+
+	"gitlab.com/uniget-org/cli/pkg/logging"
+)
+
+// blobsDirectory is the location of blobs below the storage directory as
+// used by the filesystem driver of distribution
+const blobsDirectory = "docker/registry/v2/blobs"
+
+// PullThroughCacheOptions configures a registry which fetches missing
+// content from an upstream registry and stores it on disk
+type PullThroughCacheOptions struct {
+	StorageDirectory string
+	// Upstream is the URL of the registry to fetch content from
+	Upstream string
+	Username string
+	Password string
+	// Repository limits the cache to repositories below this prefix
+	Repository string
+	// MaxSize is the maximum size of all blobs in bytes (0 is unlimited)
+	MaxSize int64
+	// TTL removes content which was not requested again (0 keeps content)
+	TTL time.Duration
+}
+
+type PullThroughCache struct {
+	opts    PullThroughCacheOptions
+	handler http.Handler
+	mutex   sync.Mutex
+}
+
+type cachedBlob struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// NewPullThroughCache creates a read-only registry serving content from
+// the upstream registry
+func NewPullThroughCache(ctx context.Context, opts PullThroughCacheOptions) (cache *PullThroughCache, err error) {
+	// https://distribution.github.io/distribution/about/configuration/
+	const distributionConfig = `
+version: 0.1
+log:
+  accesslog:
+    disabled: true
+storage:
+  filesystem:
+    rootdirectory: /var/lib/registry
+  delete:
+    enabled: true
+`
+
+	if len(opts.StorageDirectory) == 0 {
+		return nil, fmt.Errorf("storage directory must be specified")
+	}
+	upstream, err := url.Parse(opts.Upstream)
+	if err != nil || (upstream.Scheme != "https" && upstream.Scheme != "http") || len(upstream.Host) == 0 {
+		return nil, fmt.Errorf("invalid upstream registry URL %s", opts.Upstream)
+	}
+	if opts.MaxSize < 0 {
+		return nil, fmt.Errorf("maximum size must not be negative")
+	}
+	if opts.TTL < 0 {
+		return nil, fmt.Errorf("TTL must not be negative")
+	}
+	if len(opts.Repository) > 0 && !strings.HasSuffix(opts.Repository, "/") {
+		opts.Repository += "/"
+	}
+
+	storageDirectory, err := filepath.Abs(opts.StorageDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve storage directory %s: %s", opts.StorageDirectory, err)
+	}
+	opts.StorageDirectory = storageDirectory
+	err = os.MkdirAll(opts.StorageDirectory, 0750)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create storage directory %s: %s", opts.StorageDirectory, err)
+	}
+
+	config, err := configuration.Parse(bytes.NewReader([]byte(distributionConfig)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse distribution configuration: %s", err)
+	}
+	config.Storage["filesystem"]["rootdirectory"] = opts.StorageDirectory
+	config.Proxy = configuration.Proxy{
+		RemoteURL: opts.Upstream,
+		Username:  opts.Username,
+		Password:  opts.Password,
+		TTL:       &opts.TTL,
+	}
+
+	// distribution panics on invalid configuration
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to create registry: %v", r)
+		}
+	}()
+	app := handlers.NewApp(ctx, config)
+
+	return &PullThroughCache{
+		opts:    opts,
+		handler: app,
+	}, nil
+}
+
+// Handler serves pulls for repositories below the configured prefix and
+// marks blobs as recently used
+func (c *PullThroughCache) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			writeRegistryError(w, http.StatusMethodNotAllowed, "UNSUPPORTED", "the cache is read-only")
+			return
+		}
+
+		name, ok := strings.CutPrefix(r.URL.Path, "/v2/")
+		if !ok && r.URL.Path != "/v2" {
+			http.NotFound(w, r)
+			return
+		}
+		if len(name) > 0 && !strings.HasPrefix(name, c.opts.Repository) {
+			writeRegistryError(w, http.StatusNotFound, "NAME_UNKNOWN", "repository name not known to registry")
+			return
+		}
+
+		c.handler.ServeHTTP(w, r)
+
+		_, blob, ok := strings.Cut(name, "/blobs/")
+		if ok && r.Method == http.MethodGet {
+			c.touch(blob)
+		}
+	})
+}
+
+func writeRegistryError(w http.ResponseWriter, status int, code string, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = fmt.Fprintf(w, `{"errors":[{"code":%q,"message":%q}]}`+"\n", code, message)
+}
+
+func (c *PullThroughCache) blobPath(d digest.Digest) string {
+	return filepath.Join(c.opts.StorageDirectory, blobsDirectory, d.Algorithm().String(), d.Encoded()[:2], d.Encoded(), "data")
+}
+
+// touch updates the modification time of a blob which is used to evict
+// the least recently used blobs first
+func (c *PullThroughCache) touch(blob string) {
+	d, err := digest.Parse(blob)
+	if err != nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	err = os.Chtimes(c.blobPath(d), now, now)
+	if err != nil && !os.IsNotExist(err) {
+		logging.Debugf("Failed to update access time of blob %s: %s", d, err)
+	}
+}
+
+func (c *PullThroughCache) blobs() ([]cachedBlob, int64, error) {
+	blobs := make([]cachedBlob, 0)
+	var total int64
+
+	root := filepath.Join(c.opts.StorageDirectory, blobsDirectory)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || d.Name() != "data" {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		blobs = append(blobs, cachedBlob{
+			path:    path,
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+		total += info.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list blobs in %s: %s", root, err)
+	}
+
+	return blobs, total, nil
+}
+
+// Size returns the size of all blobs in the cache
+func (c *PullThroughCache) Size() (int64, error) {
+	_, total, err := c.blobs()
+	return total, err
+}
+
+// Evict removes the least recently used blobs until the cache does not
+// exceed the maximum size. It returns the number of bytes removed. Evicted
+// blobs are fetched from the upstream registry again when requested.
+func (c *PullThroughCache) Evict() (int64, error) {
+	if c.opts.MaxSize == 0 {
+		return 0, nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	blobs, total, err := c.blobs()
+	if err != nil {
+		return 0, err
+	}
+	sort.Slice(blobs, func(i, j int) bool {
+		return blobs[i].modTime.Before(blobs[j].modTime)
+	})
+
+	var removed int64
+	for _, blob := range blobs {
+		if total-removed <= c.opts.MaxSize {
+			break
+		}
+
+		// Remove the directory of the blob like distribution does
+		err := os.RemoveAll(filepath.Dir(blob.path))
+		if err != nil {
+			return removed, fmt.Errorf("failed to remove blob %s: %s", filepath.Base(filepath.Dir(blob.path)), err)
+		}
+		logging.Debugf("Evicted blob %s (%s)", filepath.Base(filepath.Dir(blob.path)), humanize.IBytes(uint64(blob.size))) // #nosec G115 -- size is not negative
+		removed += blob.size
+	}
+
+	return removed, nil
+}
+
+// EvictPeriodically calls Evict in the given interval until the context
+// is done
+func (c *PullThroughCache) EvictPeriodically(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			removed, err := c.Evict()
+			if err != nil {
+				logging.Warning.Printfln("Failed to evict blobs: %s", err)
+				continue
+			}
+			if removed > 0 {
+				logging.Info.Printfln("Evicted %s from the cache", humanize.IBytes(uint64(removed))) // #nosec G115 -- size is not negative
+			}
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package containers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/regclient/regclient/types/ref"
+
+	"gitlab.com/uniget-org/cli/pkg/transport"
+	"gitlab.com/uniget-org/cli/pkg/tui"
+)
+
+func newTestCache(t *testing.T, maxSize int64) (*PullThroughCache, string) {
+	t.Helper()
+
+	cache, err := NewPullThroughCache(context.Background(), PullThroughCacheOptions{
+		StorageDirectory: t.TempDir(),
+		Upstream:         "http://" + registryAddress,
+		Repository:       "uniget-org",
+		MaxSize:          maxSize,
+	})
+	if err != nil {
+		t.Fatalf("failed to create cache: %s", err)
+	}
+	srv := httptest.NewServer(cache.Handler())
+	t.Cleanup(srv.Close)
+
+	return cache, strings.TrimPrefix(srv.URL, "http://")
+}
+
+func TestNewPullThroughCacheInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		opts PullThroughCacheOptions
+	}{
+		{name: "missing storage", opts: PullThroughCacheOptions{Upstream: "https://ghcr.io"}},
+		{name: "invalid upstream", opts: PullThroughCacheOptions{StorageDirectory: t.TempDir(), Upstream: "ghcr.io"}},
+		{name: "negative size", opts: PullThroughCacheOptions{StorageDirectory: t.TempDir(), Upstream: "https://ghcr.io", MaxSize: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPullThroughCache(context.Background(), tt.opts)
+			if err == nil {
+				t.Errorf("expected error but got nil")
+			}
+		})
+	}
+}
+
+func TestPullThroughCacheRequests(t *testing.T) {
+	_, cacheAddress := newTestCache(t, 0)
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+	}{
+		{method: http.MethodGet, path: "/v2/", status: http.StatusOK},
+		{method: http.MethodGet, path: "/v2/other/image/tags/list", status: http.StatusNotFound},
+		{method: http.MethodGet, path: "/metrics", status: http.StatusNotFound},
+		{method: http.MethodDelete, path: "/v2/uniget-org/tools/jq/manifests/1.7.1", status: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		req, err := http.NewRequest(tt.method, "http://"+cacheAddress+tt.path, nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %s", err)
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Errorf("failed to get response for %s %s: %s", tt.method, tt.path, err)
+			continue
+		}
+		_ = res.Body.Close()
+		if res.StatusCode != tt.status {
+			t.Errorf("expected status code %d for %s %s, got %d", tt.status, tt.method, tt.path, res.StatusCode)
+		}
+	}
+}
+
+func TestPullThroughCacheMirror(t *testing.T) {
+	resetRegistryCredentials(t)
+	cache, cacheAddress := newTestCache(t, 1)
+
+	err := transport.Configure(transport.Options{
+		Hosts: map[string]string{
+			registryAddress: transport.TLSDisabled,
+			cacheAddress:    transport.TLSDisabled,
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to configure transport: %s", err)
+	}
+	t.Cleanup(func() {
+		_ = transport.Configure(transport.Options{
+			Hosts: map[string]string{registryAddress: transport.TLSDisabled},
+		})
+	})
+	err = SetRegistryCredentials([]RegistryCredential{
+		{Registry: registryAddress, Mirrors: []string{cacheAddress}},
+	})
+	if err != nil {
+		t.Fatalf("failed to set registry credentials: %s", err)
+	}
+
+	r, err := ref.New(NewToolRef(registryAddress, registryRepository, registryImage, registryTag).String())
+	if err != nil {
+		t.Fatalf("failed to parse reference: %s", err)
+	}
+	pull := func() {
+		rc := GetRegclient()
+		//nolint:errcheck
+		defer rc.Close(context.Background(), r)
+
+		err := GetFirstLayerFromRegistryRaw(context.Background(), rc, r, tui.NewProgressReader(nil, nil), func(reader io.ReadCloser) error {
+			_, err := io.Copy(io.Discard, reader)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("failed to get layer: %s", err)
+		}
+	}
+
+	pull()
+	size, err := cache.Size()
+	if err != nil {
+		t.Fatalf("failed to get size of cache: %s", err)
+	}
+	if size == 0 {
+		t.Fatalf("expected layer to be pulled through the mirror")
+	}
+
+	removed, err := cache.Evict()
+	if err != nil {
+		t.Fatalf("failed to evict blobs: %s", err)
+	}
+	if removed != size {
+		t.Errorf("expected %d bytes to be evicted, got %d", size, removed)
+	}
+
+	// Evicted blobs are fetched again
+	pull()
+}
+
+func TestPullThroughCacheEvict(t *testing.T) {
+	cache, _ := newTestCache(t, 100)
+
+	now := time.Now()
+	blobs := []struct {
+		content string
+		age     time.Duration
+		evicted bool
+	}{
+		{content: strings.Repeat("a", 100), age: 3 * time.Hour, evicted: true},
+		{content: strings.Repeat("b", 50), age: 2 * time.Hour, evicted: true},
+		{content: strings.Repeat("c", 100), age: time.Hour},
+	}
+	for _, blob := range blobs {
+		path := cache.blobPath(digest.FromString(blob.content))
+		err := os.MkdirAll(filepath.Dir(path), 0750)
+		if err != nil {
+			t.Fatalf("failed to create blob directory: %s", err)
+		}
+		err = os.WriteFile(path, []byte(blob.content), 0600)
+		if err != nil {
+			t.Fatalf("failed to write blob: %s", err)
+		}
+		err = os.Chtimes(path, now.Add(-blob.age), now.Add(-blob.age))
+		if err != nil {
+			t.Fatalf("failed to set modification time: %s", err)
+		}
+	}
+
+	removed, err := cache.Evict()
+	if err != nil {
+		t.Fatalf("failed to evict blobs: %s", err)
+	}
+	if removed != 150 {
+		t.Errorf("expected 150 bytes to be evicted, got %d", removed)
+	}
+	for _, blob := range blobs {
+		_, err := os.Stat(filepath.Dir(cache.blobPath(digest.FromString(blob.content))))
+		if blob.evicted && !os.IsNotExist(err) {
+			t.Errorf("expected blob %s to be evicted", blob.content[:1])
+		}
+		if !blob.evicted && err != nil {
+			t.Errorf("expected blob %s to be kept: %s", blob.content[:1], err)
+		}
+	}
+}
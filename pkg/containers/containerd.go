@@ -38,14 +38,14 @@ func ContainerdIsAvailable() bool {
 	return version.Version != ""
 }
 
-func GetFirstLayerFromContainerdImage(client *containerd.Client, ref *ToolRef, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
-	shaString, err := GetFirstLayerShaFromRegistry(ref)
+func GetFirstLayerFromContainerdImage(ctx context.Context, client *containerd.Client, ref *ToolRef, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
+	shaString, err := GetFirstLayerShaFromRegistry(ctx, ref)
 	if err != nil {
 		return fmt.Errorf("failed to get first layer sha: %s", err)
 	}
 	sha := shaString[7:]
 
-	err = ReadContainerdImage(client, ref.String(), p, func(reader io.ReadCloser) error {
+	err = ReadContainerdImage(ctx, client, ref.String(), p, func(reader io.ReadCloser) error {
 		err = UnpackLayerFromDockerImage(reader, sha, func(reader io.ReadCloser) error {
 			reader, err := gzip.NewReader(reader)
 			if err != nil {
@@ -72,16 +72,12 @@ func GetFirstLayerFromContainerdImage(client *containerd.Client, ref *ToolRef, p
 	return nil
 }
 
-func CheckContainerdImageExists(client *containerd.Client, ref string) bool {
-	ctx := context.Background()
-
+func CheckContainerdImageExists(ctx context.Context, client *containerd.Client, ref string) bool {
 	_, err := client.GetImage(ctx, ref)
 	return err == nil
 }
 
-func PullContainerdImage(client *containerd.Client, ref string) error {
-	ctx := context.Background()
-
+func PullContainerdImage(ctx context.Context, client *containerd.Client, ref string) error {
 	_, err := client.Pull(ctx, ref)
 	if err != nil {
 		return fmt.Errorf("failed to pull image: %s", err)
@@ -90,10 +86,8 @@ func PullContainerdImage(client *containerd.Client, ref string) error {
 	return nil
 }
 
-func ReadContainerdImage(client *containerd.Client, ref string, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
-	ctx := context.Background()
-
-	err := PullContainerdImage(client, ref)
+func ReadContainerdImage(ctx context.Context, client *containerd.Client, ref string, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
+	err := PullContainerdImage(ctx, client, ref)
 	if err != nil {
 		return fmt.Errorf("failed to pull image: %s", err)
 	}
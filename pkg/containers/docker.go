@@ -38,16 +38,16 @@ func DockerIsAvailable() bool {
 	return ping.APIVersion != ""
 }
 
-func GetFirstLayerFromDockerImage(cli *client.Client, ref *ToolRef, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
+func GetFirstLayerFromDockerImage(ctx context.Context, cli *client.Client, ref *ToolRef, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
 	logging.Tracef("Getting first layer for %s using docker", ref)
 
-	shaString, err := GetFirstLayerShaFromRegistry(ref)
+	shaString, err := GetFirstLayerShaFromRegistry(ctx, ref)
 	if err != nil {
 		return fmt.Errorf("failed to get first layer sha: %s", err)
 	}
 	sha := shaString[7:]
 
-	err = ReadDockerImage(cli, ref.String(), p, func(reader io.ReadCloser) error {
+	err = ReadDockerImage(ctx, cli, ref.String(), p, func(reader io.ReadCloser) error {
 		err := UnpackLayerFromDockerImage(reader, sha, func(reader io.ReadCloser) error {
 			err = callback(reader)
 			if err != nil {
@@ -69,9 +69,7 @@ func GetFirstLayerFromDockerImage(cli *client.Client, ref *ToolRef, p tui.Progre
 	return nil
 }
 
-func PullDockerImage(cli *client.Client, ref string) error {
-	ctx := context.Background()
-
+func PullDockerImage(ctx context.Context, cli *client.Client, ref string) error {
 	events, err := cli.ImagePull(ctx, ref, client.ImagePullOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to pull image: %s", err)
@@ -86,17 +84,13 @@ func PullDockerImage(cli *client.Client, ref string) error {
 	return nil
 }
 
-func CheckDockerImageExists(cli *client.Client, ref string) bool {
-	ctx := context.Background()
-
+func CheckDockerImageExists(ctx context.Context, cli *client.Client, ref string) bool {
 	_, err := cli.ImageInspect(ctx, ref)
 	return err == nil
 }
 
-func ReadDockerImage(cli *client.Client, ref string, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
-	ctx := context.Background()
-
-	err := PullDockerImage(cli, ref)
+func ReadDockerImage(ctx context.Context, cli *client.Client, ref string, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
+	err := PullDockerImage(ctx, cli, ref)
 	if err != nil {
 		return fmt.Errorf("failed to pull image: %s", err)
 	}
@@ -159,8 +153,7 @@ func UnpackLayerFromDockerImage(buffer io.ReadCloser, sha256 string, callback fu
 	return fmt.Errorf("failed to extract layer %s", sha256)
 }
 
-func ListDockerImagesByPrefix(cli *client.Client, prefix string) ([]image.Summary, error) {
-	ctx := context.Background()
+func ListDockerImagesByPrefix(ctx context.Context, cli *client.Client, prefix string) ([]image.Summary, error) {
 	images, err := cli.ImageList(ctx, client.ImageListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list images: %w", err)
@@ -178,8 +171,7 @@ func ListDockerImagesByPrefix(cli *client.Client, prefix string) ([]image.Summar
 	return filtered, nil
 }
 
-func RemoveDockerImage(cli *client.Client, ref string) error {
-	ctx := context.Background()
+func RemoveDockerImage(ctx context.Context, cli *client.Client, ref string) error {
 	_, err := cli.ImageRemove(ctx, ref, client.ImageRemoveOptions{Force: true, PruneChildren: true})
 	if err != nil {
 		return fmt.Errorf("failed to remove image %s: %w", ref, err)
@@ -3,6 +3,7 @@
 package containers
 
 import (
+	"context"
 	"io"
 	"testing"
 
@@ -44,20 +45,20 @@ func TestReadFirstLayerFromDockerImage(t *testing.T) {
 	toolRef := NewToolRef("ghcr.io", "uniget-org/tools", "continue", "latest")
 	image := toolRef.String()
 
-	if !CheckDockerImageExists(cli, image) {
-		err := PullDockerImage(cli, image)
+	if !CheckDockerImageExists(context.Background(), cli, image) {
+		err := PullDockerImage(context.Background(), cli, image)
 		if err != nil {
 			t.Fatalf("failed to pull docker image: %s", err)
 		}
 	}
 
-	shaString, err := GetFirstLayerShaFromRegistry(toolRef)
+	shaString, err := GetFirstLayerShaFromRegistry(context.Background(), toolRef)
 	if err != nil {
 		t.Fatalf("failed to get first layer sha: %s", err)
 	}
 	sha := shaString[7:]
 
-	err = ReadDockerImage(cli, image, func(reader io.ReadCloser) error {
+	err = ReadDockerImage(context.Background(), cli, image, func(reader io.ReadCloser) error {
 		err := UnpackLayerFromDockerImage(reader, sha, func(reader io.ReadCloser) error {
 			err = archive.ProcessTarContents(io.NopCloser(reader), func(tar *tar.Reader, header *tar.Header) error { return nil })
 			if err != nil {
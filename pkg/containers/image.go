@@ -4,13 +4,11 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/regclient/regclient"
 	"github.com/regclient/regclient/types/ref"
 )
 
-func FindNewDigest(r ref.Ref) (string, error) {
-	ctx := context.Background()
-	rc := regclient.New()
+func FindNewDigest(ctx context.Context, r ref.Ref) (string, error) {
+	rc := GetRegclient()
 	defer func() {
 		_ = rc.Close(ctx, r)
 	}()
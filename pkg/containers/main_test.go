@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/regclient/regclient/types/ref"
+
+	"gitlab.com/uniget-org/cli/pkg/transport"
 )
 
 var (
@@ -56,6 +58,12 @@ func addTestData() error {
 
 func TestMain(m *testing.M) {
 	var registryAddress = "127.0.0.1:5000"
+	err := transport.Configure(transport.Options{
+		Hosts: map[string]string{registryAddress: transport.TLSDisabled},
+	})
+	if err != nil {
+		panic(err)
+	}
 	StartRegistryWithCallback(registryAddress, func() {
 		err := addTestData()
 		if err != nil {
@@ -3,6 +3,7 @@ package containers
 import (
 	"compress/gzip"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"time"
@@ -11,32 +12,44 @@ import (
 	_ "crypto/sha512"
 
 	"github.com/opencontainers/go-digest"
+	"gitlab.com/uniget-org/cli/pkg/logging"
+	"gitlab.com/uniget-org/cli/pkg/transport"
 	"gitlab.com/uniget-org/cli/pkg/tui"
 
 	"github.com/regclient/regclient"
 	"github.com/regclient/regclient/config"
+	"github.com/regclient/regclient/scheme/reg"
 	"github.com/regclient/regclient/types/descriptor"
+	"github.com/regclient/regclient/types/errs"
 	"github.com/regclient/regclient/types/manifest"
 	"github.com/regclient/regclient/types/mediatype"
 	"github.com/regclient/regclient/types/platform"
 	"github.com/regclient/regclient/types/ref"
 )
 
+// ManifestTimeout limits how long fetching a single manifest may take
+var ManifestTimeout = 60 * time.Second
+
 func GetRegclient() *regclient.RegClient {
+	opts := transport.Current()
 	rcOpts := []regclient.Opt{}
-	rcOpts = append(rcOpts, regclient.WithDockerCredsFile("i_do_not_exist"))
-	rcOpts = append(rcOpts, regclient.WithUserAgent("uniget"))
-	rcOpts = append(rcOpts, regclient.WithConfigHost(config.Host{
-		Name: "127.0.0.1:5000",
-		TLS:  config.TLSDisabled,
-	}))
+	rcOpts = append(rcOpts, regclient.WithUserAgent(transport.UserAgent))
+	rcOpts = append(rcOpts, regclient.WithRegOpts(reg.WithRetryLimit(opts.Retries)))
+	for host, mode := range opts.Hosts {
+		var tls config.TLSConf
+		// Validated by transport.Configure
+		_ = tls.UnmarshalText([]byte(mode))
+		rcOpts = append(rcOpts, regclient.WithConfigHost(config.Host{
+			Name: host,
+			TLS:  tls,
+		}))
+	}
+	rcOpts = append(rcOpts, registryOptions()...)
 
 	return regclient.New(rcOpts...)
 }
 
-func GetImageTags(t *ToolRef) ([]string, error) {
-	ctx := context.Background()
-
+func GetImageTags(ctx context.Context, t *ToolRef) ([]string, error) {
 	r, err := ref.New(t.String())
 	if err != nil {
 		return []string{}, fmt.Errorf("failed to parse image name <%s>: %s", t.String(), err)
@@ -63,12 +76,12 @@ func GetImageTags(t *ToolRef) ([]string, error) {
 	return filteredTags, nil
 }
 
-func GetImageLabels(image *ToolRef) (labels map[string]string, err error) {
+func GetImageLabels(ctx context.Context, image *ToolRef) (labels map[string]string, err error) {
 	rc := GetRegclient()
 	//nolint:errcheck
-	defer rc.Close(context.Background(), image.GetRef())
+	defer rc.Close(ctx, image.GetRef())
 
-	m, err := GetPlatformManifestForLocalPlatform(context.Background(), rc, image.GetRef())
+	m, err := GetPlatformManifestForLocalPlatform(ctx, rc, image.GetRef())
 	if err != nil {
 		return nil, fmt.Errorf("failed to get manifest: %s", err)
 	}
@@ -83,7 +96,7 @@ func GetImageLabels(image *ToolRef) (labels map[string]string, err error) {
 		return nil, fmt.Errorf("failed to get config: %s", err)
 	}
 
-	cfg, err := rc.BlobGetOCIConfig(context.Background(), image.GetRef(), cd)
+	cfg, err := rc.BlobGetOCIConfig(ctx, image.GetRef(), cd)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get OCI config: %s", err)
 	}
@@ -97,9 +110,7 @@ func GetImageLabels(image *ToolRef) (labels map[string]string, err error) {
 	return labels, nil
 }
 
-func GetFirstLayerShaFromRegistry(image *ToolRef) (string, error) {
-	ctx := context.Background()
-
+func GetFirstLayerShaFromRegistry(ctx context.Context, image *ToolRef) (string, error) {
 	r, err := ref.New(image.String())
 	if err != nil {
 		return "", fmt.Errorf("failed to parse image name <%s>: %s", image, err)
@@ -109,7 +120,7 @@ func GetFirstLayerShaFromRegistry(image *ToolRef) (string, error) {
 	//nolint:errcheck
 	defer rc.Close(ctx, r)
 
-	manifestCtx, manifestCancel := context.WithTimeout(ctx, 60*time.Second)
+	manifestCtx, manifestCancel := context.WithTimeout(ctx, ManifestTimeout)
 	defer manifestCancel()
 	m, err := GetPlatformManifestForLocalPlatform(manifestCtx, rc, r)
 	if err != nil {
@@ -185,7 +196,7 @@ func GetPlatformManifest(ctx context.Context, rc *regclient.RegClient, r ref.Ref
 }
 
 func HeadManifest(ctx context.Context, rc *regclient.RegClient, r ref.Ref) (bool, error) {
-	manifestCtx, manifestCancel := context.WithTimeout(ctx, 60*time.Second)
+	manifestCtx, manifestCancel := context.WithTimeout(ctx, ManifestTimeout)
 	defer manifestCancel()
 
 	_, err := HeadPlatformManifestForLocalPlatform(manifestCtx, rc, r)
@@ -197,7 +208,7 @@ func HeadManifest(ctx context.Context, rc *regclient.RegClient, r ref.Ref) (bool
 }
 
 func GetManifest(ctx context.Context, rc *regclient.RegClient, r ref.Ref) (manifest.Manifest, error) {
-	manifestCtx, manifestCancel := context.WithTimeout(ctx, 60*time.Second)
+	manifestCtx, manifestCancel := context.WithTimeout(ctx, ManifestTimeout)
 	defer manifestCancel()
 
 	m, err := GetPlatformManifestForLocalPlatform(manifestCtx, rc, r)
@@ -242,16 +253,17 @@ func GetLayerFromManifestByIndex(ctx context.Context, rc *regclient.RegClient, m
 			return fmt.Errorf("failed to parse digest %s: %s", layer.Digest, err)
 		}
 
-		blob, err := rc.BlobGet(context.Background(), m.GetRef(), descriptor.Descriptor{Digest: d})
-		if err != nil {
-			return fmt.Errorf("failed to get blob for digest %s: %s", layer.Digest, err)
-		}
-
+		p.SetDigest(string(layer.Digest))
 		p.SetTotal(layer.Size)
-		p.SetReader(blob)
-		err = callback(p)
+		err = transport.Download(ctx, openBlob(rc, m.GetRef(), d), d, layer.Size, &p, func(reader io.ReadCloser) error {
+			err := callback(reader)
+			if err != nil {
+				return fmt.Errorf("failed to execute callback: %w", err)
+			}
+			return nil
+		})
 		if err != nil {
-			return fmt.Errorf("failed to execute callback: %w", err)
+			return fmt.Errorf("failed to download blob for digest %s: %w", layer.Digest, err)
 		}
 
 		return nil
@@ -301,3 +313,53 @@ func GetFirstLayerFromRegistry(ctx context.Context, rc *regclient.RegClient, r r
 
 	return nil
 }
+
+// isPermanentBlobError returns true for errors which do not go away when
+// the blob is requested again. Network errors are retried.
+func isPermanentBlobError(err error) bool {
+	return errors.Is(err, errs.ErrNotFound) ||
+		errors.Is(err, errs.ErrHTTPUnauthorized) ||
+		errors.Is(err, errs.ErrInvalidReference) ||
+		errors.Is(err, errs.ErrHTTPRedirectRefused) ||
+		errors.Is(err, context.Canceled)
+}
+
+// openBlob resumes downloads with a range request against the URL which
+// served the blob. Registries usually redirect to a storage backend which
+// does not require authentication. If the range request fails, the blob
+// is requested again and the bytes received so far are skipped.
+func openBlob(rc *regclient.RegClient, r ref.Ref, d digest.Digest) transport.Opener {
+	var blobURL string
+
+	return func(ctx context.Context, offset int64) (io.ReadCloser, error) {
+		if offset > 0 && len(blobURL) > 0 {
+			body, err := transport.OpenURL(blobURL)(ctx, offset)
+			if err == nil {
+				return body, nil
+			}
+			logging.Debugf("Unable to resume download of %s using a range request: %s", d, err)
+		}
+
+		blob, err := rc.BlobGet(ctx, r, descriptor.Descriptor{Digest: d})
+		if err != nil {
+			err = fmt.Errorf("failed to get blob: %w", err)
+			if isPermanentBlobError(err) {
+				return nil, transport.Permanent(err)
+			}
+			return nil, err
+		}
+		resp := blob.Response()
+		if resp != nil && resp.Request != nil && resp.Request.URL != nil {
+			blobURL = resp.Request.URL.String()
+		}
+
+		if offset > 0 {
+			_, err = io.CopyN(io.Discard, blob, offset)
+			if err != nil {
+				_ = blob.Close()
+				return nil, fmt.Errorf("failed to skip %d bytes: %w", offset, err)
+			}
+		}
+		return blob, nil
+	}
+}
@@ -1,15 +1,22 @@
 package containers
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"testing"
 
+	"github.com/opencontainers/go-digest"
+	"github.com/regclient/regclient/types/descriptor"
+	"github.com/regclient/regclient/types/errs"
 	"github.com/regclient/regclient/types/platform"
 	"github.com/regclient/regclient/types/ref"
+	"gitlab.com/uniget-org/cli/pkg/transport"
 	"gitlab.com/uniget-org/cli/pkg/tui"
 )
 
@@ -23,7 +30,7 @@ func TestGetRegclient(t *testing.T) {
 func TestGetImageTagsInvalidImage(t *testing.T) {
 	registryAddress := "127.0.0.1:5000:5001"
 	toolRef := NewToolRef(registryAddress, "a/b", "c", "d+e")
-	_, err := GetImageTags(toolRef)
+	_, err := GetImageTags(context.Background(), toolRef)
 	if err == nil {
 		t.Errorf("expected error due to invalid registry host %s: %s", registryAddress, err)
 	}
@@ -32,14 +39,14 @@ func TestGetImageTagsInvalidImage(t *testing.T) {
 func TestGetImageTagsUnreachableRegistry(t *testing.T) {
 	registryAddress := "127.0.0.1:5001"
 	toolRef := NewToolRef(registryAddress, registryRepository, registryImage, registryTag)
-	_, err := GetImageTags(toolRef)
+	_, err := GetImageTags(context.Background(), toolRef)
 	if err == nil {
 		t.Errorf("expected error due to unreachable registry %s: %s", registryAddress, err)
 	}
 }
 
 func TestGetImageTags(t *testing.T) {
-	tags, err := GetImageTags(toolRef)
+	tags, err := GetImageTags(context.Background(), toolRef)
 	if err != nil {
 		t.Errorf("failed to get image tags: %s", err)
 	}
@@ -74,7 +81,7 @@ func TestGetPlatformManifestForLocalPlatform(t *testing.T) {
 func TestGetFirstLayerShaFromRegistry(t *testing.T) {
 	toolRef = NewToolRef(registryAddress, registryRepository, registryImage, registryTag)
 
-	layerSha, err := GetFirstLayerShaFromRegistry(toolRef)
+	layerSha, err := GetFirstLayerShaFromRegistry(context.Background(), toolRef)
 	if err != nil {
 		t.Errorf("failed to get first layer sha: %s", err)
 	}
@@ -218,3 +225,69 @@ func TestGetFirstLayerFromRegistry(t *testing.T) {
 		t.Errorf("failed to get first layer: %s", err)
 	}
 }
+
+func TestOpenBlobResume(t *testing.T) {
+	var registryAddress = "127.0.0.1:5003"
+	opts := transport.Current()
+	err := transport.Configure(transport.Options{
+		Hosts: map[string]string{registryAddress: transport.TLSDisabled},
+	})
+	if err != nil {
+		t.Fatalf("failed to configure transport: %s", err)
+	}
+	defer func() {
+		_ = transport.Configure(opts)
+	}()
+
+	StartRegistryWithCallback(registryAddress, func() {
+		ctx := context.Background()
+		r, err := ref.New(registryAddress + "/uniget-org/tools/blob:latest")
+		if err != nil {
+			t.Fatalf("failed to parse reference: %s", err)
+		}
+		rc := GetRegclient()
+		//nolint:errcheck
+		defer rc.Close(ctx, r)
+
+		payload := []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+		d := digest.FromBytes(payload)
+		_, err = rc.BlobPut(ctx, r, descriptor.Descriptor{Digest: d, Size: int64(len(payload))}, bytes.NewReader(payload))
+		if err != nil {
+			t.Fatalf("failed to push blob: %s", err)
+		}
+
+		open := openBlob(rc, r, d)
+		for _, offset := range []int64{0, 10, 30} {
+			body, err := open(ctx, offset)
+			if err != nil {
+				t.Fatalf("failed to open blob at offset %d: %s", offset, err)
+			}
+			data, err := io.ReadAll(body)
+			_ = body.Close()
+			if err != nil {
+				t.Fatalf("failed to read blob at offset %d: %s", offset, err)
+			}
+			if !bytes.Equal(data, payload[offset:]) {
+				t.Errorf("expected %q at offset %d but got %q", payload[offset:], offset, data)
+			}
+		}
+	})
+}
+
+func TestIsPermanentBlobError(t *testing.T) {
+	tests := []struct {
+		err       error
+		permanent bool
+	}{
+		{err: fmt.Errorf("failed to get blob: %w", errs.ErrNotFound), permanent: true},
+		{err: fmt.Errorf("failed to get blob: %w", errs.ErrHTTPUnauthorized), permanent: true},
+		{err: fmt.Errorf("failed to get blob: %w", context.Canceled), permanent: true},
+		{err: fmt.Errorf("failed to get blob: %w", io.ErrUnexpectedEOF), permanent: false},
+		{err: fmt.Errorf("failed to get blob: %w", &net.OpError{Op: "dial", Err: errors.New("connection refused")}), permanent: false},
+	}
+	for _, tt := range tests {
+		if isPermanentBlobError(tt.err) != tt.permanent {
+			t.Errorf("expected permanent=%v for %s", tt.permanent, tt.err)
+		}
+	}
+}
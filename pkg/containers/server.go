@@ -5,14 +5,46 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/distribution/distribution/v3/configuration"
 	"github.com/distribution/distribution/v3/registry"
+	_ "github.com/distribution/distribution/v3/registry/auth/htpasswd"
 	_ "github.com/distribution/distribution/v3/registry/storage/driver/inmemory"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func CreateRegistry(registryAddress string) (*registry.Registry, error) {
+	return createRegistry(registryAddress, nil)
+}
+
+// CreateRegistryWithAuth creates a registry which requires basic auth for
+// the users in the given htpasswd file
+func CreateRegistryWithAuth(registryAddress string, htpasswdFile string) (*registry.Registry, error) {
+	return createRegistry(registryAddress, configuration.Auth{
+		"htpasswd": configuration.Parameters{
+			"realm": "uniget",
+			"path":  htpasswdFile,
+		},
+	})
+}
+
+// WriteHtpasswdFile creates an htpasswd file containing a single user
+func WriteHtpasswdFile(path string, username string, password string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %s", err)
+	}
+
+	err = os.WriteFile(path, []byte(username+":"+string(hash)+"\n"), 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write htpasswd file: %s", err)
+	}
+	return nil
+}
+
+func createRegistry(registryAddress string, auth configuration.Auth) (*registry.Registry, error) {
 	// https://distribution.github.io/distribution/about/configuration/
 	const distributionConfig = `
 version: 0.1
@@ -32,6 +64,7 @@ storage:
 		return nil, fmt.Errorf("failed to parse distribution configuration: %s", err)
 	}
 	config.HTTP.Addr = registryAddress
+	config.Auth = auth
 
 	registry, err := registry.NewRegistry(ctx, config)
 	if err != nil {
@@ -26,7 +26,7 @@ func NewToolRef(registry, repository, tool, version string) *ToolRef {
 	}
 }
 
-func FindToolRef(registries, repositories []string, tool, version string) (*ToolRef, error) {
+func FindToolRef(ctx context.Context, registries, repositories []string, tool, version string) (*ToolRef, error) {
 	if len(registries) == 0 {
 		return nil, fmt.Errorf("no registries provided")
 	}
@@ -40,7 +40,7 @@ func FindToolRef(registries, repositories []string, tool, version string) (*Tool
 	for index := range registries {
 		toolRef := NewToolRef(registries[index], repositories[index], tool, version)
 		logging.Tracef("Checking %s", toolRef)
-		if toolRef.ManifestExists() {
+		if toolRef.ManifestExists(ctx) {
 			logging.Tracef("Found %s", toolRef)
 			return toolRef, nil
 		}
@@ -48,10 +48,9 @@ func FindToolRef(registries, repositories []string, tool, version string) (*Tool
 	return nil, fmt.Errorf("tool %s:%s not found in sources", tool, version)
 }
 
-func (t *ToolRef) ManifestExists() bool {
+func (t *ToolRef) ManifestExists(ctx context.Context) bool {
 	ref := t.GetRef()
 
-	ctx := context.Background()
 	rc := GetRegclient()
 	//nolint:errcheck
 	defer rc.Close(ctx, ref)
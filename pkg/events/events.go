@@ -0,0 +1,160 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pterm/pterm"
+
+	"gitlab.com/uniget-org/cli/pkg/logging"
+)
+
+type Type string
+
+const (
+	TypePlanComputed     Type = "plan_computed"
+	TypeStarted          Type = "started"
+	TypeDownloadStarted  Type = "download_started"
+	TypeDownloadProgress Type = "download_progress"
+	TypeExtracted        Type = "extracted"
+	TypeHookRun          Type = "hook_run"
+	TypeSucceeded        Type = "succeeded"
+	TypeSkipped          Type = "skipped"
+	TypeFailed           Type = "failed"
+	TypeMetadataUpdated  Type = "metadata_updated"
+	TypeMetadataCurrent  Type = "metadata_current"
+	TypeToolAdded        Type = "tool_added"
+	TypeToolUpdated      Type = "tool_updated"
+	TypeNews             Type = "news"
+	TypeFinished         Type = "finished"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+var Formats = []string{FormatText, FormatJSON}
+
+type Event struct {
+	Time        time.Time `json:"time"`
+	Command     string    `json:"command,omitempty"`
+	Type        Type      `json:"type"`
+	Tool        string    `json:"tool,omitempty"`
+	Version     string    `json:"version,omitempty"`
+	OldVersion  string    `json:"old_version,omitempty"`
+	Digest      string    `json:"digest,omitempty"`
+	Tools       []string  `json:"tools,omitempty"`
+	Revision    string    `json:"revision,omitempty"`
+	OldRevision string    `json:"old_revision,omitempty"`
+	Bytes       int64     `json:"bytes,omitempty"`
+	Total       int64     `json:"total,omitempty"`
+	Files       int       `json:"files,omitempty"`
+	DurationMs  int64     `json:"duration_ms,omitempty"`
+	Hook        string    `json:"hook,omitempty"`
+	Reason      string    `json:"reason,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	Message     string    `json:"message,omitempty"`
+}
+
+func (e *Event) SetDuration(d time.Duration) {
+	e.DurationMs = d.Milliseconds()
+}
+
+// Emitter renders events either as human readable log messages or as
+// newline delimited JSON. Commands emit the same events in both modes.
+type Emitter struct {
+	mutex    sync.Mutex
+	writer   io.Writer
+	format   string
+	command  string
+	sinks    []func(Event)
+	progress bool
+}
+
+func NewEmitter(writer io.Writer, format string) *Emitter {
+	return &Emitter{
+		writer: writer,
+		format: format,
+	}
+}
+
+func NewTextEmitter() *Emitter {
+	return NewEmitter(os.Stdout, FormatText)
+}
+
+func (e *Emitter) SetCommand(command string) {
+	e.command = command
+}
+
+// AddSink registers a function which receives every event regardless of
+// the output format
+func (e *Emitter) AddSink(sink func(Event)) {
+	e.sinks = append(e.sinks, sink)
+}
+
+func (e *Emitter) IsJSON() bool {
+	return e.format == FormatJSON
+}
+
+// EnableProgress makes downloads emit progress events in text mode as well
+// so that sinks can follow them
+func (e *Emitter) EnableProgress() {
+	e.progress = true
+}
+
+func (e *Emitter) EmitsProgress() bool {
+	return e.progress || e.IsJSON()
+}
+
+func (e *Emitter) Emit(event Event) {
+	event.Time = time.Now().UTC()
+	event.Command = e.command
+
+	for _, sink := range e.sinks {
+		sink(event)
+	}
+
+	if e.IsJSON() {
+		data, err := json.Marshal(event)
+		if err != nil {
+			logging.Warning.Printfln("Unable to marshal event: %s", err)
+			return
+		}
+
+		e.mutex.Lock()
+		defer e.mutex.Unlock()
+		//nolint:errcheck
+		fmt.Fprintln(e.writer, string(data))
+		return
+	}
+
+	if len(event.Message) == 0 {
+		return
+	}
+	switch event.Type {
+	case TypeSucceeded:
+		logging.Success.Println(event.Message)
+	case TypeSkipped:
+		logging.Skip.Println(event.Message)
+	case TypeFailed:
+		logging.Error.Println(event.Message)
+	case TypeStarted, TypeMetadataCurrent:
+		logging.Info.Println(event.Message)
+	case TypeHookRun:
+		//nolint:errcheck
+		fmt.Fprintln(logging.OutputWriter, event.Message)
+	case TypeToolAdded:
+		logging.Customf(pterm.FgBlack, pterm.BgGreen, pterm.FgWhite, pterm.BgDefault, "NEW", " %s", event.Message)
+	case TypeToolUpdated:
+		logging.Customf(pterm.FgBlack, pterm.BgYellow, pterm.FgWhite, pterm.BgDefault, "UPDATE", "%s", event.Message)
+	case TypeNews:
+		logging.Customf(pterm.FgBlack, pterm.BgYellow, pterm.FgWhite, pterm.BgDefault, "NEWS", "%s", event.Message)
+	default:
+		logging.Debug(event.Message)
+	}
+}
@@ -0,0 +1,83 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestEmitJSON(t *testing.T) {
+	var buf bytes.Buffer
+	emitter := NewEmitter(&buf, FormatJSON)
+	emitter.SetCommand("install")
+
+	emitter.Emit(Event{Type: TypePlanComputed, Tools: []string{"jq", "yq"}})
+	emitter.Emit(Event{Type: TypeSucceeded, Tool: "jq", Version: "1.7.1", Message: "jq 1.7.1"})
+
+	scanner := bufio.NewScanner(&buf)
+	var received []Event
+	for scanner.Scan() {
+		var event Event
+		err := json.Unmarshal(scanner.Bytes(), &event)
+		if err != nil {
+			t.Fatalf("failed to unmarshal line %q: %v", scanner.Text(), err)
+		}
+		received = append(received, event)
+	}
+
+	if len(received) != 2 {
+		t.Fatalf("expected 2 events but got %d", len(received))
+	}
+	if received[0].Type != TypePlanComputed || len(received[0].Tools) != 2 {
+		t.Errorf("unexpected first event: %+v", received[0])
+	}
+	if received[1].Command != "install" || received[1].Tool != "jq" || received[1].Time.IsZero() {
+		t.Errorf("unexpected second event: %+v", received[1])
+	}
+}
+
+func TestEmitTextIgnoresWriter(t *testing.T) {
+	var buf bytes.Buffer
+	emitter := NewEmitter(&buf, FormatText)
+	emitter.Emit(Event{Type: TypePlanComputed, Tools: []string{"jq"}})
+
+	if buf.Len() > 0 {
+		t.Errorf("expected no JSON output in text mode but got %s", buf.String())
+	}
+}
+
+func TestProgressReader(t *testing.T) {
+	var buf bytes.Buffer
+	emitter := NewEmitter(&buf, FormatJSON)
+
+	content := strings.Repeat("x", 1024)
+	progressReader := emitter.NewProgressReader("jq", "1.7.1", nil)
+	progressReader.SetDigest("sha256:1234")
+	progressReader.SetTotal(int64(len(content)))
+	progressReader.SetReader(io.NopCloser(strings.NewReader(content)))
+	_, err := io.Copy(io.Discard, progressReader)
+	if err != nil {
+		t.Fatalf("failed to read: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var first, last Event
+	err = json.Unmarshal([]byte(lines[0]), &first)
+	if err != nil {
+		t.Fatalf("failed to unmarshal first event: %v", err)
+	}
+	err = json.Unmarshal([]byte(lines[len(lines)-1]), &last)
+	if err != nil {
+		t.Fatalf("failed to unmarshal last event: %v", err)
+	}
+
+	if first.Type != TypeDownloadStarted || first.Digest != "sha256:1234" || first.Total != 1024 {
+		t.Errorf("unexpected first event: %+v", first)
+	}
+	if last.Type != TypeDownloadProgress || last.Bytes != 1024 {
+		t.Errorf("unexpected last event: %+v", last)
+	}
+}
@@ -0,0 +1,57 @@
+package events
+
+import (
+	"time"
+
+	"gitlab.com/uniget-org/cli/pkg/tui"
+)
+
+const progressInterval = 500 * time.Millisecond
+
+func (e *Emitter) NewProgressReader(tool string, version string, onDigest func(string)) tui.ProgressReader {
+	var digest string
+	var total int64
+	var bytes int64
+	var lastProgress time.Time
+	started := time.Now()
+
+	progressReader := tui.NewProgressReader(
+		func(n int64) {
+			total = n
+			started = time.Now()
+			e.Emit(Event{
+				Type:    TypeDownloadStarted,
+				Tool:    tool,
+				Version: version,
+				Digest:  digest,
+				Total:   total,
+			})
+		},
+		func(n int64) {
+			bytes += n
+			if bytes < total && time.Since(lastProgress) < progressInterval {
+				return
+			}
+			lastProgress = time.Now()
+
+			event := Event{
+				Type:    TypeDownloadProgress,
+				Tool:    tool,
+				Version: version,
+				Digest:  digest,
+				Bytes:   bytes,
+				Total:   total,
+			}
+			event.SetDuration(time.Since(started))
+			e.Emit(event)
+		},
+	)
+	progressReader.OnDigest(func(d string) {
+		digest = d
+		if onDigest != nil {
+			onDigest(d)
+		}
+	})
+
+	return progressReader
+}
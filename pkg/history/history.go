@@ -0,0 +1,186 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ActionInstall        = "install"
+	ActionUpgrade        = "upgrade"
+	ActionReinstall      = "reinstall"
+	ActionUninstall      = "uninstall"
+	ActionRollback       = "rollback"
+	ActionMetadataUpdate = "metadata-update"
+
+	ResultSucceeded = "succeeded"
+	ResultFailed    = "failed"
+)
+
+type Entry struct {
+	Time        time.Time `json:"time"`
+	User        string    `json:"user"`
+	SudoUser    string    `json:"sudo_user,omitempty"`
+	CommandLine string    `json:"command_line"`
+	Action      string    `json:"action"`
+	Tool        string    `json:"tool,omitempty"`
+	OldVersion  string    `json:"old_version,omitempty"`
+	NewVersion  string    `json:"new_version,omitempty"`
+	Digest      string    `json:"digest,omitempty"`
+	Result      string    `json:"result"`
+	Error       string    `json:"error,omitempty"`
+}
+
+func NewEntry(action string, tool string, oldVersion string, newVersion string) Entry {
+	return Entry{
+		Time:        time.Now().UTC(),
+		User:        currentUser(),
+		SudoUser:    os.Getenv("SUDO_USER"),
+		CommandLine: strings.Join(os.Args, " "),
+		Action:      action,
+		Tool:        tool,
+		OldVersion:  oldVersion,
+		NewVersion:  newVersion,
+		Result:      ResultSucceeded,
+	}
+}
+
+func (e Entry) String() string {
+	var result strings.Builder
+	fmt.Fprintf(&result, "%s %s", e.Action, e.Result)
+	if len(e.Tool) > 0 {
+		fmt.Fprintf(&result, " tool=%s", e.Tool)
+	}
+	if len(e.OldVersion) > 0 {
+		fmt.Fprintf(&result, " old_version=%s", e.OldVersion)
+	}
+	if len(e.NewVersion) > 0 {
+		fmt.Fprintf(&result, " new_version=%s", e.NewVersion)
+	}
+	if len(e.Digest) > 0 {
+		fmt.Fprintf(&result, " digest=%s", e.Digest)
+	}
+	fmt.Fprintf(&result, " user=%s", e.User)
+	if len(e.Error) > 0 {
+		fmt.Fprintf(&result, " error=%q", e.Error)
+	}
+	return result.String()
+}
+
+func currentUser() string {
+	u, err := user.Current()
+	if err != nil {
+		return strconv.Itoa(os.Getuid())
+	}
+	return u.Username
+}
+
+func Append(path string, entry Entry) error {
+	err := os.MkdirAll(filepath.Dir(path), 0755) // #nosec G301 -- Directory must be accessible by all users
+	if err != nil {
+		return fmt.Errorf("unable to create directory for %s: %s", path, err)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("unable to marshal history entry: %s", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644) // #nosec G302 G304 -- History must be world-readable
+	if err != nil {
+		return fmt.Errorf("unable to open %s: %s", path, err)
+	}
+	//nolint:errcheck
+	defer file.Close()
+
+	_, err = file.Write(append(data, '\n'))
+	if err != nil {
+		return fmt.Errorf("unable to write to %s: %s", path, err)
+	}
+
+	return nil
+}
+
+type Filter struct {
+	Tool  string
+	Since time.Time
+}
+
+func (f Filter) Matches(entry Entry) bool {
+	if len(f.Tool) > 0 && entry.Tool != f.Tool {
+		return false
+	}
+	if !f.Since.IsZero() && entry.Time.Before(f.Since) {
+		return false
+	}
+	return true
+}
+
+func Read(path string, filter Filter) ([]Entry, error) {
+	entries := make([]Entry, 0)
+
+	file, err := os.Open(path) // #nosec G304 -- Path is built from configuration
+	if os.IsNotExist(err) {
+		return entries, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to open %s: %s", path, err)
+	}
+	//nolint:errcheck
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var entry Entry
+		err := json.Unmarshal(line, &entry)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse line %d of %s: %s", lineNumber, path, err)
+		}
+		if filter.Matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %s", path, err)
+	}
+
+	return entries, nil
+}
+
+// ParseSince accepts a date (2006-01-02), a timestamp (RFC 3339) or a
+// duration relative to now (e.g. 36h or 7d)
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return t, nil
+	}
+
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	d, err := time.ParseDuration(value)
+	if err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+
+	return time.Time{}, fmt.Errorf("unable to parse %s as date, timestamp or duration", value)
+}
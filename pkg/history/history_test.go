@@ -0,0 +1,80 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAppendAndRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lib", "history.jsonl")
+
+	old := NewEntry(ActionInstall, "jq", "", "1.7.0")
+	old.Time = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	upgrade := NewEntry(ActionUpgrade, "jq", "1.7.0", "1.7.1")
+	upgrade.Time = time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	other := NewEntry(ActionInstall, "yq", "", "4.0.0")
+	other.Time = time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC)
+
+	for _, entry := range []Entry{old, upgrade, other} {
+		err := Append(path, entry)
+		if err != nil {
+			t.Fatalf("failed to append entry: %v", err)
+		}
+	}
+
+	entries, err := Read(path, Filter{})
+	if err != nil {
+		t.Fatalf("failed to read history: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries but got %d", len(entries))
+	}
+
+	entries, err = Read(path, Filter{Tool: "jq", Since: time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("failed to read history: %v", err)
+	}
+	if len(entries) != 1 || entries[0].NewVersion != "1.7.1" || entries[0].OldVersion != "1.7.0" {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+}
+
+func TestReadMissingFile(t *testing.T) {
+	entries, err := Read(filepath.Join(t.TempDir(), "history.jsonl"), Filter{})
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no entries but got %d", len(entries))
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tt := []struct {
+		value    string
+		expected time.Time
+	}{
+		{value: "2026-03-01T00:00:00Z", expected: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{value: "36h", expected: time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)},
+		{value: "7d", expected: time.Date(2026, 3, 3, 12, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range tt {
+		t.Run(tc.value, func(t *testing.T) {
+			since, err := ParseSince(tc.value, now)
+			if err != nil {
+				t.Fatalf("failed to parse %s: %v", tc.value, err)
+			}
+			if !since.Equal(tc.expected) {
+				t.Errorf("expected %s but got %s", tc.expected, since)
+			}
+		})
+	}
+
+	_, err := ParseSince("yesterday", now)
+	if err == nil {
+		t.Errorf("expected error for invalid value")
+	}
+}
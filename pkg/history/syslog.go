@@ -0,0 +1,28 @@
+package history
+
+import (
+	"fmt"
+	"log/syslog"
+)
+
+// Forward sends the entry to the local syslog daemon. journald collects
+// these messages as well.
+func Forward(entry Entry) error {
+	writer, err := syslog.New(syslog.LOG_INFO|syslog.LOG_USER, "uniget")
+	if err != nil {
+		return fmt.Errorf("unable to connect to syslog: %s", err)
+	}
+	//nolint:errcheck
+	defer writer.Close()
+
+	if entry.Result == ResultFailed {
+		err = writer.Warning(entry.String())
+	} else {
+		err = writer.Info(entry.String())
+	}
+	if err != nil {
+		return fmt.Errorf("unable to write to syslog: %s", err)
+	}
+
+	return nil
+}
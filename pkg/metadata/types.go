@@ -1,6 +1,7 @@
 package metadata
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -51,13 +52,13 @@ func NewMetadataSource(
 	}, nil
 }
 
-func (m *MetadataSource) Download(p tui.ProgressReader) error {
+func (m *MetadataSource) Download(ctx context.Context, p tui.ProgressReader) error {
 	err := os.Chdir(m.Directory)
 	if err != nil {
 		return fmt.Errorf("error changing directory to %s: %s", m.Directory, err)
 	}
 
-	err = (*m.Downloader).Get(m.Source, p, func(reader io.ReadCloser) error {
+	err = (*m.Downloader).Get(ctx, m.Source, p, func(reader io.ReadCloser) error {
 		return (*m.Unpacker).Unpack(reader)
 	})
 	if err != nil {
@@ -1,6 +1,7 @@
 package metadata
 
 import (
+	"context"
 	"errors"
 	"io"
 	"os"
@@ -20,7 +21,7 @@ type fakeDownloader struct {
 	data   string
 }
 
-func (d *fakeDownloader) Get(src *source.Source, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
+func (d *fakeDownloader) Get(ctx context.Context, src *source.Source, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
 	d.called = true
 	d.src = src
 	if d.err != nil {
@@ -136,7 +137,7 @@ func TestMetadataSourceDownload(t *testing.T) {
 			Verifier:   &verifier,
 		}
 
-		err = metadataSource.Download(tui.NewQuietProgressReader())
+		err = metadataSource.Download(context.Background(), tui.NewQuietProgressReader())
 		if err != nil {
 			t.Fatalf("Download() unexpected error: %v", err)
 		}
@@ -181,7 +182,7 @@ func TestMetadataSourceDownload(t *testing.T) {
 			Verifier:   &verifier,
 		}
 
-		err := metadataSource.Download(tui.NewQuietProgressReader())
+		err := metadataSource.Download(context.Background(), tui.NewQuietProgressReader())
 		if err == nil {
 			t.Fatal("Download() expected error when directory does not exist, got nil")
 		}
@@ -219,7 +220,7 @@ func TestMetadataSourceDownload(t *testing.T) {
 			Verifier:   &verifier,
 		}
 
-		err = metadataSource.Download(tui.NewQuietProgressReader())
+		err = metadataSource.Download(context.Background(), tui.NewQuietProgressReader())
 		if err == nil {
 			t.Fatal("Download() expected downloader error, got nil")
 		}
@@ -260,7 +261,7 @@ func TestMetadataSourceDownload(t *testing.T) {
 			Verifier:   &verifier,
 		}
 
-		err = metadataSource.Download(tui.NewQuietProgressReader())
+		err = metadataSource.Download(context.Background(), tui.NewQuietProgressReader())
 		if err == nil {
 			t.Fatal("Download() expected verifier error, got nil")
 		}
@@ -1,6 +1,7 @@
 package os
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -163,6 +164,32 @@ func CloneFile(src, dst string) (err error) {
 	return nil
 }
 
+type contextReader struct {
+	ctx    context.Context
+	reader io.ReadCloser
+}
+
+// NewContextReader returns a reader which fails with the error of the
+// context as soon as the context is done
+func NewContextReader(ctx context.Context, reader io.ReadCloser) io.ReadCloser {
+	return &contextReader{
+		ctx:    ctx,
+		reader: reader,
+	}
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	err := r.ctx.Err()
+	if err != nil {
+		return 0, err
+	}
+	return r.reader.Read(p)
+}
+
+func (r *contextReader) Close() error {
+	return r.reader.Close()
+}
+
 func DirectoryExists(directory string) bool {
 	_, err := os.Stat(directory)
 	return err == nil
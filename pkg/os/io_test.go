@@ -1,6 +1,10 @@
 package os
 
 import (
+	"context"
+	"errors"
+	"io"
+	"strings"
 	"testing"
 )
 
@@ -24,3 +28,20 @@ func TestFileMode(t *testing.T) {
 		}
 	}
 }
+
+func TestContextReader(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	reader := NewContextReader(ctx, io.NopCloser(strings.NewReader("data")))
+
+	buf := make([]byte, 2)
+	n, err := reader.Read(buf)
+	if err != nil || n != 2 {
+		t.Fatalf("expected to read 2 bytes, got %d and %v", n, err)
+	}
+
+	cancel()
+	_, err = reader.Read(buf)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
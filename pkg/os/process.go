@@ -0,0 +1,39 @@
+package os
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+)
+
+func FindProcessesRunningFile(info os.FileInfo) ([]int, error) {
+	return findProcessesRunningFile("/proc", info)
+}
+
+func findProcessesRunningFile(procDirectory string, info os.FileInfo) ([]int, error) {
+	entries, err := os.ReadDir(procDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %s", procDirectory, err)
+	}
+
+	pids := []int{}
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		// The link keeps pointing to the old inode after it was replaced
+		exeInfo, err := os.Stat(procDirectory + "/" + entry.Name() + "/exe")
+		if err != nil {
+			continue
+		}
+		if os.SameFile(info, exeInfo) {
+			pids = append(pids, pid)
+		}
+	}
+	slices.Sort(pids)
+
+	return pids, nil
+}
@@ -0,0 +1,50 @@
+package os
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestFindProcessesRunningFile(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatalf("failed to get executable: %v", err)
+	}
+	info, err := os.Stat(exe)
+	if err != nil {
+		t.Fatalf("failed to stat executable: %v", err)
+	}
+	if _, err := os.Stat("/proc/self/exe"); err != nil {
+		t.Skipf("procfs is not available: %v", err)
+	}
+
+	pids, err := FindProcessesRunningFile(info)
+	if err != nil {
+		t.Fatalf("failed to find processes: %v", err)
+	}
+	if !slices.Contains(pids, os.Getpid()) {
+		t.Errorf("expected %d to be contained in %v", os.Getpid(), pids)
+	}
+}
+
+func TestFindProcessesRunningFileUnused(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "unused")
+	err := os.WriteFile(file, []byte("unused"), 0600)
+	if err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatalf("failed to stat file: %v", err)
+	}
+
+	pids, err := findProcessesRunningFile(t.TempDir(), info)
+	if err != nil {
+		t.Fatalf("failed to find processes: %v", err)
+	}
+	if len(pids) > 0 {
+		t.Errorf("expected no processes but got %v", pids)
+	}
+}
@@ -2,6 +2,7 @@ package parse
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"slices"
 
@@ -47,7 +48,7 @@ func (imageRefs *ImageRefs) Bump(tools *tool.Tools, callback func(toolName strin
 			reference.Tag = tool.Version
 			reference.Digest = ""
 			reference.Reference = fmt.Sprintf("%s/%s:%s", reference.Registry, reference.Repository, reference.Tag)
-			reference.Digest, err = containers.FindNewDigest(reference)
+			reference.Digest, err = containers.FindNewDigest(context.Background(), reference)
 			if err != nil {
 				return fmt.Errorf("failed to find new digest for %s: %w", reference, err)
 			}
@@ -0,0 +1,391 @@
+package query
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	goversion "github.com/hashicorp/go-version"
+
+	"gitlab.com/uniget-org/cli/pkg/tool"
+)
+
+// Fields lists the fields supported in expressions like tag:kubernetes
+var Fields = []string{
+	"name",
+	"description",
+	"tag",
+	"license",
+	"version",
+	"dep",
+	"builddep",
+	"conflicts",
+	"platform",
+	"installed",
+	"upgradable",
+	"renamed",
+	"removed",
+}
+
+type node interface {
+	match(t *tool.Tool) bool
+	needsStatus() bool
+}
+
+// Query is a parsed expression like
+//
+//	tag:kubernetes AND NOT tag:deprecated license:Apache-2.0 installed:true
+//
+// Terms are combined using AND, OR and NOT as well as parentheses. Terms
+// without an operator between them must all match. Terms without a field
+// match the name or description.
+type Query struct {
+	expression string
+	root       node
+}
+
+func Parse(expression string) (*Query, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	if len(tokens) == 0 {
+		return &Query{expression: expression, root: matchAll{}}, nil
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.position < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %s at position %d", p.tokens[p.position].value, p.tokens[p.position].offset+1)
+	}
+
+	return &Query{expression: expression, root: root}, nil
+}
+
+func (q *Query) String() string {
+	return q.expression
+}
+
+func (q *Query) Match(t *tool.Tool) bool {
+	return q.root.match(t)
+}
+
+// NeedsStatus reports whether the status of tools must be updated before
+// the query is evaluated
+func (q *Query) NeedsStatus() bool {
+	return q.root.needsStatus()
+}
+
+func (q *Query) Filter(tools *tool.Tools) *tool.Tools {
+	results := &tool.Tools{
+		Revision: tools.Revision,
+	}
+	for index := range tools.Tools {
+		if q.Match(&tools.Tools[index]) {
+			results.Tools = append(results.Tools, tools.Tools[index])
+		}
+	}
+	return results
+}
+
+const (
+	tokenWord = iota
+	tokenOpen
+	tokenClose
+)
+
+type token struct {
+	kind   int
+	value  string
+	offset int
+}
+
+func tokenize(expression string) ([]token, error) {
+	tokens := make([]token, 0)
+	runes := []rune(expression)
+
+	for i := 0; i < len(runes); {
+		switch {
+		case unicode.IsSpace(runes[i]):
+			i++
+
+		case runes[i] == '(':
+			tokens = append(tokens, token{kind: tokenOpen, value: "(", offset: i})
+			i++
+
+		case runes[i] == ')':
+			tokens = append(tokens, token{kind: tokenClose, value: ")", offset: i})
+			i++
+
+		default:
+			start := i
+			var word strings.Builder
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '(' && runes[i] != ')' {
+				if runes[i] == '"' {
+					end := slices.Index(runes[i+1:], '"')
+					if end < 0 {
+						return nil, fmt.Errorf("unterminated quote at position %d", i+1)
+					}
+					word.WriteString(string(runes[i+1 : i+1+end]))
+					i += end + 2
+					continue
+				}
+				word.WriteRune(runes[i])
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenWord, value: word.String(), offset: start})
+		}
+	}
+
+	return tokens, nil
+}
+
+type parser struct {
+	tokens   []token
+	position int
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.position >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.position], true
+}
+
+func (p *parser) isKeyword(keyword string) bool {
+	t, ok := p.peek()
+	return ok && t.kind == tokenWord && t.value == keyword
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("OR") {
+		p.position++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = or{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.peek()
+		if !ok || t.kind == tokenClose || p.isKeyword("OR") {
+			return left, nil
+		}
+		if p.isKeyword("AND") {
+			p.position++
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = and{left, right}
+	}
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.isKeyword("NOT") {
+		p.position++
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return not{operand}, nil
+	}
+	return p.parseTerm()
+}
+
+func (p *parser) parseTerm() (node, error) {
+	t, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("unexpected end of query")
+	}
+	p.position++
+
+	switch t.kind {
+	case tokenOpen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		closing, ok := p.peek()
+		if !ok || closing.kind != tokenClose {
+			return nil, fmt.Errorf("missing closing parenthesis for position %d", t.offset+1)
+		}
+		p.position++
+		return inner, nil
+
+	case tokenClose:
+		return nil, fmt.Errorf("unexpected ) at position %d", t.offset+1)
+	}
+
+	if t.value == "AND" || t.value == "OR" {
+		return nil, fmt.Errorf("unexpected %s at position %d", t.value, t.offset+1)
+	}
+	return newTerm(t.value)
+}
+
+func newTerm(word string) (node, error) {
+	field, value, found := strings.Cut(word, ":")
+	if !found {
+		return text{value: strings.ToLower(word)}, nil
+	}
+	if !slices.Contains(Fields, field) {
+		return nil, fmt.Errorf("unknown field %s (supported: %s)", field, strings.Join(Fields, ", "))
+	}
+	if len(value) == 0 {
+		return nil, fmt.Errorf("missing value for field %s", field)
+	}
+
+	switch field {
+	case "installed", "upgradable", "renamed", "removed":
+		expected, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("value for field %s must be true or false", field)
+		}
+		return flag{field: field, expected: expected}, nil
+
+	case "version":
+		if strings.ContainsAny(value[:1], "<>=!~") {
+			constraint, err := goversion.NewConstraint(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version constraint %s: %s", value, err)
+			}
+			return versionConstraint{constraint: constraint}, nil
+		}
+	}
+
+	_, err := path.Match(value, "")
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %s: %s", value, err)
+	}
+	return fieldMatch{field: field, pattern: value}, nil
+}
+
+type matchAll struct{}
+
+func (matchAll) match(*tool.Tool) bool { return true }
+func (matchAll) needsStatus() bool     { return false }
+
+type and [2]node
+
+func (n and) match(t *tool.Tool) bool { return n[0].match(t) && n[1].match(t) }
+func (n and) needsStatus() bool       { return n[0].needsStatus() || n[1].needsStatus() }
+
+type or [2]node
+
+func (n or) match(t *tool.Tool) bool { return n[0].match(t) || n[1].match(t) }
+func (n or) needsStatus() bool       { return n[0].needsStatus() || n[1].needsStatus() }
+
+type not [1]node
+
+func (n not) match(t *tool.Tool) bool { return !n[0].match(t) }
+func (n not) needsStatus() bool       { return n[0].needsStatus() }
+
+type text struct {
+	value string
+}
+
+func (n text) match(t *tool.Tool) bool {
+	return strings.Contains(strings.ToLower(t.Name), n.value) ||
+		strings.Contains(strings.ToLower(t.Description), n.value)
+}
+
+func (n text) needsStatus() bool { return false }
+
+type flag struct {
+	field    string
+	expected bool
+}
+
+func (n flag) match(t *tool.Tool) bool {
+	var actual bool
+	switch n.field {
+	case "installed":
+		actual = t.IsInstalled()
+	case "upgradable":
+		actual = t.IsUpgradable()
+	case "renamed":
+		actual = len(t.Lifecycle.RenamedTo) > 0
+	case "removed":
+		actual = len(t.Lifecycle.RemovedWithReason) > 0
+	}
+	return actual == n.expected
+}
+
+func (n flag) needsStatus() bool {
+	return n.field == "installed" || n.field == "upgradable"
+}
+
+type versionConstraint struct {
+	constraint goversion.Constraints
+}
+
+func (n versionConstraint) match(t *tool.Tool) bool {
+	v, err := goversion.NewVersion(t.Version)
+	if err != nil {
+		return false
+	}
+	return n.constraint.Check(v)
+}
+
+func (n versionConstraint) needsStatus() bool { return false }
+
+// fieldMatch compares a field with a glob pattern ignoring case
+type fieldMatch struct {
+	field   string
+	pattern string
+}
+
+func (n fieldMatch) matches(value string) bool {
+	match, err := path.Match(strings.ToLower(n.pattern), strings.ToLower(value))
+	return err == nil && match
+}
+
+func (n fieldMatch) matchesAny(values []string) bool {
+	return slices.ContainsFunc(values, n.matches)
+}
+
+func (n fieldMatch) match(t *tool.Tool) bool {
+	switch n.field {
+	case "name":
+		return n.matches(t.Name)
+	case "description":
+		return strings.Contains(strings.ToLower(t.Description), strings.ToLower(n.pattern))
+	case "tag":
+		return n.matchesAny(t.Tags)
+	case "license":
+		return n.matches(t.License.Name)
+	case "version":
+		return n.matches(t.Version)
+	case "dep":
+		return n.matchesAny(t.RuntimeDependencies)
+	case "builddep":
+		return n.matchesAny(t.BuildDependencies)
+	case "conflicts":
+		return n.matchesAny(t.ConflictsWith)
+	case "platform":
+		// Tools without platforms are available everywhere
+		return len(t.Platforms) == 0 || n.matchesAny(t.Platforms)
+	}
+	return false
+}
+
+func (n fieldMatch) needsStatus() bool { return false }
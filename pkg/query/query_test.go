@@ -0,0 +1,111 @@
+package query
+
+import (
+	"strings"
+	"testing"
+
+	"gitlab.com/uniget-org/cli/pkg/tool"
+)
+
+var testTools = &tool.Tools{
+	Tools: []tool.Tool{
+		{
+			Name:        "helm",
+			Version:     "3.14.0",
+			Description: "Package manager for Kubernetes",
+			Tags:        []string{"kubernetes", "package"},
+			License:     tool.License{Name: "Apache-2.0"},
+			Status:      tool.ToolStatus{BinaryPresent: true, MarkerFilePresent: true, VersionMatches: false},
+		},
+		{
+			Name:                "k9s",
+			Version:             "0.32.4",
+			Description:         "Terminal UI for Kubernetes clusters",
+			Tags:                []string{"kubernetes", "deprecated"},
+			License:             tool.License{Name: "Apache-2.0"},
+			RuntimeDependencies: []string{"kubectl"},
+			Platforms:           []string{"linux/amd64"},
+		},
+		{
+			Name:        "jq",
+			Version:     "1.7.1",
+			Description: "Command-line JSON processor",
+			Tags:        []string{"json"},
+			License:     tool.License{Name: "MIT"},
+			Status:      tool.ToolStatus{BinaryPresent: true, MarkerFilePresent: true, VersionMatches: true},
+		},
+		{
+			Name:      "oldjq",
+			Version:   "1.0.0",
+			Lifecycle: tool.Lifecycle{RenamedTo: "jq"},
+		},
+	},
+}
+
+func names(tools *tool.Tools) string {
+	result := make([]string, 0, len(tools.Tools))
+	for _, t := range tools.Tools {
+		result = append(result, t.Name)
+	}
+	return strings.Join(result, ",")
+}
+
+func TestQuery(t *testing.T) {
+	tt := []struct {
+		query    string
+		expected string
+		status   bool
+	}{
+		{query: "", expected: "helm,k9s,jq,oldjq"},
+		{query: "tag:kubernetes", expected: "helm,k9s"},
+		{query: "tag:kubernetes AND NOT tag:deprecated license:Apache-2.0 installed:true platform:linux/arm64", expected: "helm", status: true},
+		{query: "tag:kubernetes NOT tag:deprecated", expected: "helm"},
+		{query: "tag:json OR name:k*", expected: "k9s,jq"},
+		{query: "(tag:json OR tag:package) AND installed:true", expected: "helm,jq", status: true},
+		{query: "upgradable:true", expected: "helm", status: true},
+		{query: "installed:false", expected: "k9s,oldjq", status: true},
+		{query: "license:mit", expected: "jq"},
+		{query: "dep:kubectl", expected: "k9s"},
+		{query: "platform:linux/arm64", expected: "helm,jq,oldjq"},
+		{query: "version:>=1.0,<3", expected: "jq,oldjq"},
+		{query: "version:3.*", expected: "helm"},
+		{query: "renamed:true", expected: "oldjq"},
+		{query: "kubernetes", expected: "helm,k9s"},
+		{query: `description:"json processor"`, expected: "jq"},
+	}
+
+	for _, tc := range tt {
+		q, err := Parse(tc.query)
+		if err != nil {
+			t.Errorf("unexpected error for %s: %s", tc.query, err)
+			continue
+		}
+		result := names(q.Filter(testTools))
+		if result != tc.expected {
+			t.Errorf("expected %s for %s, got %s", tc.expected, tc.query, result)
+		}
+		if q.NeedsStatus() != tc.status {
+			t.Errorf("expected NeedsStatus to be %t for %s", tc.status, tc.query)
+		}
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	for _, query := range []string{
+		"foo:bar",
+		"tag:",
+		"installed:maybe",
+		"(tag:json",
+		"tag:json)",
+		"tag:json AND",
+		"OR tag:json",
+		`description:"json`,
+		"version:>=foo",
+		"name:[",
+	} {
+		_, err := Parse(query)
+		if err == nil {
+			t.Errorf("expected error for %s", query)
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/sigstore/sigstore-go/pkg/verify"
 	"github.com/theupdateframework/go-tuf/v2/metadata/fetcher"
 	"gitlab.com/uniget-org/cli/pkg/logging"
+	"gitlab.com/uniget-org/cli/pkg/transport"
 )
 
 func GetSigstoreTrustedRoot() (*root.TrustedRoot, error) {
@@ -19,6 +20,7 @@ func GetSigstoreTrustedRoot() (*root.TrustedRoot, error) {
 	opts.RepositoryBaseURL = "https://tuf-repo-cdn.sigstore.dev"
 	fetcher := fetcher.NewDefaultFetcher()
 	fetcher.SetHTTPUserAgent(util.ConstructUserAgent())
+	fetcher.SetHTTPClient(transport.Client())
 	opts.Fetcher = fetcher
 	client, err := tuf.New(opts)
 	if err != nil {
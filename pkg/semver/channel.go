@@ -0,0 +1,149 @@
+package semver
+
+import (
+	"fmt"
+
+	goversion "github.com/hashicorp/go-version"
+)
+
+const (
+	ChannelLatest = "latest"
+	ChannelStable = "stable"
+	ChannelPinned = "pinned"
+	ChannelRange  = "range"
+)
+
+// Channel selects a version from a list of released versions. It is
+// either latest, stable (lagging one minor release), a pinned version or
+// a version constraint like "~> 0.24".
+type Channel struct {
+	Kind       string
+	Spec       string
+	pinned     *goversion.Version
+	constraint goversion.Constraints
+}
+
+func ParseChannel(spec string) (Channel, error) {
+	switch spec {
+	case "", ChannelLatest:
+		return Channel{Kind: ChannelLatest, Spec: ChannelLatest}, nil
+	case ChannelStable:
+		return Channel{Kind: ChannelStable, Spec: spec}, nil
+	}
+
+	pinned, err := goversion.NewVersion(spec)
+	if err == nil {
+		return Channel{Kind: ChannelPinned, Spec: spec, pinned: pinned}, nil
+	}
+
+	constraint, err := goversion.NewConstraint(spec)
+	if err != nil {
+		return Channel{}, fmt.Errorf("invalid channel %s: must be latest, stable, a version or a version constraint", spec)
+	}
+	return Channel{Kind: ChannelRange, Spec: spec, constraint: constraint}, nil
+}
+
+// Resolve returns the version selected by the channel from the given tags.
+// Pre-releases are only considered when pinned explicitly.
+func (c Channel) Resolve(tags []string) (string, error) {
+	var latest *goversion.Version
+	var latestTag string
+	var stable *goversion.Version
+	var stableTag string
+	var matching *goversion.Version
+	var matchingTag string
+
+	versions := make(map[string]*goversion.Version)
+	for _, tag := range tags {
+		v, err := goversion.NewVersion(tag)
+		if err != nil {
+			continue
+		}
+		if c.Kind == ChannelPinned && v.Equal(c.pinned) {
+			return tag, nil
+		}
+		if len(v.Prerelease()) > 0 {
+			continue
+		}
+		versions[tag] = v
+
+		if latest == nil || v.GreaterThan(latest) {
+			latest = v
+			latestTag = tag
+		}
+		if c.Kind == ChannelRange && c.constraint.Check(v) && (matching == nil || v.GreaterThan(matching)) {
+			matching = v
+			matchingTag = tag
+		}
+	}
+
+	switch c.Kind {
+	case ChannelLatest:
+		if latest == nil {
+			return "", fmt.Errorf("no release found")
+		}
+		return latestTag, nil
+
+	case ChannelStable:
+		if latest == nil {
+			return "", fmt.Errorf("no release found")
+		}
+		for tag, v := range versions {
+			if sameMinor(v, latest) {
+				continue
+			}
+			if stable == nil || v.GreaterThan(stable) {
+				stable = v
+				stableTag = tag
+			}
+		}
+		if stable == nil {
+			return latestTag, nil
+		}
+		return stableTag, nil
+
+	case ChannelPinned:
+		return "", fmt.Errorf("pinned version %s not found", c.Spec)
+
+	case ChannelRange:
+		if matching == nil {
+			return "", fmt.Errorf("no release matches %s", c.Spec)
+		}
+		return matchingTag, nil
+	}
+
+	return "", fmt.Errorf("unknown channel %s", c.Kind)
+}
+
+// ShouldUpgrade decides whether to move from the current to the resolved
+// version. Pinned versions and ranges are enforced and may downgrade
+// while latest and stable only move forward.
+func (c Channel) ShouldUpgrade(current string, resolved string) bool {
+	if current == resolved {
+		return false
+	}
+
+	currentVersion, err := goversion.NewVersion(current)
+	if err != nil {
+		return true
+	}
+	resolvedVersion, err := goversion.NewVersion(resolved)
+	if err != nil {
+		return false
+	}
+
+	switch c.Kind {
+	case ChannelPinned:
+		return !currentVersion.Equal(resolvedVersion)
+	case ChannelRange:
+		return !c.constraint.Check(currentVersion) || resolvedVersion.GreaterThan(currentVersion)
+	default:
+		return resolvedVersion.GreaterThan(currentVersion)
+	}
+}
+
+func sameMinor(a *goversion.Version, b *goversion.Version) bool {
+	sa := a.Segments()
+	sb := b.Segments()
+	return sa[0] == sb[0] && sa[1] == sb[1]
+}
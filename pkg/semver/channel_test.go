@@ -0,0 +1,94 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestChannelResolve(t *testing.T) {
+	tags := []string{"0.23.0", "0.24.0", "0.24.3", "0.24.2", "0.25.0", "0.25.1", "0.26.0-rc.1", "latest"}
+
+	tt := []struct {
+		channel  string
+		expected string
+		fails    bool
+	}{
+		{channel: "", expected: "0.25.1"},
+		{channel: "latest", expected: "0.25.1"},
+		{channel: "stable", expected: "0.24.3"},
+		{channel: "0.24.2", expected: "0.24.2"},
+		{channel: "0.26.0-rc.1", expected: "0.26.0-rc.1"},
+		{channel: "~> 0.24.0", expected: "0.24.3"},
+		{channel: ">= 0.23, < 0.25", expected: "0.24.3"},
+		{channel: "0.22.0", fails: true},
+		{channel: "> 1.0", fails: true},
+	}
+
+	for _, tc := range tt {
+		channel, err := ParseChannel(tc.channel)
+		if err != nil {
+			t.Fatalf("failed to parse channel %s: %s", tc.channel, err)
+		}
+		resolved, err := channel.Resolve(tags)
+		if tc.fails {
+			if err == nil {
+				t.Errorf("expected error for channel %s but got %s", tc.channel, resolved)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error for channel %s: %s", tc.channel, err)
+		}
+		if resolved != tc.expected {
+			t.Errorf("expected %s for channel %s but got %s", tc.expected, tc.channel, resolved)
+		}
+	}
+}
+
+func TestChannelResolveStableSingleMinor(t *testing.T) {
+	channel, err := ParseChannel("stable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolved, err := channel.Resolve([]string{"1.0.0", "1.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolved != "1.0.1" {
+		t.Errorf("expected 1.0.1 but got %s", resolved)
+	}
+}
+
+func TestParseChannelInvalid(t *testing.T) {
+	_, err := ParseChannel("nightly")
+	if err == nil {
+		t.Errorf("expected error for invalid channel")
+	}
+}
+
+func TestChannelShouldUpgrade(t *testing.T) {
+	tt := []struct {
+		channel  string
+		current  string
+		resolved string
+		expected bool
+	}{
+		{channel: "latest", current: "0.25.0", resolved: "0.25.1", expected: true},
+		{channel: "latest", current: "0.25.1", resolved: "0.25.1", expected: false},
+		{channel: "stable", current: "0.25.1", resolved: "0.24.3", expected: false},
+		{channel: "0.24.2", current: "0.25.1", resolved: "0.24.2", expected: true},
+		{channel: "~> 0.24.0", current: "0.25.1", resolved: "0.24.3", expected: true},
+		{channel: "~> 0.24.0", current: "0.24.1", resolved: "0.24.3", expected: true},
+		{channel: "latest", current: "main", resolved: "0.25.1", expected: true},
+	}
+
+	for _, tc := range tt {
+		channel, err := ParseChannel(tc.channel)
+		if err != nil {
+			t.Fatalf("failed to parse channel %s: %s", tc.channel, err)
+		}
+		result := channel.ShouldUpgrade(tc.current, tc.resolved)
+		if result != tc.expected {
+			t.Errorf("expected %t for channel %s from %s to %s", tc.expected, tc.channel, tc.current, tc.resolved)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"context"
 	"fmt"
 	"io"
 
@@ -30,16 +31,16 @@ func NewContainerdCache(namespace string) (*ContainerdCache, error) {
 	}, nil
 }
 
-func (c *ContainerdCache) Put(key string, p tui.ProgressReader, reader io.ReadCloser) error {
-	return containers.PullContainerdImage(c.client, key)
+func (c *ContainerdCache) Put(ctx context.Context, key string, p tui.ProgressReader, reader io.ReadCloser) error {
+	return containers.PullContainerdImage(ctx, c.client, key)
 }
 
-func (c *ContainerdCache) Has(key string) bool {
-	return containers.CheckContainerdImageExists(c.client, key)
+func (c *ContainerdCache) Has(ctx context.Context, key string) bool {
+	return containers.CheckContainerdImageExists(ctx, c.client, key)
 }
 
-func (c *ContainerdCache) Get(key string, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
-	return containers.ReadContainerdImage(c.client, key, p, func(reader io.ReadCloser) error {
+func (c *ContainerdCache) Get(ctx context.Context, key string, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
+	return containers.ReadContainerdImage(ctx, c.client, key, p, func(reader io.ReadCloser) error {
 		err := callback(reader)
 		if err != nil {
 			return fmt.Errorf("failed to execute callback: %w", err)
@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"context"
 	"fmt"
 	"io"
 
@@ -30,8 +31,8 @@ func NewDockerCache() (*DockerCache, error) {
 	}, nil
 }
 
-func (c *DockerCache) Put(key string, p tui.ProgressReader, reader io.ReadCloser) error {
-	err := containers.PullDockerImage(c.cli, key)
+func (c *DockerCache) Put(ctx context.Context, key string, p tui.ProgressReader, reader io.ReadCloser) error {
+	err := containers.PullDockerImage(ctx, c.cli, key)
 	if err != nil {
 		return fmt.Errorf("failed to pull docker image: %w", err)
 	}
@@ -39,12 +40,12 @@ func (c *DockerCache) Put(key string, p tui.ProgressReader, reader io.ReadCloser
 	return nil
 }
 
-func (c *DockerCache) Has(key string) bool {
-	return containers.CheckDockerImageExists(c.cli, key)
+func (c *DockerCache) Has(ctx context.Context, key string) bool {
+	return containers.CheckDockerImageExists(ctx, c.cli, key)
 }
 
-func (c *DockerCache) Get(key string, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
-	return containers.ReadDockerImage(c.cli, key, p, func(reader io.ReadCloser) error {
+func (c *DockerCache) Get(ctx context.Context, key string, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
+	return containers.ReadDockerImage(ctx, c.cli, key, p, func(reader io.ReadCloser) error {
 		err := callback(reader)
 		if err != nil {
 			return fmt.Errorf("failed to execute callback: %w", err)
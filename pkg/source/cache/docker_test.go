@@ -4,6 +4,7 @@ package cache
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"testing"
 
@@ -64,11 +65,11 @@ func TestDockerCache_Put(t *testing.T) {
 
 	// DockerCache.Put ignores the reader; the key is the image reference and
 	// Put pulls that image into the local daemon.
-	err = c.Put(testDockerImage, pr, io.NopCloser(bytes.NewReader(nil)))
+	err = c.Put(context.Background(), testDockerImage, pr, io.NopCloser(bytes.NewReader(nil)))
 	if err != nil {
 		t.Fatalf("Put() unexpected error: %v", err)
 	}
-	if !c.Has(testDockerImage) {
+	if !c.Has(context.Background(), testDockerImage) {
 		t.Errorf("Has(%q) = false after Put, want true", testDockerImage)
 	}
 }
@@ -81,10 +82,10 @@ func TestDockerCache_Has(t *testing.T) {
 	pr := tui.NewQuietProgressReader()
 
 	// Ensure the image is present before asserting on Has.
-	if err := c.Put(testDockerImage, pr, io.NopCloser(bytes.NewReader(nil))); err != nil {
+	if err := c.Put(context.Background(), testDockerImage, pr, io.NopCloser(bytes.NewReader(nil))); err != nil {
 		t.Fatalf("Put() unexpected error: %v", err)
 	}
-	if !c.Has(testDockerImage) {
+	if !c.Has(context.Background(), testDockerImage) {
 		t.Errorf("Has(%q) = false for pulled image, want true", testDockerImage)
 	}
 }
@@ -96,7 +97,7 @@ func TestDockerCache_Has_Missing(t *testing.T) {
 	}
 
 	const missing = "uniget-cache-test/does-not-exist:definitely-not-a-tag"
-	if c.Has(missing) {
+	if c.Has(context.Background(), missing) {
 		t.Errorf("Has(%q) = true for nonexistent image, want false", missing)
 	}
 }
@@ -108,13 +109,13 @@ func TestDockerCache_Get(t *testing.T) {
 	}
 	pr := tui.NewQuietProgressReader()
 
-	if err := c.Put(testDockerImage, pr, io.NopCloser(bytes.NewReader(nil))); err != nil {
+	if err := c.Put(context.Background(), testDockerImage, pr, io.NopCloser(bytes.NewReader(nil))); err != nil {
 		t.Fatalf("Put() unexpected error: %v", err)
 	}
 
 	called := false
 	var readBytes int64
-	err = c.Get(testDockerImage, pr, func(reader io.ReadCloser) error {
+	err = c.Get(context.Background(), testDockerImage, pr, func(reader io.ReadCloser) error {
 		called = true
 		n, err := io.Copy(io.Discard, reader)
 		if err != nil {
@@ -143,7 +144,7 @@ func TestDockerCache_Get_Missing(t *testing.T) {
 
 	const missing = "uniget-cache-test/does-not-exist:definitely-not-a-tag"
 	called := false
-	err = c.Get(missing, pr, func(reader io.ReadCloser) error {
+	err = c.Get(context.Background(), missing, pr, func(reader io.ReadCloser) error {
 		called = true
 		return nil
 	})
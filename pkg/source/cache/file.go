@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -34,7 +35,7 @@ func NewFileCache(directory string, retentionSeconds int) (*FileCache, error) {
 	return cache, nil
 }
 
-func (c *FileCache) Put(key string, p tui.ProgressReader, reader io.ReadCloser) error {
+func (c *FileCache) Put(ctx context.Context, key string, p tui.ProgressReader, reader io.ReadCloser) error {
 	//nolint:errcheck
 	defer reader.Close()
 
@@ -49,13 +50,18 @@ func (c *FileCache) Put(key string, p tui.ProgressReader, reader io.ReadCloser)
 
 	_, err = io.Copy(file, reader)
 	if err != nil {
+		// Do not leave a truncated entry behind which would be used later
+		removeErr := os.Remove(fmt.Sprintf("%s/%s", c.cacheDirectory, key))
+		if removeErr != nil {
+			logging.Warning.Printfln("Failed to remove partial cache entry for key %s: %s", key, removeErr)
+		}
 		return fmt.Errorf("failed to write data for key %s to cache: %s", key, err)
 	}
 
 	return nil
 }
 
-func (c *FileCache) Has(key string) bool {
+func (c *FileCache) Has(ctx context.Context, key string) bool {
 	logging.Tracef("Checking cache for key %s", key)
 	stat, err := os.Stat(fmt.Sprintf("%s/%s", c.cacheDirectory, key))
 	if os.IsNotExist(err) {
@@ -77,7 +83,7 @@ func (c *FileCache) Has(key string) bool {
 	return true
 }
 
-func (c *FileCache) Get(key string, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
+func (c *FileCache) Get(ctx context.Context, key string, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
 	logging.Tracef("Reading data from cache for key %s", key)
 	fileReader, err := os.Open(fmt.Sprintf("%s/%s", c.cacheDirectory, key))
 	if err != nil {
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"os"
@@ -92,7 +93,7 @@ func TestFileCache_Put(t *testing.T) {
 			pr := tui.NewQuietProgressReader()
 			reader := io.NopCloser(bytes.NewReader(tt.payload))
 
-			if err := c.Put(tt.key, pr, reader); err != nil {
+			if err := c.Put(context.Background(), tt.key, pr, reader); err != nil {
 				t.Fatalf("Put() unexpected error: %v", err)
 			}
 
@@ -103,7 +104,7 @@ func TestFileCache_Put(t *testing.T) {
 			if !bytes.Equal(got, tt.payload) {
 				t.Errorf("file contents = %v, want %v", got, tt.payload)
 			}
-			if !c.Has(tt.key) {
+			if !c.Has(context.Background(), tt.key) {
 				t.Errorf("Has(%q) = false after Put, want true", tt.key)
 			}
 		})
@@ -120,7 +121,7 @@ func TestFileCache_Put_CreateFailure(t *testing.T) {
 
 	// A key containing a path separator points into a nonexistent subdirectory,
 	// which makes os.Create fail.
-	err = c.Put("missing/subdir/key", pr, io.NopCloser(bytes.NewReader([]byte("data"))))
+	err = c.Put(context.Background(), "missing/subdir/key", pr, io.NopCloser(bytes.NewReader([]byte("data"))))
 	if err == nil {
 		t.Fatal("Put() expected error for invalid path, got nil")
 	}
@@ -140,9 +141,12 @@ func TestFileCache_Put_ReaderError(t *testing.T) {
 	}
 	pr := tui.NewQuietProgressReader()
 
-	if err := c.Put("key", pr, fileErrorReader{}); err == nil {
+	if err := c.Put(context.Background(), "key", pr, fileErrorReader{}); err == nil {
 		t.Fatal("Put() expected error from failing reader, got nil")
 	}
+	if c.Has(context.Background(), "key") {
+		t.Error("Has() = true after failed Put(), want partial entry to be removed")
+	}
 }
 
 func TestFileCache_Put_Overwrite(t *testing.T) {
@@ -153,10 +157,10 @@ func TestFileCache_Put_Overwrite(t *testing.T) {
 	}
 	pr := tui.NewQuietProgressReader()
 
-	if err := c.Put("k", pr, io.NopCloser(bytes.NewReader([]byte("first")))); err != nil {
+	if err := c.Put(context.Background(), "k", pr, io.NopCloser(bytes.NewReader([]byte("first")))); err != nil {
 		t.Fatalf("Put() unexpected error: %v", err)
 	}
-	if err := c.Put("k", pr, io.NopCloser(bytes.NewReader([]byte("second")))); err != nil {
+	if err := c.Put(context.Background(), "k", pr, io.NopCloser(bytes.NewReader([]byte("second")))); err != nil {
 		t.Fatalf("Put() unexpected error: %v", err)
 	}
 
@@ -174,7 +178,7 @@ func TestFileCache_Has_Missing(t *testing.T) {
 	if err != nil {
 		t.Fatalf("NewFileCache() unexpected error: %v", err)
 	}
-	if c.Has("missing") {
+	if c.Has(context.Background(), "missing") {
 		t.Error("Has(\"missing\") = true, want false")
 	}
 }
@@ -187,7 +191,7 @@ func TestFileCache_Has_Expired(t *testing.T) {
 	}
 	pr := tui.NewQuietProgressReader()
 
-	if err := c.Put("k", pr, io.NopCloser(bytes.NewReader([]byte("data")))); err != nil {
+	if err := c.Put(context.Background(), "k", pr, io.NopCloser(bytes.NewReader([]byte("data")))); err != nil {
 		t.Fatalf("Put() unexpected error: %v", err)
 	}
 
@@ -197,7 +201,7 @@ func TestFileCache_Has_Expired(t *testing.T) {
 		t.Fatalf("Chtimes() unexpected error: %v", err)
 	}
 
-	if c.Has("k") {
+	if c.Has(context.Background(), "k") {
 		t.Error("Has(\"k\") = true for expired entry, want false")
 	}
 	if _, err := os.Stat(filepath.Join(dir, "k")); !os.IsNotExist(err) {
@@ -214,13 +218,13 @@ func TestFileCache_Get_Existing(t *testing.T) {
 	pr := tui.NewQuietProgressReader()
 	payload := []byte("cached value")
 
-	if err := c.Put("k", pr, io.NopCloser(bytes.NewReader(payload))); err != nil {
+	if err := c.Put(context.Background(), "k", pr, io.NopCloser(bytes.NewReader(payload))); err != nil {
 		t.Fatalf("Put() unexpected error: %v", err)
 	}
 
 	called := false
 	var got []byte
-	err = c.Get("k", pr, func(reader io.ReadCloser) error {
+	err = c.Get(context.Background(), "k", pr, func(reader io.ReadCloser) error {
 		called = true
 		data, err := io.ReadAll(reader)
 		if err != nil {
@@ -248,7 +252,7 @@ func TestFileCache_Get_Missing(t *testing.T) {
 	pr := tui.NewQuietProgressReader()
 
 	called := false
-	err = c.Get("missing", pr, func(reader io.ReadCloser) error {
+	err = c.Get(context.Background(), "missing", pr, func(reader io.ReadCloser) error {
 		called = true
 		return nil
 	})
@@ -269,11 +273,11 @@ func TestFileCache_Get_CallbackError(t *testing.T) {
 	pr := tui.NewQuietProgressReader()
 	wantErr := errors.New("callback error")
 
-	if err := c.Put("k", pr, io.NopCloser(bytes.NewReader([]byte("data")))); err != nil {
+	if err := c.Put(context.Background(), "k", pr, io.NopCloser(bytes.NewReader([]byte("data")))); err != nil {
 		t.Fatalf("Put() unexpected error: %v", err)
 	}
 
-	err = c.Get("k", pr, func(reader io.ReadCloser) error {
+	err = c.Get(context.Background(), "k", pr, func(reader io.ReadCloser) error {
 		return wantErr
 	})
 	if !errors.Is(err, wantErr) {
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 
@@ -22,7 +23,7 @@ func NewMemoryCache() *MemoryCache {
 	}
 }
 
-func (c *MemoryCache) Put(key string, p tui.ProgressReader, reader io.ReadCloser) error {
+func (c *MemoryCache) Put(ctx context.Context, key string, p tui.ProgressReader, reader io.ReadCloser) error {
 	data, err := io.ReadAll(reader)
 	if err != nil {
 		return fmt.Errorf("failed to read data for key %s: %w", key, err)
@@ -31,12 +32,12 @@ func (c *MemoryCache) Put(key string, p tui.ProgressReader, reader io.ReadCloser
 	return nil
 }
 
-func (c *MemoryCache) Has(key string) bool {
+func (c *MemoryCache) Has(ctx context.Context, key string) bool {
 	_, ok := c.data[key]
 	return ok
 }
 
-func (c *MemoryCache) Get(key string, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
+func (c *MemoryCache) Get(ctx context.Context, key string, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
 	data, ok := c.data[key]
 	if !ok {
 		return nil
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"testing"
@@ -49,10 +50,10 @@ func TestMemoryCache_Put(t *testing.T) {
 			pr := tui.NewQuietProgressReader()
 			reader := io.NopCloser(bytes.NewReader(tt.payload))
 
-			if err := c.Put(tt.key, pr, reader); err != nil {
+			if err := c.Put(context.Background(), tt.key, pr, reader); err != nil {
 				t.Fatalf("Put() unexpected error: %v", err)
 			}
-			if !c.Has(tt.key) {
+			if !c.Has(context.Background(), tt.key) {
 				t.Errorf("Has(%q) = false after Put, want true", tt.key)
 			}
 			if got, want := c.data[tt.key], tt.payload; !bytes.Equal(got, want) {
@@ -72,11 +73,11 @@ func TestMemoryCache_Put_ReaderError(t *testing.T) {
 	c := NewMemoryCache()
 	pr := tui.NewQuietProgressReader()
 
-	err := c.Put("key", pr, errorReader{})
+	err := c.Put(context.Background(), "key", pr, errorReader{})
 	if err == nil {
 		t.Fatal("Put() expected error from failing reader, got nil")
 	}
-	if c.Has("key") {
+	if c.Has(context.Background(), "key") {
 		t.Error("Has(\"key\") = true after failed Put, want false")
 	}
 }
@@ -85,10 +86,10 @@ func TestMemoryCache_Put_Overwrite(t *testing.T) {
 	c := NewMemoryCache()
 	pr := tui.NewQuietProgressReader()
 
-	if err := c.Put("k", pr, io.NopCloser(bytes.NewReader([]byte("first")))); err != nil {
+	if err := c.Put(context.Background(), "k", pr, io.NopCloser(bytes.NewReader([]byte("first")))); err != nil {
 		t.Fatalf("Put() unexpected error: %v", err)
 	}
-	if err := c.Put("k", pr, io.NopCloser(bytes.NewReader([]byte("second")))); err != nil {
+	if err := c.Put(context.Background(), "k", pr, io.NopCloser(bytes.NewReader([]byte("second")))); err != nil {
 		t.Fatalf("Put() unexpected error: %v", err)
 	}
 
@@ -100,15 +101,15 @@ func TestMemoryCache_Put_Overwrite(t *testing.T) {
 func TestMemoryCache_Has(t *testing.T) {
 	c := NewMemoryCache()
 
-	if c.Has("missing") {
+	if c.Has(context.Background(), "missing") {
 		t.Error("Has(\"missing\") = true, want false")
 	}
 
 	pr := tui.NewQuietProgressReader()
-	if err := c.Put("stored", pr, io.NopCloser(bytes.NewReader([]byte("data")))); err != nil {
+	if err := c.Put(context.Background(), "stored", pr, io.NopCloser(bytes.NewReader([]byte("data")))); err != nil {
 		t.Fatalf("Put() unexpected error: %v", err)
 	}
-	if !c.Has("stored") {
+	if !c.Has(context.Background(), "stored") {
 		t.Error("Has(\"stored\") = false after Put, want true")
 	}
 }
@@ -118,7 +119,7 @@ func TestMemoryCache_Get_Missing(t *testing.T) {
 	pr := tui.NewQuietProgressReader()
 
 	called := false
-	err := c.Get("missing", pr, func(reader io.ReadCloser) error {
+	err := c.Get(context.Background(), "missing", pr, func(reader io.ReadCloser) error {
 		called = true
 		return nil
 	})
@@ -135,13 +136,13 @@ func TestMemoryCache_Get_Existing(t *testing.T) {
 	pr := tui.NewQuietProgressReader()
 	payload := []byte("cached value")
 
-	if err := c.Put("k", pr, io.NopCloser(bytes.NewReader(payload))); err != nil {
+	if err := c.Put(context.Background(), "k", pr, io.NopCloser(bytes.NewReader(payload))); err != nil {
 		t.Fatalf("Put() unexpected error: %v", err)
 	}
 
 	called := false
 	var got []byte
-	err := c.Get("k", pr, func(reader io.ReadCloser) error {
+	err := c.Get(context.Background(), "k", pr, func(reader io.ReadCloser) error {
 		called = true
 		data, err := io.ReadAll(reader)
 		if err != nil {
@@ -166,11 +167,11 @@ func TestMemoryCache_Get_CallbackError(t *testing.T) {
 	pr := tui.NewQuietProgressReader()
 	wantErr := errors.New("callback error")
 
-	if err := c.Put("k", pr, io.NopCloser(bytes.NewReader([]byte("data")))); err != nil {
+	if err := c.Put(context.Background(), "k", pr, io.NopCloser(bytes.NewReader([]byte("data")))); err != nil {
 		t.Fatalf("Put() unexpected error: %v", err)
 	}
 
-	err := c.Get("k", pr, func(reader io.ReadCloser) error {
+	err := c.Get(context.Background(), "k", pr, func(reader io.ReadCloser) error {
 		return wantErr
 	})
 	if !errors.Is(err, wantErr) {
@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"context"
 	"io"
 
 	"gitlab.com/uniget-org/cli/pkg/tui"
@@ -18,14 +19,14 @@ func NewNoneCache() *NoneCache {
 	}
 }
 
-func (c *NoneCache) Put(key string, p tui.ProgressReader, reader io.ReadCloser) error {
+func (c *NoneCache) Put(ctx context.Context, key string, p tui.ProgressReader, reader io.ReadCloser) error {
 	return nil
 }
 
-func (c *NoneCache) Has(key string) bool {
+func (c *NoneCache) Has(ctx context.Context, key string) bool {
 	return false
 }
 
-func (c *NoneCache) Get(key string, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
+func (c *NoneCache) Get(ctx context.Context, key string, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
 	return nil
 }
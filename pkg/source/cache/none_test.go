@@ -2,6 +2,7 @@ package cache
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"testing"
 
@@ -41,10 +42,10 @@ func TestNoneCache_Put(t *testing.T) {
 			pr := tui.NewQuietProgressReader()
 			reader := io.NopCloser(bytes.NewReader(tt.payload))
 
-			if err := c.Put(tt.key, pr, reader); err != nil {
+			if err := c.Put(context.Background(), tt.key, pr, reader); err != nil {
 				t.Errorf("Put() unexpected error: %v", err)
 			}
-			if c.Has(tt.key) {
+			if c.Has(context.Background(), tt.key) {
 				t.Errorf("Has(%q) = true after Put, want false", tt.key)
 			}
 		})
@@ -54,15 +55,15 @@ func TestNoneCache_Put(t *testing.T) {
 func TestNoneCache_Has(t *testing.T) {
 	c := NewNoneCache()
 
-	if c.Has("missing") {
+	if c.Has(context.Background(), "missing") {
 		t.Error("Has(\"missing\") = true, want false")
 	}
 
 	pr := tui.NewQuietProgressReader()
-	if err := c.Put("stored", pr, io.NopCloser(bytes.NewReader([]byte("data")))); err != nil {
+	if err := c.Put(context.Background(), "stored", pr, io.NopCloser(bytes.NewReader([]byte("data")))); err != nil {
 		t.Fatalf("Put() unexpected error: %v", err)
 	}
-	if c.Has("stored") {
+	if c.Has(context.Background(), "stored") {
 		t.Error("Has(\"stored\") = true after Put, want false (NoneCache never stores)")
 	}
 }
@@ -77,7 +78,7 @@ func TestNoneCache_Get(t *testing.T) {
 		return nil
 	}
 
-	if err := c.Get("missing", pr, callback); err != nil {
+	if err := c.Get(context.Background(), "missing", pr, callback); err != nil {
 		t.Errorf("Get() unexpected error: %v", err)
 	}
 	if called {
@@ -89,12 +90,12 @@ func TestNoneCache_GetAfterPut(t *testing.T) {
 	c := NewNoneCache()
 	pr := tui.NewQuietProgressReader()
 
-	if err := c.Put("k", pr, io.NopCloser(bytes.NewReader([]byte("value")))); err != nil {
+	if err := c.Put(context.Background(), "k", pr, io.NopCloser(bytes.NewReader([]byte("value")))); err != nil {
 		t.Fatalf("Put() unexpected error: %v", err)
 	}
 
 	called := false
-	err := c.Get("k", pr, func(reader io.ReadCloser) error {
+	err := c.Get(context.Background(), "k", pr, func(reader io.ReadCloser) error {
 		called = true
 		return nil
 	})
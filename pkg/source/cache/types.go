@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"context"
 	"io"
 
 	"gitlab.com/uniget-org/cli/pkg/tui"
@@ -30,9 +31,9 @@ type CacheStruct struct {
 
 type Cache interface {
 	GetName() string
-	Put(key string, p tui.ProgressReader, reader io.ReadCloser) error
-	Has(key string) bool
-	Get(key string, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error
+	Put(ctx context.Context, key string, p tui.ProgressReader, reader io.ReadCloser) error
+	Has(ctx context.Context, key string) bool
+	Get(ctx context.Context, key string, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error
 }
 
 func (c *CacheStruct) GetName() string {
@@ -1,6 +1,7 @@
 package source
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -33,7 +34,7 @@ func IsFileRef(source *Source) bool {
 	return strings.HasPrefix(source.Url, "file://")
 }
 
-func (d *FileBackend) Get(source *Source, p tui.ProgressReader, callback func(reader io.ReadCloser) error) (err error) {
+func (d *FileBackend) Get(ctx context.Context, source *Source, p tui.ProgressReader, callback func(reader io.ReadCloser) error) (err error) {
 	f, err := os.Open(strings.TrimPrefix(source.Url, "file://"))
 	if err != nil {
 		return fmt.Errorf("failed to open file %s: %s", source.Url, err)
@@ -2,6 +2,7 @@ package source
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"os"
@@ -72,7 +73,7 @@ func TestFileBackend_Get(t *testing.T) {
 		}
 
 		var got []byte
-		err = d.Get(&Source{Url: "file://" + path}, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error {
+		err = d.Get(context.Background(), &Source{Url: "file://" + path}, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error {
 			b, err := io.ReadAll(reader)
 			if err != nil {
 				return err
@@ -98,7 +99,7 @@ func TestFileBackend_Get(t *testing.T) {
 		}
 
 		called := false
-		err = d.Get(&Source{Url: "file://" + missing}, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error {
+		err = d.Get(context.Background(), &Source{Url: "file://" + missing}, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error {
 			called = true
 			return nil
 		})
@@ -123,7 +124,7 @@ func TestFileBackend_Get(t *testing.T) {
 		}
 
 		want := errors.New("callback failed")
-		err = d.Get(&Source{Url: "file://" + path}, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error {
+		err = d.Get(context.Background(), &Source{Url: "file://" + path}, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error {
 			return want
 		})
 		if !errors.Is(err, want) {
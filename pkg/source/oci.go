@@ -32,24 +32,22 @@ func IsOciRef(source *Source) bool {
 	return strings.HasPrefix(source.Url, "oci://")
 }
 
-func (d *OciBackend) Get(source *Source, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
+func (d *OciBackend) Get(ctx context.Context, source *Source, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
 	switch d.CacheType {
 	case cache.CacheNone:
-		return d.GetFromRegistry(source, p, callback)
+		return d.GetFromRegistry(ctx, source, p, callback)
 	case cache.CacheFile:
-		return d.GetFileCache(source, p, callback)
+		return d.GetFileCache(ctx, source, p, callback)
 	case cache.CacheDocker:
-		return d.HandleCache(source, p, callback)
+		return d.HandleCache(ctx, source, p, callback)
 	case cache.CacheContainerd:
-		return d.HandleCache(source, p, callback)
+		return d.HandleCache(ctx, source, p, callback)
 	default:
 		return fmt.Errorf("unsupported cache type: %v", d.CacheType)
 	}
 }
 
-func (d *OciBackend) GetFromRegistry(source *Source, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
-	ctx := context.Background()
-
+func (d *OciBackend) GetFromRegistry(ctx context.Context, source *Source, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
 	ref := strings.TrimPrefix(source.Url, "oci://")
 	r, err := rref.New(ref)
 	if err != nil {
@@ -75,15 +73,15 @@ func (d *OciBackend) GetFromRegistry(source *Source, p tui.ProgressReader, callb
 	return nil
 }
 
-func (d *OciBackend) GetFileCache(source *Source, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
-	if !d.Cache.Has(source.Url) {
-		err := d.GetFromRegistry(source, p, func(reader io.ReadCloser) error {
-			return d.Cache.Put(source.Url, p, reader)
+func (d *OciBackend) GetFileCache(ctx context.Context, source *Source, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
+	if !d.Cache.Has(ctx, source.Url) {
+		err := d.GetFromRegistry(ctx, source, p, func(reader io.ReadCloser) error {
+			return d.Cache.Put(ctx, source.Url, p, reader)
 		})
 		if err != nil {
 			return fmt.Errorf("failed to get from registry and put in cache: %w", err)
 		}
 	}
 
-	return d.Cache.Get(source.Url, p, callback)
+	return d.Cache.Get(ctx, source.Url, p, callback)
 }
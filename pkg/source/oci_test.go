@@ -1,6 +1,7 @@
 package source
 
 import (
+	"context"
 	"errors"
 	"io"
 	"testing"
@@ -85,7 +86,7 @@ func TestOciBackend_Get(t *testing.T) {
 		}
 		d.CacheType = cache.CacheType(999)
 
-		err = d.Get(src, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error { return nil })
+		err = d.Get(context.Background(), src, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error { return nil })
 		if err == nil {
 			t.Fatal("Get() expected error for unsupported cache type, got nil")
 		}
@@ -100,7 +101,7 @@ func TestOciBackend_Get(t *testing.T) {
 		fc := &fakeCache{name: "fake", has: true}
 		d.Cache = fc
 
-		err = d.Get(src, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error { return nil })
+		err = d.Get(context.Background(), src, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error { return nil })
 		if err != nil {
 			t.Fatalf("Get() unexpected error: %v", err)
 		}
@@ -121,7 +122,7 @@ func TestOciBackend_Get(t *testing.T) {
 		fc := &fakeCache{name: "fake", has: true}
 		d.Cache = fc
 
-		err = d.Get(src, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error { return nil })
+		err = d.Get(context.Background(), src, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error { return nil })
 		if err != nil {
 			t.Fatalf("Get() unexpected error: %v", err)
 		}
@@ -144,7 +145,7 @@ func TestOciBackend_GetFileCache_Hit(t *testing.T) {
 	d.Cache = fc
 
 	src := &Source{Url: "oci://example.com/foo:tag"}
-	err = d.GetFileCache(src, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error { return nil })
+	err = d.GetFileCache(context.Background(), src, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error { return nil })
 	if err != nil {
 		t.Fatalf("GetFileCache() unexpected error: %v", err)
 	}
@@ -168,7 +169,7 @@ func TestOciBackend_GetFileCache_HitPropagatesGetError(t *testing.T) {
 	fc := &fakeCache{name: "fake", has: true, getErr: want}
 	d.Cache = fc
 
-	err = d.GetFileCache(&Source{Url: "oci://example.com/foo:tag"}, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error { return nil })
+	err = d.GetFileCache(context.Background(), &Source{Url: "oci://example.com/foo:tag"}, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error { return nil })
 	if !errors.Is(err, want) {
 		t.Errorf("GetFileCache() error = %v, want %v", err, want)
 	}
@@ -181,7 +182,7 @@ func TestOciBackend_GetFromRegistry_InvalidRef(t *testing.T) {
 	}
 
 	// An uppercase letter in the repository segment is rejected by regclient.
-	err = d.GetFromRegistry(&Source{Url: "oci://example.com/BAD:tag"}, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error { return nil })
+	err = d.GetFromRegistry(context.Background(), &Source{Url: "oci://example.com/BAD:tag"}, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error { return nil })
 	if err == nil {
 		t.Fatal("GetFromRegistry() expected error for invalid ref, got nil")
 	}
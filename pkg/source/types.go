@@ -1,6 +1,7 @@
 package source
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"strconv"
@@ -73,15 +74,15 @@ func NewBackendFromScheme(url *Source, cacheType cache.CacheType, cacheConfigura
 	return nil, fmt.Errorf("unsupported scheme for url: %s", url.Url)
 }
 
-func (b *Backend) HandleCache(source *Source, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
-	if !b.Cache.Has(source.Url) {
-		err := b.Cache.Put(source.Url, p, nil)
+func (b *Backend) HandleCache(ctx context.Context, source *Source, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
+	if !b.Cache.Has(ctx, source.Url) {
+		err := b.Cache.Put(ctx, source.Url, p, nil)
 		if err != nil {
 			return fmt.Errorf("failed to put %s into cache of type %s: %w", source.Url, b.Cache.GetName(), err)
 		}
 	}
 
-	err := b.Cache.Get(source.Url, p, callback)
+	err := b.Cache.Get(ctx, source.Url, p, callback)
 	if err != nil {
 		return fmt.Errorf("failed to get %s from cache of type %s: %w", source.Url, b.Cache.GetName(), err)
 	}
@@ -91,8 +92,11 @@ func (b *Backend) HandleCache(source *Source, p tui.ProgressReader, callback fun
 
 type Source struct {
 	Url string
+	// Digest is verified after downloading from the web if set,
+	// e.g. sha256:<hex>
+	Digest string
 }
 
 type Downloader interface {
-	Get(source *Source, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error
+	Get(ctx context.Context, source *Source, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error
 }
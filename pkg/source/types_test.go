@@ -1,6 +1,7 @@
 package source
 
 import (
+	"context"
 	"errors"
 	"io"
 	"os"
@@ -25,19 +26,19 @@ type fakeCache struct {
 
 func (c *fakeCache) GetName() string { return c.name }
 
-func (c *fakeCache) Put(key string, p tui.ProgressReader, reader io.ReadCloser) error {
+func (c *fakeCache) Put(ctx context.Context, key string, p tui.ProgressReader, reader io.ReadCloser) error {
 	c.putCall++
 	c.lastKey = key
 	return c.putErr
 }
 
-func (c *fakeCache) Has(key string) bool {
+func (c *fakeCache) Has(ctx context.Context, key string) bool {
 	c.hasCall++
 	c.lastKey = key
 	return c.has
 }
 
-func (c *fakeCache) Get(key string, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
+func (c *fakeCache) Get(ctx context.Context, key string, p tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
 	c.getCall++
 	c.lastKey = key
 	return c.getErr
@@ -168,7 +169,7 @@ func TestBackend_HandleCache(t *testing.T) {
 		fc := &fakeCache{name: "fake", has: false}
 		b := &Backend{Cache: fc}
 
-		err := b.HandleCache(src, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error { return nil })
+		err := b.HandleCache(context.Background(), src, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error { return nil })
 		if err != nil {
 			t.Fatalf("HandleCache() unexpected error: %v", err)
 		}
@@ -187,7 +188,7 @@ func TestBackend_HandleCache(t *testing.T) {
 		fc := &fakeCache{name: "fake", has: true}
 		b := &Backend{Cache: fc}
 
-		err := b.HandleCache(src, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error { return nil })
+		err := b.HandleCache(context.Background(), src, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error { return nil })
 		if err != nil {
 			t.Fatalf("HandleCache() unexpected error: %v", err)
 		}
@@ -203,7 +204,7 @@ func TestBackend_HandleCache(t *testing.T) {
 		fc := &fakeCache{name: "fake", has: false, putErr: errors.New("boom")}
 		b := &Backend{Cache: fc}
 
-		err := b.HandleCache(src, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error { return nil })
+		err := b.HandleCache(context.Background(), src, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error { return nil })
 		if err == nil {
 			t.Fatal("HandleCache() expected error from Put, got nil")
 		}
@@ -216,7 +217,7 @@ func TestBackend_HandleCache(t *testing.T) {
 		fc := &fakeCache{name: "fake", has: true, getErr: errors.New("boom")}
 		b := &Backend{Cache: fc}
 
-		err := b.HandleCache(src, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error { return nil })
+		err := b.HandleCache(context.Background(), src, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error { return nil })
 		if err == nil {
 			t.Fatal("HandleCache() expected error from Get, got nil")
 		}
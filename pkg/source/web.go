@@ -1,12 +1,15 @@
 package source
 
 import (
+	"context"
 	"fmt"
 	"io"
-	"net/http"
 	"strings"
 
+	"github.com/opencontainers/go-digest"
+
 	"gitlab.com/uniget-org/cli/pkg/source/cache"
+	"gitlab.com/uniget-org/cli/pkg/transport"
 	"gitlab.com/uniget-org/cli/pkg/tui"
 )
 
@@ -33,33 +36,33 @@ func IsWebRef(source *Source) bool {
 	return strings.HasPrefix(source.Url, "http://") || strings.HasPrefix(source.Url, "https://")
 }
 
-func (d *WebBackend) Get(source *Source, p tui.ProgressReader, callback func(reader io.ReadCloser) error) (err error) {
+func (d *WebBackend) Get(ctx context.Context, source *Source, p tui.ProgressReader, callback func(reader io.ReadCloser) error) (err error) {
 	switch d.CacheType {
 	case cache.CacheNone:
-		resp, err := http.Get(source.Url)
+		err := download(ctx, source, &p, callback)
 		if err != nil {
 			return fmt.Errorf("failed to get %s: %w", source.Url, err)
 		}
-		//nolint:errcheck
-		defer resp.Body.Close()
-
-		return callback(resp.Body)
+		return nil
 
 	case cache.CacheFile:
-		if !d.Cache.Has(source.Url) {
-			resp, err := http.Get(source.Url)
+		if !d.Cache.Has(ctx, source.Url) {
+			err := download(ctx, source, &p, func(reader io.ReadCloser) error {
+				err := d.Cache.Put(ctx, source.Url, p, reader)
+				if err != nil {
+					return fmt.Errorf("failed to put %s into cache: %w", source.Url, err)
+				}
+				return nil
+			})
 			if err != nil {
 				return fmt.Errorf("failed to get %s: %w", source.Url, err)
 			}
-			//nolint:errcheck
-			defer resp.Body.Close()
-
-			err = d.Cache.Put(source.Url, p, resp.Body)
-			if err != nil {
-				return fmt.Errorf("failed to put %s into cache: %w", source.Url, err)
-			}
 		}
 	}
 
-	return d.Cache.Get(source.Url, p, callback)
+	return d.Cache.Get(ctx, source.Url, p, callback)
+}
+
+func download(ctx context.Context, source *Source, p *tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
+	return transport.Download(ctx, transport.OpenURL(source.Url), digest.Digest(source.Digest), 0, p, callback)
 }
@@ -2,6 +2,7 @@ package source
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"net/http"
@@ -9,7 +10,10 @@ import (
 	"sync/atomic"
 	"testing"
 
+	"github.com/opencontainers/go-digest"
+
 	"gitlab.com/uniget-org/cli/pkg/source/cache"
+	"gitlab.com/uniget-org/cli/pkg/transport"
 	"gitlab.com/uniget-org/cli/pkg/tui"
 )
 
@@ -91,7 +95,7 @@ func TestWebBackend_Get(t *testing.T) {
 		fc := &fakeCache{name: "fake", has: false}
 		d.Cache = fc
 
-		err = d.Get(&Source{Url: srv.URL}, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error { return nil })
+		err = d.Get(context.Background(), &Source{Url: srv.URL}, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error { return nil })
 		if err != nil {
 			t.Fatalf("Get() unexpected error: %v", err)
 		}
@@ -125,7 +129,7 @@ func TestWebBackend_Get(t *testing.T) {
 		fc := &fakeCache{name: "fake", has: true}
 		d.Cache = fc
 
-		err = d.Get(&Source{Url: srv.URL}, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error { return nil })
+		err = d.Get(context.Background(), &Source{Url: srv.URL}, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error { return nil })
 		if err != nil {
 			t.Fatalf("Get() unexpected error: %v", err)
 		}
@@ -146,6 +150,15 @@ func TestWebBackend_Get(t *testing.T) {
 		badURL := srv.URL
 		srv.Close()
 
+		// Do not wait for retries of the refused connection
+		opts := transport.Current()
+		if err := transport.Configure(transport.Options{}); err != nil {
+			t.Fatalf("Configure() unexpected error: %v", err)
+		}
+		defer func() {
+			_ = transport.Configure(opts)
+		}()
+
 		d, err := NewWebDownloader(cache.CacheNone, nil)
 		if err != nil {
 			t.Fatalf("NewWebDownloader() unexpected error: %v", err)
@@ -153,7 +166,7 @@ func TestWebBackend_Get(t *testing.T) {
 		fc := &fakeCache{name: "fake", has: false}
 		d.Cache = fc
 
-		err = d.Get(&Source{Url: badURL}, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error { return nil })
+		err = d.Get(context.Background(), &Source{Url: badURL}, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error { return nil })
 		if err == nil {
 			t.Fatal("Get() expected error for unreachable server, got nil")
 		}
@@ -162,6 +175,25 @@ func TestWebBackend_Get(t *testing.T) {
 		}
 	})
 
+	t.Run("cancelled context", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("payload"))
+		}))
+		defer srv.Close()
+
+		d, err := NewWebDownloader(cache.CacheNone, nil)
+		if err != nil {
+			t.Fatalf("NewWebDownloader() unexpected error: %v", err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err = d.Get(ctx, &Source{Url: srv.URL}, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error { return nil })
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Get() error = %v, want %v", err, context.Canceled)
+		}
+	})
+
 	t.Run("propagates cache get error", func(t *testing.T) {
 		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			_, _ = w.Write([]byte("payload"))
@@ -177,12 +209,42 @@ func TestWebBackend_Get(t *testing.T) {
 		fc := &fakeCache{name: "fake", has: true, getErr: want}
 		d.Cache = fc
 
-		err = d.Get(&Source{Url: srv.URL}, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error { return nil })
+		err = d.Get(context.Background(), &Source{Url: srv.URL}, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error { return nil })
 		if !errors.Is(err, want) {
 			t.Errorf("Get() error = %v, want %v", err, want)
 		}
 	})
 
+	t.Run("digest is verified", func(t *testing.T) {
+		payload := []byte("payload")
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write(payload)
+		}))
+		defer srv.Close()
+
+		d, err := NewWebDownloader(cache.CacheNone, nil)
+		if err != nil {
+			t.Fatalf("NewWebDownloader() unexpected error: %v", err)
+		}
+
+		err = d.Get(context.Background(), &Source{Url: srv.URL, Digest: digest.FromBytes(payload).String()}, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error { return nil })
+		if err != nil {
+			t.Errorf("Get() unexpected error for matching digest: %v", err)
+		}
+
+		called := false
+		err = d.Get(context.Background(), &Source{Url: srv.URL, Digest: digest.FromString("other").String()}, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error {
+			called = true
+			return nil
+		})
+		if err == nil {
+			t.Errorf("Get() expected error for digest mismatch, got nil")
+		}
+		if called {
+			t.Errorf("callback must not be called for digest mismatch")
+		}
+	})
+
 	t.Run("response body is piped into cache.Put", func(t *testing.T) {
 		payload := []byte("body-bytes")
 		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -198,7 +260,7 @@ func TestWebBackend_Get(t *testing.T) {
 		rc := &recordingCache{}
 		d.Cache = rc
 
-		err = d.Get(&Source{Url: srv.URL}, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error { return nil })
+		err = d.Get(context.Background(), &Source{Url: srv.URL}, tui.NewQuietProgressReader(), func(reader io.ReadCloser) error { return nil })
 		if err != nil {
 			t.Fatalf("Get() unexpected error: %v", err)
 		}
@@ -214,7 +276,7 @@ type recordingCache struct {
 	putBody []byte
 }
 
-func (c *recordingCache) Put(key string, p tui.ProgressReader, reader io.ReadCloser) error {
+func (c *recordingCache) Put(ctx context.Context, key string, p tui.ProgressReader, reader io.ReadCloser) error {
 	if reader != nil {
 		b, err := io.ReadAll(reader)
 		if err != nil {
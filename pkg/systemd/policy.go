@@ -0,0 +1,53 @@
+package systemd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Policy controls what happens to the units of a tool after it was
+// installed or upgraded. Units are only reloaded by default.
+type Policy struct {
+	Enable           bool `json:"enable"`
+	Start            bool `json:"start"`
+	RestartOnUpgrade bool `json:"restart_on_upgrade"`
+}
+
+type Policies map[string]Policy
+
+func LoadPolicies(path string) (Policies, error) {
+	policies := make(Policies)
+
+	data, err := os.ReadFile(path) // #nosec G304 -- Path is built from configuration
+	if err != nil {
+		if os.IsNotExist(err) {
+			return policies, nil
+		}
+		return nil, fmt.Errorf("unable to read %s: %s", path, err)
+	}
+
+	err = json.Unmarshal(data, &policies)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %s", path, err)
+	}
+	return policies, nil
+}
+
+func SavePolicies(path string, policies Policies) error {
+	err := os.MkdirAll(filepath.Dir(path), 0755) // #nosec G301 -- Directory must be accessible by all users
+	if err != nil {
+		return fmt.Errorf("unable to create directory for %s: %s", path, err)
+	}
+
+	data, err := json.MarshalIndent(policies, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to marshal policies: %s", err)
+	}
+	err = os.WriteFile(path, data, 0644) // #nosec G306 -- File must be world-readable
+	if err != nil {
+		return fmt.Errorf("unable to write %s: %s", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package systemd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gitlab.com/uniget-org/cli/pkg/logging"
+)
+
+var unitExtensions = []string{
+	".service",
+	".socket",
+	".timer",
+	".path",
+	".mount",
+	".target",
+}
+
+// IsUnitFile checks whether a path from a tool manifest is a systemd unit
+func IsUnitFile(path string) bool {
+	if !strings.Contains(path, "systemd/system/") && !strings.Contains(path, "systemd/user/") {
+		return false
+	}
+	return slices.Contains(unitExtensions, filepath.Ext(path))
+}
+
+// IsTemplateUnit checks whether a unit requires an instance name
+func IsTemplateUnit(unit string) bool {
+	return strings.Contains(unit, "@.")
+}
+
+// FindUnits returns the sorted names of all units in the list of files
+func FindUnits(files []string) []string {
+	units := make([]string, 0)
+	for _, file := range files {
+		if !IsUnitFile(file) {
+			continue
+		}
+		unit := filepath.Base(file)
+		if !slices.Contains(units, unit) {
+			units = append(units, unit)
+		}
+	}
+	slices.Sort(units)
+	return units
+}
+
+// Systemctl runs systemctl for the system or the user instance of systemd
+type Systemctl struct {
+	User    bool
+	command func(name string, args ...string) *exec.Cmd
+}
+
+func NewSystemctl(user bool) *Systemctl {
+	return &Systemctl{
+		User:    user,
+		command: exec.Command,
+	}
+}
+
+// IsAvailable checks whether systemd is running and systemctl can be found
+func (s *Systemctl) IsAvailable() bool {
+	_, err := os.Stat("/run/systemd/system")
+	if err != nil {
+		return false
+	}
+	_, err = exec.LookPath("systemctl")
+	return err == nil
+}
+
+func (s *Systemctl) run(args ...string) error {
+	if s.User {
+		args = append([]string{"--user"}, args...)
+	}
+	logging.Debugf("Running systemctl %s", strings.Join(args, " "))
+
+	output, err := s.command("systemctl", args...).CombinedOutput() // #nosec G204 -- Units are taken from manifest
+	if err != nil {
+		return fmt.Errorf("systemctl %s failed: %s: %s", strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func (s *Systemctl) DaemonReload() error {
+	return s.run("daemon-reload")
+}
+
+func (s *Systemctl) Enable(units ...string) error {
+	return s.run(append([]string{"enable"}, units...)...)
+}
+
+// EnableNow enables and starts units
+func (s *Systemctl) EnableNow(units ...string) error {
+	return s.run(append([]string{"enable", "--now"}, units...)...)
+}
+
+func (s *Systemctl) Start(units ...string) error {
+	return s.run(append([]string{"start"}, units...)...)
+}
+
+// TryRestart restarts units which are running
+func (s *Systemctl) TryRestart(units ...string) error {
+	return s.run(append([]string{"try-restart"}, units...)...)
+}
+
+// DisableNow stops and disables units
+func (s *Systemctl) DisableNow(units ...string) error {
+	return s.run(append([]string{"disable", "--now"}, units...)...)
+}
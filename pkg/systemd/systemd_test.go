@@ -0,0 +1,83 @@
+package systemd
+
+import (
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFindUnits(t *testing.T) {
+	files := []string{
+		"usr/local/bin/foo",
+		"/etc/systemd/system/foo.service",
+		"/etc/systemd/system/foo.timer",
+		".config/systemd/user/bar@.service",
+		"usr/local/etc/systemd/system/foo.service",
+		"usr/local/share/doc/foo/foo.service",
+	}
+
+	units := FindUnits(files)
+	expected := []string{"bar@.service", "foo.service", "foo.timer"}
+	if !slices.Equal(units, expected) {
+		t.Errorf("expected %v, got %v", expected, units)
+	}
+
+	if !IsTemplateUnit("bar@.service") || IsTemplateUnit("foo.service") {
+		t.Errorf("unexpected result for template units")
+	}
+}
+
+func TestSystemctlArguments(t *testing.T) {
+	var calls []string
+	s := &Systemctl{
+		User: true,
+		command: func(name string, args ...string) *exec.Cmd {
+			calls = append(calls, name+" "+strings.Join(args, " "))
+			return exec.Command("true")
+		},
+	}
+
+	err := s.DisableNow("foo.service", "foo.timer")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = s.DaemonReload()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"systemctl --user disable --now foo.service foo.timer",
+		"systemctl --user daemon-reload",
+	}
+	if !slices.Equal(calls, expected) {
+		t.Errorf("expected %v, got %v", expected, calls)
+	}
+}
+
+func TestPolicies(t *testing.T) {
+	path := t.TempDir() + "/uniget/systemd.json"
+
+	policies, err := LoadPolicies(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(policies) != 0 {
+		t.Errorf("expected no policies, got %v", policies)
+	}
+
+	policies["foo"] = Policy{Enable: true, RestartOnUpgrade: true}
+	err = SavePolicies(path, policies)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	policies, err = LoadPolicies(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if policies["foo"] != (Policy{Enable: true, RestartOnUpgrade: true}) {
+		t.Errorf("unexpected policy: %v", policies["foo"])
+	}
+}
@@ -0,0 +1,222 @@
+package tool
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+const (
+	ChangeAdded        = "added"
+	ChangeRemoved      = "removed"
+	ChangeRenamed      = "renamed"
+	ChangeVersion      = "version"
+	ChangeDependencies = "dependencies"
+	ChangeLicense      = "license"
+	ChangeSources      = "sources"
+)
+
+var changeOrder = []string{
+	ChangeAdded,
+	ChangeRemoved,
+	ChangeRenamed,
+	ChangeVersion,
+	ChangeDependencies,
+	ChangeLicense,
+	ChangeSources,
+}
+
+type ToolChange struct {
+	Tool   string `json:"tool"`
+	Change string `json:"change"`
+	Old    string `json:"old,omitempty"`
+	New    string `json:"new,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type ToolsDiff struct {
+	OldRevision string       `json:"old_revision"`
+	NewRevision string       `json:"new_revision"`
+	Changes     []ToolChange `json:"changes"`
+}
+
+func (d ToolsDiff) Count(change string) int {
+	count := 0
+	for _, c := range d.Changes {
+		if c.Change == change {
+			count++
+		}
+	}
+	return count
+}
+
+// Diff compares two catalogs. Renames and removals are taken from the
+// lifecycle of the tools and take precedence over all other changes.
+func Diff(oldTools *Tools, newTools *Tools) ToolsDiff {
+	diff := ToolsDiff{
+		OldRevision: oldTools.Revision,
+		NewRevision: newTools.Revision,
+		Changes:     make([]ToolChange, 0),
+	}
+
+	oldByName := make(map[string]*Tool, len(oldTools.Tools))
+	for index := range oldTools.Tools {
+		oldByName[oldTools.Tools[index].Name] = &oldTools.Tools[index]
+	}
+	newByName := make(map[string]*Tool, len(newTools.Tools))
+	renameTargets := make(map[string]bool)
+	for index := range newTools.Tools {
+		newTool := &newTools.Tools[index]
+		newByName[newTool.Name] = newTool
+
+		oldTool, ok := oldByName[newTool.Name]
+		if len(newTool.Lifecycle.RenamedTo) > 0 && (!ok || len(oldTool.Lifecycle.RenamedTo) == 0) {
+			renameTargets[newTool.Lifecycle.RenamedTo] = true
+		}
+	}
+
+	for _, newTool := range newTools.Tools {
+		oldTool, ok := oldByName[newTool.Name]
+		wasRenamed := ok && len(oldTool.Lifecycle.RenamedTo) > 0
+		wasRemoved := ok && len(oldTool.Lifecycle.RemovedWithReason) > 0
+
+		switch {
+		case len(newTool.Lifecycle.RenamedTo) > 0:
+			if !wasRenamed {
+				diff.Changes = append(diff.Changes, ToolChange{
+					Tool:   newTool.Name,
+					Change: ChangeRenamed,
+					Old:    newTool.Name,
+					New:    newTool.Lifecycle.RenamedTo,
+				})
+			}
+
+		case len(newTool.Lifecycle.RemovedWithReason) > 0:
+			if !wasRemoved {
+				diff.Changes = append(diff.Changes, ToolChange{
+					Tool:   newTool.Name,
+					Change: ChangeRemoved,
+					Old:    newTool.Version,
+					Detail: newTool.Lifecycle.RemovedWithReason,
+				})
+			}
+
+		case !ok:
+			if !renameTargets[newTool.Name] {
+				diff.Changes = append(diff.Changes, ToolChange{
+					Tool:   newTool.Name,
+					Change: ChangeAdded,
+					New:    newTool.Version,
+					Detail: newTool.Description,
+				})
+			}
+
+		default:
+			diff.Changes = append(diff.Changes, diffTool(oldTool, &newTool)...)
+		}
+	}
+
+	for _, oldTool := range oldTools.Tools {
+		_, ok := newByName[oldTool.Name]
+		if ok || len(oldTool.Lifecycle.RenamedTo) > 0 || len(oldTool.Lifecycle.RemovedWithReason) > 0 {
+			continue
+		}
+		diff.Changes = append(diff.Changes, ToolChange{
+			Tool:   oldTool.Name,
+			Change: ChangeRemoved,
+			Old:    oldTool.Version,
+		})
+	}
+
+	sort.SliceStable(diff.Changes, func(i, j int) bool {
+		if diff.Changes[i].Tool != diff.Changes[j].Tool {
+			return diff.Changes[i].Tool < diff.Changes[j].Tool
+		}
+		return slices.Index(changeOrder, diff.Changes[i].Change) < slices.Index(changeOrder, diff.Changes[j].Change)
+	})
+
+	return diff
+}
+
+func diffTool(oldTool *Tool, newTool *Tool) []ToolChange {
+	changes := make([]ToolChange, 0)
+
+	if oldTool.Version != newTool.Version {
+		changes = append(changes, ToolChange{
+			Tool:   newTool.Name,
+			Change: ChangeVersion,
+			Old:    oldTool.Version,
+			New:    newTool.Version,
+		})
+	}
+
+	oldDependencies := dependencyList(oldTool)
+	newDependencies := dependencyList(newTool)
+	if !slices.Equal(oldDependencies, newDependencies) {
+		changes = append(changes, ToolChange{
+			Tool:   newTool.Name,
+			Change: ChangeDependencies,
+			Old:    strings.Join(oldDependencies, ", "),
+			New:    strings.Join(newDependencies, ", "),
+			Detail: describeListChange(oldDependencies, newDependencies),
+		})
+	}
+
+	if oldTool.License != newTool.License {
+		changes = append(changes, ToolChange{
+			Tool:   newTool.Name,
+			Change: ChangeLicense,
+			Old:    oldTool.License.Name,
+			New:    newTool.License.Name,
+		})
+	}
+
+	oldSources := sourceList(oldTool)
+	newSources := sourceList(newTool)
+	if !slices.Equal(oldSources, newSources) {
+		changes = append(changes, ToolChange{
+			Tool:   newTool.Name,
+			Change: ChangeSources,
+			Old:    strings.Join(oldSources, ", "),
+			New:    strings.Join(newSources, ", "),
+			Detail: describeListChange(oldSources, newSources),
+		})
+	}
+
+	return changes
+}
+
+// dependencyList combines runtime and build dependencies. Build
+// dependencies are prefixed with build:
+func dependencyList(t *Tool) []string {
+	dependencies := make([]string, 0, len(t.RuntimeDependencies)+len(t.BuildDependencies))
+	dependencies = append(dependencies, t.RuntimeDependencies...)
+	for _, dependency := range t.BuildDependencies {
+		dependencies = append(dependencies, "build:"+dependency)
+	}
+	sort.Strings(dependencies)
+	return dependencies
+}
+
+func sourceList(t *Tool) []string {
+	sources := make([]string, 0, len(t.Sources))
+	for _, source := range t.Sources {
+		sources = append(sources, source.Registry+"/"+source.Repository)
+	}
+	return sources
+}
+
+func describeListChange(oldList []string, newList []string) string {
+	parts := make([]string, 0)
+	for _, item := range newList {
+		if !slices.Contains(oldList, item) {
+			parts = append(parts, "+"+item)
+		}
+	}
+	for _, item := range oldList {
+		if !slices.Contains(newList, item) {
+			parts = append(parts, "-"+item)
+		}
+	}
+	return strings.Join(parts, " ")
+}
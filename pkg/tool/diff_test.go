@@ -0,0 +1,75 @@
+package tool
+
+import (
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	oldTools := &Tools{
+		Revision: "old",
+		Tools: []Tool{
+			{Name: "bumped", Version: "1.0.0", RuntimeDependencies: []string{"a"}},
+			{Name: "relicensed", Version: "1.0.0", License: License{Name: "MIT"}},
+			{Name: "gone", Version: "1.0.0"},
+			{Name: "deprecated", Version: "1.0.0"},
+			{Name: "oldname", Version: "1.0.0"},
+			{Name: "moved", Version: "1.0.0", Sources: []Source{{Registry: "ghcr.io", Repository: "uniget-org/tools"}}},
+			{Name: "unchanged", Version: "1.0.0"},
+		},
+	}
+	newTools := &Tools{
+		Revision: "new",
+		Tools: []Tool{
+			{Name: "bumped", Version: "1.1.0", RuntimeDependencies: []string{"b"}, BuildDependencies: []string{"a"}},
+			{Name: "relicensed", Version: "1.0.0", License: License{Name: "Apache-2.0"}},
+			{Name: "deprecated", Version: "1.0.0", Lifecycle: Lifecycle{RemovedWithReason: "unmaintained"}},
+			{Name: "oldname", Version: "1.0.0", Lifecycle: Lifecycle{RenamedTo: "newname"}},
+			{Name: "newname", Version: "1.0.0"},
+			{Name: "moved", Version: "1.0.0", Sources: []Source{{Registry: "docker.io", Repository: "uniget"}}},
+			{Name: "unchanged", Version: "1.0.0"},
+			{Name: "fresh", Version: "0.1.0", Description: "new tool"},
+		},
+	}
+
+	diff := Diff(oldTools, newTools)
+	if diff.OldRevision != "old" || diff.NewRevision != "new" {
+		t.Errorf("unexpected revisions %s and %s", diff.OldRevision, diff.NewRevision)
+	}
+
+	expected := []ToolChange{
+		{Tool: "bumped", Change: ChangeVersion, Old: "1.0.0", New: "1.1.0"},
+		{Tool: "bumped", Change: ChangeDependencies, Old: "a", New: "b, build:a", Detail: "+b +build:a -a"},
+		{Tool: "deprecated", Change: ChangeRemoved, Old: "1.0.0", Detail: "unmaintained"},
+		{Tool: "fresh", Change: ChangeAdded, New: "0.1.0", Detail: "new tool"},
+		{Tool: "gone", Change: ChangeRemoved, Old: "1.0.0"},
+		{Tool: "moved", Change: ChangeSources, Old: "ghcr.io/uniget-org/tools", New: "docker.io/uniget", Detail: "+docker.io/uniget -ghcr.io/uniget-org/tools"},
+		{Tool: "oldname", Change: ChangeRenamed, Old: "oldname", New: "newname"},
+		{Tool: "relicensed", Change: ChangeLicense, Old: "MIT", New: "Apache-2.0"},
+	}
+	if len(diff.Changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %d: %+v", len(expected), len(diff.Changes), diff.Changes)
+	}
+	for index, change := range expected {
+		if diff.Changes[index] != change {
+			t.Errorf("expected %+v, got %+v", change, diff.Changes[index])
+		}
+	}
+
+	if diff.Count(ChangeRemoved) != 2 {
+		t.Errorf("expected 2 removed tools, got %d", diff.Count(ChangeRemoved))
+	}
+}
+
+func TestDiffUnchanged(t *testing.T) {
+	tools := &Tools{
+		Tools: []Tool{
+			{Name: "foo", Version: "1.0.0", Lifecycle: Lifecycle{RenamedTo: "bar"}},
+			{Name: "bar", Version: "1.0.0"},
+		},
+	}
+
+	diff := Diff(tools, tools)
+	if len(diff.Changes) != 0 {
+		t.Errorf("expected no changes, got %+v", diff.Changes)
+	}
+}
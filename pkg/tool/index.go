@@ -0,0 +1,351 @@
+package tool
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+const (
+	FieldName         = "name"
+	FieldTags         = "tags"
+	FieldDependencies = "dependencies"
+	FieldDescription  = "description"
+)
+
+// Scores of a single term by field and kind of match. Exact names rank
+// over prefixes over tags over descriptions.
+var searchScores = map[string]map[string]int{
+	FieldName: {
+		matchExact:     100,
+		matchPrefix:    80,
+		matchSubstring: 60,
+		matchFuzzy:     50,
+	},
+	FieldTags: {
+		matchExact:  40,
+		matchPrefix: 30,
+		matchFuzzy:  25,
+	},
+	FieldDependencies: {
+		matchExact:  20,
+		matchPrefix: 15,
+		matchFuzzy:  10,
+	},
+	FieldDescription: {
+		matchExact:     15,
+		matchPrefix:    10,
+		matchSubstring: 8,
+		matchFuzzy:     5,
+	},
+}
+
+const (
+	matchExact     = "exact"
+	matchPrefix    = "prefix"
+	matchSubstring = "substring"
+	matchFuzzy     = "fuzzy"
+)
+
+type SearchFields struct {
+	Name         bool
+	Description  bool
+	Tags         bool
+	Dependencies bool
+}
+
+func (f SearchFields) includes(field string) bool {
+	switch field {
+	case FieldName:
+		return f.Name
+	case FieldDescription:
+		return f.Description
+	case FieldTags:
+		return f.Tags
+	case FieldDependencies:
+		return f.Dependencies
+	}
+	return false
+}
+
+var AllSearchFields = SearchFields{Name: true, Description: true, Tags: true, Dependencies: true}
+
+// SearchMatch is a token of a field which matched a search term
+type SearchMatch struct {
+	Field string `json:"field"`
+	Token string `json:"token"`
+}
+
+type SearchResult struct {
+	Tool    *Tool         `json:"tool"`
+	Score   int           `json:"score"`
+	Matches []SearchMatch `json:"matches"`
+}
+
+type posting struct {
+	tool  int
+	field string
+}
+
+// SearchIndex maps the tokens of names, tags, dependencies and
+// descriptions to the tools containing them
+type SearchIndex struct {
+	tools    *Tools
+	revision string
+	size     int
+	postings map[string][]posting
+	tokens   []string
+}
+
+var (
+	searchIndexMutex sync.Mutex
+	searchIndex      *SearchIndex
+)
+
+// GetSearchIndex returns the index for the tools. The index is built once
+// and reused until the metadata revision changes.
+func (tools *Tools) GetSearchIndex() *SearchIndex {
+	searchIndexMutex.Lock()
+	defer searchIndexMutex.Unlock()
+
+	if searchIndex == nil ||
+		searchIndex.tools != tools ||
+		searchIndex.revision != tools.Revision ||
+		searchIndex.size != len(tools.Tools) {
+		searchIndex = NewSearchIndex(tools)
+	}
+	return searchIndex
+}
+
+func NewSearchIndex(tools *Tools) *SearchIndex {
+	index := &SearchIndex{
+		tools:    tools,
+		revision: tools.Revision,
+		size:     len(tools.Tools),
+		postings: make(map[string][]posting),
+	}
+
+	for i, tool := range tools.Tools {
+		index.add(strings.ToLower(tool.Name), i, FieldName)
+		for _, tag := range tool.Tags {
+			index.add(strings.ToLower(tag), i, FieldTags)
+		}
+		for _, dependency := range tool.RuntimeDependencies {
+			index.add(strings.ToLower(dependency), i, FieldDependencies)
+		}
+		for _, token := range tokenize(tool.Description) {
+			index.add(token, i, FieldDescription)
+		}
+	}
+
+	index.tokens = make([]string, 0, len(index.postings))
+	for token := range index.postings {
+		index.tokens = append(index.tokens, token)
+	}
+	sort.Strings(index.tokens)
+
+	return index
+}
+
+func (index *SearchIndex) add(token string, tool int, field string) {
+	for _, p := range index.postings[token] {
+		if p.tool == tool && p.field == field {
+			return
+		}
+	}
+	index.postings[token] = append(index.postings[token], posting{tool: tool, field: field})
+}
+
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Search returns the tools matching all terms ordered by relevance. Tools
+// with the same score keep the order of the catalog.
+func (index *SearchIndex) Search(terms []string, fields SearchFields) []SearchResult {
+	var scores map[int]int
+	matches := make(map[int][]SearchMatch)
+
+	for _, term := range terms {
+		term = strings.ToLower(strings.TrimSpace(term))
+		if len(term) == 0 {
+			continue
+		}
+
+		termScores := make(map[int]int)
+		for _, token := range index.tokens {
+			kind, distance := matchToken(term, token)
+			if len(kind) == 0 {
+				continue
+			}
+
+			for _, p := range index.postings[token] {
+				if !fields.includes(p.field) {
+					continue
+				}
+				score := searchScores[p.field][kind]
+				if score == 0 {
+					continue
+				}
+				if kind == matchFuzzy {
+					score -= 5 * (distance - 1)
+				}
+				if score > termScores[p.tool] {
+					termScores[p.tool] = score
+				}
+				matches[p.tool] = append(matches[p.tool], SearchMatch{Field: p.field, Token: token})
+			}
+		}
+
+		if scores == nil {
+			scores = termScores
+			continue
+		}
+		for tool, score := range scores {
+			termScore, ok := termScores[tool]
+			if !ok {
+				delete(scores, tool)
+				continue
+			}
+			scores[tool] = score + termScore
+		}
+	}
+
+	results := make([]SearchResult, 0, len(scores))
+	for i := range index.tools.Tools {
+		score, ok := scores[i]
+		if !ok {
+			continue
+		}
+		results = append(results, SearchResult{
+			Tool:    &index.tools.Tools[i],
+			Score:   score,
+			Matches: matches[i],
+		})
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+
+	return results
+}
+
+// matchToken returns the kind of match and the number of typos
+func matchToken(term string, token string) (string, int) {
+	switch {
+	case term == token:
+		return matchExact, 0
+	case strings.HasPrefix(token, term):
+		return matchPrefix, 0
+	case len(term) > 1 && strings.Contains(token, term):
+		return matchSubstring, 0
+	}
+
+	typos := allowedTypos(term)
+	if typos == 0 {
+		return "", 0
+	}
+	// Compare with the prefix of longer tokens to tolerate typos while
+	// typing but rank them below complete tokens
+	truncated := false
+	if runes := []rune(token); len(runes) > len([]rune(term))+typos {
+		token = string(runes[:len([]rune(term))])
+		truncated = true
+	}
+	distance := editDistance(term, token)
+	if distance > typos {
+		return "", 0
+	}
+	if truncated {
+		distance++
+	}
+	return matchFuzzy, distance
+}
+
+func allowedTypos(term string) int {
+	switch {
+	case len(term) <= 3:
+		return 0
+	case len(term) <= 6:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// editDistance is the optimal string alignment distance counting
+// insertions, deletions, substitutions and transpositions
+func editDistance(a string, b string) int {
+	ra := []rune(a)
+	rb := []rune(b)
+
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+// Highlight marks all tokens of the given field which matched the search
+// terms in text
+func (r SearchResult) Highlight(field string, text string, mark func(string) string) string {
+	lower := strings.ToLower(text)
+	if len(lower) != len(text) {
+		return text
+	}
+	marked := make([]bool, len(text))
+	for _, match := range r.Matches {
+		if match.Field != field {
+			continue
+		}
+		for offset := 0; offset < len(lower); {
+			position := strings.Index(lower[offset:], match.Token)
+			if position < 0 {
+				break
+			}
+			for i := offset + position; i < offset+position+len(match.Token); i++ {
+				marked[i] = true
+			}
+			offset += position + len(match.Token)
+		}
+	}
+
+	var result strings.Builder
+	start := -1
+	for i := 0; i <= len(text); i++ {
+		if i < len(text) && marked[i] {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			result.WriteString(mark(text[start:i]))
+			start = -1
+		}
+		if i < len(text) {
+			result.WriteByte(text[i])
+		}
+	}
+	return result.String()
+}
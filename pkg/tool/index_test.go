@@ -0,0 +1,96 @@
+package tool
+
+import (
+	"strings"
+	"testing"
+)
+
+var testIndexTools = &Tools{
+	Revision: "test",
+	Tools: []Tool{
+		{Name: "kubectl-krew", Description: "Plugin manager for kubectl", Tags: []string{"kubernetes"}},
+		{Name: "helm", Description: "Package manager for Kubernetes", Tags: []string{"kubernetes", "package"}},
+		{Name: "kubectl", Description: "Kubernetes CLI", Tags: []string{"kubernetes"}},
+		{Name: "k9s", Description: "Terminal UI for Kubernetes clusters", RuntimeDependencies: []string{"kubectl"}},
+		{Name: "jq", Description: "Command-line JSON processor", Tags: []string{"json"}},
+	},
+}
+
+func resultNames(results []SearchResult) []string {
+	names := make([]string, 0, len(results))
+	for _, result := range results {
+		names = append(names, result.Tool.Name)
+	}
+	return names
+}
+
+func TestSearchRanking(t *testing.T) {
+	tt := []struct {
+		terms    []string
+		fields   SearchFields
+		expected string
+	}{
+		{terms: []string{"kubectl"}, fields: AllSearchFields, expected: "kubectl,kubectl-krew,k9s"},
+		{terms: []string{"kubetcl"}, fields: AllSearchFields, expected: "kubectl,kubectl-krew,k9s"},
+		{terms: []string{"kubernetes"}, fields: AllSearchFields, expected: "kubectl-krew,helm,kubectl,k9s"},
+		{terms: []string{"package", "manager"}, fields: AllSearchFields, expected: "helm"},
+		{terms: []string{"JSON"}, fields: SearchFields{Description: true}, expected: "jq"},
+		{terms: []string{"kubectl"}, fields: SearchFields{Dependencies: true}, expected: "k9s"},
+		{terms: []string{"[invalid"}, fields: AllSearchFields, expected: ""},
+	}
+
+	for _, tc := range tt {
+		results := testIndexTools.Search(tc.terms, tc.fields)
+		names := strings.Join(resultNames(results), ",")
+		if names != tc.expected {
+			t.Errorf("expected %s for %v, got %s", tc.expected, tc.terms, names)
+		}
+	}
+}
+
+func TestSearchIndexIsReused(t *testing.T) {
+	index := testIndexTools.GetSearchIndex()
+	if testIndexTools.GetSearchIndex() != index {
+		t.Errorf("expected index to be reused")
+	}
+
+	other := &Tools{Revision: "other"}
+	if other.GetSearchIndex() == index {
+		t.Errorf("expected new index for other tools")
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tt := []struct {
+		a        string
+		b        string
+		expected int
+	}{
+		{a: "kubectl", b: "kubectl", expected: 0},
+		{a: "kubetcl", b: "kubectl", expected: 1},
+		{a: "helm", b: "halm", expected: 1},
+		{a: "jq", b: "yq", expected: 1},
+		{a: "docker", b: "podman", expected: 5},
+	}
+
+	for _, tc := range tt {
+		distance := editDistance(tc.a, tc.b)
+		if distance != tc.expected {
+			t.Errorf("expected distance %d between %s and %s, got %d", tc.expected, tc.a, tc.b, distance)
+		}
+	}
+}
+
+func TestSearchHighlight(t *testing.T) {
+	results := testIndexTools.Search([]string{"manager"}, AllSearchFields)
+	if len(results) == 0 {
+		t.Fatalf("expected results")
+	}
+
+	highlighted := results[0].Highlight(FieldDescription, results[0].Tool.Description, func(s string) string {
+		return "<" + s + ">"
+	})
+	if highlighted != "Plugin <manager> for kubectl" {
+		t.Errorf("unexpected highlight: %s", highlighted)
+	}
+}
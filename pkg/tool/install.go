@@ -3,7 +3,6 @@ package tool
 import (
 	"fmt"
 	"io"
-	"path/filepath"
 	"strings"
 
 	"github.com/google/safearchive/tar"
@@ -78,13 +77,23 @@ func (tool *Tool) Inspect(w io.Writer, layer io.ReadCloser, rules []PathRewrite)
 			}
 			result = append(result, fmt.Sprintf("-%s %s", mode, header.Name))
 
-		case tar.TypeSymlink, tar.TypeLink:
+		case tar.TypeSymlink:
 			mode, err := myos.ConvertFileModeToString(header.Mode)
 			if err != nil {
 				return fmt.Errorf("unable to convert mode: %s", err)
 			}
 			result = append(result, fmt.Sprintf("l%s %s -> %s", mode, header.Name, header.Linkname))
 
+		case tar.TypeLink:
+			mode, err := myos.ConvertFileModeToString(header.Mode)
+			if err != nil {
+				return fmt.Errorf("unable to convert mode: %s", err)
+			}
+			if len(rules) > 0 {
+				header.Linkname = applyPathRewrites(header.Linkname, rules)
+			}
+			result = append(result, fmt.Sprintf("h%s %s => %s", mode, header.Name, header.Linkname))
+
 		default:
 			result = append(result, fmt.Sprintf("Unknown: %s", header.Name))
 		}
@@ -98,33 +107,20 @@ func (tool *Tool) Inspect(w io.Writer, layer io.ReadCloser, rules []PathRewrite)
 	return result, nil
 }
 
-func (tool *Tool) Install(w io.Writer, layer io.ReadCloser, rules []PathRewrite, patchFile func(path string) string) ([]string, error) {
+func (tool *Tool) Install(w io.Writer, layer io.ReadCloser, rules []PathRewrite, patchFile func(path string) string, opts archive.ExtractOptions) ([]string, error) {
 	installedFiles := []string{}
 
 	err := archive.ProcessTarContents(layer, func(reader *tar.Reader, header *tar.Header) error {
 		if header.Typeflag != tar.TypeDir {
 			if header.Typeflag == tar.TypeLink && len(header.Linkname) > 0 {
-				var err error
-
-				absName, err := filepath.Abs(header.Name)
-				if err != nil {
-					return fmt.Errorf("unable to get absolute path for %s: %s", header.Name, err)
-				}
-				absLinkname, err := filepath.Abs(header.Linkname)
-				if err != nil {
-					return fmt.Errorf("unable to get absolute path for %s: %s", header.Linkname, err)
-				}
-
-				logging.Tracef("Name: %s, Linkname: %s", absName, absLinkname)
-				header.Linkname, err = filepath.Rel(filepath.Dir(absName), absLinkname)
-				if err != nil {
-					return fmt.Errorf("unable to get relative linkname for %s: %s", header.Linkname, err)
-				}
-				logging.Tracef("    Relative linkname is %s", header.Linkname)
+				// Hard links point to a path relative to the root of the archive
+				// which is subject to the same rewrites as the link itself
+				header.Linkname = applyPathRewrites(header.Linkname, rules)
+				logging.Tracef("    Rewritten linkname is %s", header.Linkname)
 			}
 			header.Name = applyPathRewrites(header.Name, rules)
 
-			err := archive.CallbackExtractTarItem(reader, header)
+			err := archive.ExtractTarItem(reader, header, opts)
 			if err != nil {
 				return fmt.Errorf("error extracting tar item %s: %s", header.Name, err)
 			}
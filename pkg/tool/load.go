@@ -52,21 +52,21 @@ func LoadFromBytes(data []byte) (*Tools, error) {
 	return LoadFromReader(io.NopCloser(bytes.NewReader(data)))
 }
 
-func LoadMetadata(registry []string, repository []string, tag string, p tui.ProgressReader) (*Tools, error) {
-	t, err := containers.FindToolRef(registry, repository, "metadata", tag)
+func LoadMetadata(ctx context.Context, registry []string, repository []string, tag string, p tui.ProgressReader) (*Tools, error) {
+	t, err := containers.FindToolRef(ctx, registry, repository, "metadata", tag)
 	if err != nil {
 		return nil, fmt.Errorf("error finding metadata: %s", err)
 	}
 	rc := containers.GetRegclient()
 	defer func() {
-		err := rc.Close(context.Background(), t.GetRef())
+		err := rc.Close(ctx, t.GetRef())
 		if err != nil {
 			fmt.Printf("Error closing registry client: %s\n", err)
 		}
 	}()
 
 	var metadataJsonReader io.ReadCloser
-	err = containers.GetFirstLayerFromRegistry(context.Background(), rc, t.GetRef(), p, func(reader io.ReadCloser) error {
+	err = containers.GetFirstLayerFromRegistry(ctx, rc, t.GetRef(), p, func(reader io.ReadCloser) error {
 		return archive.ProcessTarContents(reader, func(reader *tar.Reader, header *tar.Header) error {
 			if header.Typeflag == tar.TypeReg && header.Name == "metadata.json" {
 				metadataJsonReader = io.NopCloser(reader)
@@ -88,21 +88,21 @@ func LoadMetadata(registry []string, repository []string, tag string, p tui.Prog
 	return tools, nil
 }
 
-func LoadMetadataFromRegistry(registry string, imageRepository string, metadataImageTag string, p tui.ProgressReader) ([]byte, error) {
-	t, err := containers.FindToolRef([]string{registry}, []string{imageRepository}, "metadata", metadataImageTag)
+func LoadMetadataFromRegistry(ctx context.Context, registry string, imageRepository string, metadataImageTag string, p tui.ProgressReader) ([]byte, error) {
+	t, err := containers.FindToolRef(ctx, []string{registry}, []string{imageRepository}, "metadata", metadataImageTag)
 	if err != nil {
 		return nil, fmt.Errorf("error finding metadata: %s", err)
 	}
 	rc := containers.GetRegclient()
 	defer func() {
-		err := rc.Close(context.Background(), t.GetRef())
+		err := rc.Close(ctx, t.GetRef())
 		if err != nil {
 			logging.Warning.Printfln("error closing registry client: %s", err)
 		}
 	}()
 
 	var metadata Tools
-	err = containers.GetFirstLayerFromRegistry(context.Background(), rc, t.GetRef(), p, func(reader io.ReadCloser) error {
+	err = containers.GetFirstLayerFromRegistry(ctx, rc, t.GetRef(), p, func(reader io.ReadCloser) error {
 		err = archive.ProcessTarContents(reader, func(reader *tar.Reader, header *tar.Header) error {
 			if header.Typeflag == tar.TypeReg && header.Name == "metadata.json" {
 				data, err := io.ReadAll(reader)
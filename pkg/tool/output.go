@@ -113,7 +113,8 @@ func (tool *Tool) Print(w io.Writer) {
 		//nolint:errcheck
 		fmt.Fprintf(w, " (%s)", tool.License.Link)
 	}
-	fmt.Print("\n")
+	//nolint:errcheck
+	fmt.Fprint(w, "\n")
 	//nolint:errcheck
 	fmt.Fprintf(w, "  Version: %s\n", tool.Version)
 
@@ -2,7 +2,6 @@ package tool
 
 import (
 	"fmt"
-	"regexp"
 
 	"gitlab.com/uniget-org/cli/pkg/logging"
 )
@@ -70,43 +69,24 @@ func (tools *Tools) GetByTags(tagNames []string) *Tools {
 func (tools *Tools) Find(term string, searchInName bool, searchInDesc bool, searchInTags bool, searchInDeps bool) *Tools {
 	var results = Tools{}
 
-	for _, tool := range tools.Tools {
-		matches := false
-
-		if searchInName && tool.MatchesName(term) {
-			matches = true
-		}
-
-		if searchInDesc && tool.MatchesDescription(term) {
-			matches = true
-		}
-
-		if searchInTags {
-			for _, tag := range tool.Tags {
-				match, err := regexp.MatchString(term, tag)
-				if err == nil && match {
-					matches = true
-				}
-			}
-		}
-
-		if searchInDeps {
-			for _, dep := range tool.RuntimeDependencies {
-				match, err := regexp.MatchString(term, dep)
-				if err == nil && match {
-					matches = true
-				}
-			}
-		}
-
-		if matches {
-			results.Tools = append(results.Tools, tool)
-		}
+	fields := SearchFields{
+		Name:         searchInName,
+		Description:  searchInDesc,
+		Tags:         searchInTags,
+		Dependencies: searchInDeps,
+	}
+	for _, result := range tools.GetSearchIndex().Search([]string{term}, fields) {
+		results.Tools = append(results.Tools, *result.Tool)
 	}
 
 	return &results
 }
 
+// Search returns the tools matching all terms ranked by relevance
+func (tools *Tools) Search(terms []string, fields SearchFields) []SearchResult {
+	return tools.GetSearchIndex().Search(terms, fields)
+}
+
 func (tools *Tools) GetNames() []string {
 	var toolNames []string
 
@@ -0,0 +1,197 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+
+	"gitlab.com/uniget-org/cli/pkg/logging"
+	"gitlab.com/uniget-org/cli/pkg/tui"
+)
+
+// Opener returns the content starting at offset
+type Opener func(ctx context.Context, offset int64) (io.ReadCloser, error)
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks an error returned by an Opener which must not be retried
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// fileWriter remembers write errors to distinguish them from read errors
+type fileWriter struct {
+	file *os.File
+	err  error
+}
+
+func (w *fileWriter) Write(p []byte) (int, error) {
+	n, err := w.file.Write(p)
+	if err != nil {
+		w.err = err
+	}
+	return n, err
+}
+
+// Download writes the content returned by open to a temporary file. An
+// interrupted transfer is resumed at the current offset after an
+// exponential backoff. The size and digest are verified before the
+// callback receives the file, if they are set. The temporary file is
+// removed when Download returns.
+func Download(ctx context.Context, open Opener, expected digest.Digest, size int64, p *tui.ProgressReader, callback func(reader io.ReadCloser) error) error {
+	algorithm := digest.SHA256
+	if len(expected) > 0 {
+		err := expected.Validate()
+		if err != nil {
+			return fmt.Errorf("invalid digest %s: %s", expected, err)
+		}
+		algorithm = expected.Algorithm()
+	}
+	digester := algorithm.Digester()
+
+	file, err := os.CreateTemp("", "uniget-download-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %s", err)
+	}
+	defer func() {
+		_ = file.Close()
+		err := os.Remove(file.Name())
+		if err != nil {
+			logging.Warning.Printfln("Failed to remove temporary file %s: %s", file.Name(), err)
+		}
+	}()
+	writer := &fileWriter{file: file}
+
+	opts := Current()
+	delay := opts.RetryDelay
+	var offset int64
+	for attempt := 0; ; attempt++ {
+		n, err := downloadFrom(ctx, open, offset, p, io.MultiWriter(writer, digester.Hash()))
+		offset += n
+		if err == nil {
+			break
+		}
+
+		if writer.err != nil {
+			return fmt.Errorf("failed to write %s: %s", file.Name(), writer.err)
+		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("download interrupted: %w", ctx.Err())
+		}
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return err
+		}
+		if attempt >= opts.Retries {
+			return fmt.Errorf("download failed after %d attempt(s): %w", attempt+1, err)
+		}
+
+		logging.Warning.Printfln("Download failed after %d bytes, retrying in %s: %s", offset, delay, err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("download interrupted: %w", ctx.Err())
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+
+	if size > 0 && offset != size {
+		return fmt.Errorf("expected %d bytes but downloaded %d", size, offset)
+	}
+	if len(expected) > 0 && digester.Digest() != expected {
+		return fmt.Errorf("digest mismatch: expected %s but got %s", expected, digester.Digest())
+	}
+
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		return fmt.Errorf("failed to rewind %s: %s", file.Name(), err)
+	}
+	return callback(io.NopCloser(file))
+}
+
+func downloadFrom(ctx context.Context, open Opener, offset int64, p *tui.ProgressReader, w io.Writer) (int64, error) {
+	body, err := open(ctx, offset)
+	if err != nil {
+		return 0, err
+	}
+	//nolint:errcheck
+	defer body.Close()
+
+	if p == nil {
+		return io.Copy(w, body)
+	}
+	p.SetReader(body)
+	return io.Copy(w, p)
+}
+
+// OpenURL returns an Opener which resumes downloads from url using range
+// requests. Retries are left to Download.
+func OpenURL(url string) Opener {
+	client := &http.Client{
+		Transport: BaseTransport(),
+	}
+
+	return func(ctx context.Context, offset int64) (io.ReadCloser, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, Permanent(fmt.Errorf("failed to create request: %w", err))
+		}
+		if offset > 0 {
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		}
+
+		resp, err := client.Do(req) // #nosec G704 -- URL is provided by the caller
+		if err != nil {
+			if !shouldRetry(nil, err) {
+				return nil, Permanent(err)
+			}
+			return nil, err
+		}
+
+		switch {
+		case offset > 0 && resp.StatusCode == http.StatusPartialContent:
+			if !strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", offset)) {
+				_ = resp.Body.Close()
+				return nil, fmt.Errorf("unexpected content range %s", resp.Header.Get("Content-Range"))
+			}
+			return resp.Body, nil
+
+		case resp.StatusCode == http.StatusOK:
+			if offset > 0 {
+				// Server does not support range requests
+				_, err = io.CopyN(io.Discard, resp.Body, offset)
+				if err != nil {
+					_ = resp.Body.Close()
+					return nil, fmt.Errorf("failed to skip %d bytes: %w", offset, err)
+				}
+			}
+			return resp.Body, nil
+		}
+
+		_ = resp.Body.Close()
+		err = fmt.Errorf("unexpected status %s", resp.Status)
+		if shouldRetry(resp, nil) || resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusRequestTimeout {
+			return nil, err
+		}
+		return nil, Permanent(err)
+	}
+}
@@ -0,0 +1,185 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+)
+
+var payload = bytes.Repeat([]byte("uniget"), 16*1024)
+
+// newFlakyServer drops the connection after sending half of the payload
+// for the first request. Range requests are only honoured if supportRange
+// is set.
+func newFlakyServer(t *testing.T, supportRange bool, ranges *[]string) *httptest.Server {
+	t.Helper()
+
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*ranges = append(*ranges, r.Header.Get("Range"))
+		if atomic.AddInt32(&hits, 1) == 1 {
+			w.Header().Set("Content-Length", strconv.Itoa(len(payload)))
+			_, _ = w.Write(payload[:len(payload)/2])
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		if !supportRange {
+			r.Header.Del("Range")
+		}
+		http.ServeContent(w, r, "payload", time.Time{}, bytes.NewReader(payload))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func download(t *testing.T, url string, expected digest.Digest, size int64) ([]byte, error) {
+	t.Helper()
+
+	var got []byte
+	err := Download(context.Background(), OpenURL(url), expected, size, nil, func(reader io.ReadCloser) error {
+		data, err := io.ReadAll(reader)
+		got = data
+		return err
+	})
+	return got, err
+}
+
+func TestDownloadResume(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	configure(t, Options{Retries: 3, RetryDelay: time.Millisecond})
+
+	tests := []struct {
+		name         string
+		supportRange bool
+		wantRange    string
+	}{
+		{name: "range request", supportRange: true, wantRange: "bytes=" + strconv.Itoa(len(payload)/2) + "-"},
+		{name: "no range support", supportRange: false, wantRange: "bytes=" + strconv.Itoa(len(payload)/2) + "-"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ranges []string
+			srv := newFlakyServer(t, tt.supportRange, &ranges)
+
+			got, err := download(t, srv.URL, digest.FromBytes(payload), int64(len(payload)))
+			if err != nil {
+				t.Fatalf("Download() unexpected error: %v", err)
+			}
+			if !bytes.Equal(got, payload) {
+				t.Errorf("Download() returned %d bytes, want %d matching bytes", len(got), len(payload))
+			}
+			if len(ranges) != 2 || ranges[0] != "" || ranges[1] != tt.wantRange {
+				t.Errorf("requested ranges = %q, want [\"\" %q]", ranges, tt.wantRange)
+			}
+		})
+	}
+
+	entries, err := os.ReadDir(os.Getenv("TMPDIR"))
+	if err != nil {
+		t.Fatalf("ReadDir() unexpected error: %v", err)
+	}
+	if len(entries) > 0 {
+		t.Errorf("expected temporary files to be removed, found %d", len(entries))
+	}
+}
+
+func TestDownloadVerification(t *testing.T) {
+	configure(t, Options{Retries: 0})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(payload)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name     string
+		expected digest.Digest
+		size     int64
+		fails    bool
+	}{
+		{name: "no verification", expected: "", size: 0},
+		{name: "matching digest and size", expected: digest.FromBytes(payload), size: int64(len(payload))},
+		{name: "digest mismatch", expected: digest.FromString("other"), fails: true},
+		{name: "size mismatch", size: int64(len(payload)) + 1, fails: true},
+		{name: "invalid digest", expected: "sha256:1234", fails: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			err := Download(context.Background(), OpenURL(srv.URL), tt.expected, tt.size, nil, func(reader io.ReadCloser) error {
+				called = true
+				return nil
+			})
+			if tt.fails {
+				if err == nil {
+					t.Errorf("Download() expected error, got nil")
+				}
+				if called {
+					t.Errorf("callback must not be called when verification fails")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Download() unexpected error: %v", err)
+			}
+			if !called {
+				t.Errorf("callback was not called")
+			}
+		})
+	}
+}
+
+func TestDownloadErrors(t *testing.T) {
+	configure(t, Options{Retries: 2, RetryDelay: time.Millisecond})
+
+	tests := []struct {
+		name     string
+		status   int
+		wantHits int32
+	}{
+		{name: "not found is permanent", status: http.StatusNotFound, wantHits: 1},
+		{name: "server error is retried", status: http.StatusServiceUnavailable, wantHits: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hits int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&hits, 1)
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			_, err := download(t, srv.URL, "", 0)
+			if err == nil || !strings.Contains(err.Error(), strconv.Itoa(tt.status)) {
+				t.Errorf("Download() error = %v, want status %d", err, tt.status)
+			}
+			if got := atomic.LoadInt32(&hits); got != tt.wantHits {
+				t.Errorf("hits = %d, want %d", got, tt.wantHits)
+			}
+		})
+	}
+}
+
+func TestDownloadCancelled(t *testing.T) {
+	configure(t, Options{Retries: 3, RetryDelay: time.Hour})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := Download(ctx, OpenURL(srv.URL), "", 0, nil, func(reader io.ReadCloser) error { return nil })
+	if err == nil || ctx.Err() == nil {
+		t.Errorf("Download() error = %v, want interruption", err)
+	}
+}
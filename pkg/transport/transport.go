@@ -0,0 +1,257 @@
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+
+	"gitlab.com/uniget-org/cli/pkg/logging"
+)
+
+const (
+	TLSEnabled  = "enabled"
+	TLSInsecure = "insecure"
+	TLSDisabled = "disabled"
+)
+
+// UserAgent is added to all requests which do not set a user agent
+var UserAgent = "uniget"
+
+type Options struct {
+	// CACertificates is a PEM file with certificates which are trusted
+	// in addition to the system roots
+	CACertificates string
+	// Hosts maps host[:port] to one of enabled, insecure or disabled.
+	// Insecure skips certificate verification and disabled uses plain
+	// HTTP.
+	Hosts map[string]string
+	// Retries is the number of retries for failed GET and HEAD requests
+	Retries int
+	// RetryDelay is doubled for every retry
+	RetryDelay time.Duration
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Hosts:      map[string]string{},
+		Retries:    3,
+		RetryDelay: time.Second,
+	}
+}
+
+var (
+	mutex   sync.Mutex
+	options = DefaultOptions()
+	base    http.RoundTripper
+)
+
+// Configure replaces the options used by all transports returned
+// afterwards. Proxies are read from HTTPS_PROXY, HTTP_PROXY and
+// NO_PROXY when Configure is called.
+func Configure(opts Options) error {
+	for host, mode := range opts.Hosts {
+		if mode != TLSEnabled && mode != TLSInsecure && mode != TLSDisabled {
+			return fmt.Errorf("invalid TLS setting %s for host %s", mode, host)
+		}
+	}
+	if opts.Retries < 0 {
+		return fmt.Errorf("retries must not be negative")
+	}
+
+	t, err := newHostTransport(opts)
+	if err != nil {
+		return err
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	options = opts
+	base = t
+	return nil
+}
+
+// Current returns the options passed to Configure
+func Current() Options {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return options
+}
+
+// BaseTransport returns a transport without retries for clients which
+// retry requests on their own
+func BaseTransport() http.RoundTripper {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if base == nil {
+		t, err := newHostTransport(options)
+		if err != nil {
+			logging.Warning.Printfln("Unable to create transport: %s", err)
+			return http.DefaultTransport
+		}
+		base = t
+	}
+	return base
+}
+
+// Transport returns a transport which retries failed GET and HEAD requests
+func Transport() http.RoundTripper {
+	opts := Current()
+	return &retryTransport{
+		base:    BaseTransport(),
+		retries: opts.Retries,
+		delay:   opts.RetryDelay,
+	}
+}
+
+func Client() *http.Client {
+	return &http.Client{
+		Transport: Transport(),
+	}
+}
+
+func newTLSConfig(opts Options) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if len(opts.CACertificates) == 0 {
+		return tlsConfig, nil
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		logging.Debugf("Unable to load system certificates: %s", err)
+		pool = x509.NewCertPool()
+	}
+	data, err := os.ReadFile(opts.CACertificates)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read CA certificates from %s: %s", opts.CACertificates, err)
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in %s", opts.CACertificates)
+	}
+	tlsConfig.RootCAs = pool
+	return tlsConfig, nil
+}
+
+func newHostTransport(opts Options) (*hostTransport, error) {
+	tlsConfig, err := newTLSConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	proxy := httpproxy.FromEnvironment().ProxyFunc()
+	secure := &http.Transport{
+		Proxy: func(req *http.Request) (*url.URL, error) {
+			return proxy(req.URL)
+		},
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 60 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig:       tlsConfig,
+	}
+	insecure := secure.Clone()
+	insecure.TLSClientConfig.InsecureSkipVerify = true // #nosec G402 -- Only used for hosts configured as insecure
+
+	hosts := make(map[string]string, len(opts.Hosts))
+	for host, mode := range opts.Hosts {
+		hosts[host] = mode
+	}
+
+	return &hostTransport{
+		hosts:    hosts,
+		secure:   secure,
+		insecure: insecure,
+	}, nil
+}
+
+// hostTransport applies per-host TLS settings and adds the user agent
+type hostTransport struct {
+	hosts    map[string]string
+	secure   *http.Transport
+	insecure *http.Transport
+}
+
+func (t *hostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	mode := t.hosts[req.URL.Host]
+	if len(req.Header.Get("User-Agent")) == 0 || (mode == TLSDisabled && req.URL.Scheme == "https") {
+		req = req.Clone(req.Context())
+		if len(req.Header.Get("User-Agent")) == 0 {
+			req.Header.Set("User-Agent", UserAgent)
+		}
+		if mode == TLSDisabled {
+			req.URL.Scheme = "http"
+		}
+	}
+
+	if mode == TLSInsecure {
+		return t.insecure.RoundTrip(req)
+	}
+	return t.secure.RoundTrip(req)
+}
+
+// retryTransport retries GET and HEAD requests after network errors,
+// rate limiting and server errors
+type retryTransport struct {
+	base    http.RoundTripper
+	retries int
+	delay   time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return t.base.RoundTrip(req)
+	}
+
+	delay := t.delay
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if attempt >= t.retries || !shouldRetry(resp, err) || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		if err != nil {
+			logging.Debugf("Retrying %s %s in %s after error: %s", req.Method, req.URL.Redacted(), delay, err)
+		} else {
+			logging.Debugf("Retrying %s %s in %s after status %s", req.Method, req.URL.Redacted(), delay, resp.Status)
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+			_ = resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		var certErr *tls.CertificateVerificationError
+		return !errors.As(err, &certErr)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests ||
+		resp.StatusCode == http.StatusBadGateway ||
+		resp.StatusCode == http.StatusServiceUnavailable ||
+		resp.StatusCode == http.StatusGatewayTimeout
+}
@@ -0,0 +1,217 @@
+package transport
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func configure(t *testing.T, opts Options) {
+	t.Helper()
+
+	err := Configure(opts)
+	if err != nil {
+		t.Fatalf("Configure() unexpected error: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = Configure(DefaultOptions())
+	})
+}
+
+func get(t *testing.T, method string, url string) (*http.Response, error) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		t.Fatalf("NewRequest() unexpected error: %v", err)
+	}
+	resp, err := Client().Do(req)
+	if err == nil {
+		t.Cleanup(func() {
+			_ = resp.Body.Close()
+		})
+	}
+	return resp, err
+}
+
+func TestConfigureInvalid(t *testing.T) {
+	t.Cleanup(func() {
+		_ = Configure(DefaultOptions())
+	})
+
+	tests := []struct {
+		name string
+		opts Options
+	}{
+		{name: "invalid TLS setting", opts: Options{Hosts: map[string]string{"registry.example.com": "maybe"}}},
+		{name: "negative retries", opts: Options{Retries: -1}},
+		{name: "missing CA file", opts: Options{CACertificates: filepath.Join(t.TempDir(), "missing.pem")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Configure(tt.opts); err == nil {
+				t.Errorf("Configure() expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	var userAgent atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent.Store(r.Header.Get("User-Agent"))
+	}))
+	defer srv.Close()
+	configure(t, DefaultOptions())
+
+	_, err := get(t, http.MethodGet, srv.URL)
+	if err != nil {
+		t.Fatalf("Get() unexpected error: %v", err)
+	}
+	if got := userAgent.Load(); got != UserAgent {
+		t.Errorf("User-Agent = %q, want %q", got, UserAgent)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatalf("NewRequest() unexpected error: %v", err)
+	}
+	req.Header.Set("User-Agent", "custom")
+	resp, err := Client().Do(req)
+	if err != nil {
+		t.Fatalf("Do() unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+	if got := userAgent.Load(); got != "custom" {
+		t.Errorf("User-Agent = %q, want %q", got, "custom")
+	}
+}
+
+func TestRetries(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		retries    int
+		failures   int32
+		wantStatus int
+		wantHits   int32
+	}{
+		{name: "recovers", method: http.MethodGet, retries: 3, failures: 2, wantStatus: http.StatusOK, wantHits: 3},
+		{name: "gives up", method: http.MethodGet, retries: 1, failures: 2, wantStatus: http.StatusServiceUnavailable, wantHits: 2},
+		{name: "no retries", method: http.MethodHead, retries: 0, failures: 1, wantStatus: http.StatusServiceUnavailable, wantHits: 1},
+		{name: "post is not retried", method: http.MethodPost, retries: 3, failures: 1, wantStatus: http.StatusServiceUnavailable, wantHits: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hits int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&hits, 1) <= tt.failures {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+			}))
+			defer srv.Close()
+			configure(t, Options{Retries: tt.retries, RetryDelay: time.Millisecond})
+
+			resp, err := get(t, tt.method, srv.URL)
+			if err != nil {
+				t.Fatalf("Do() unexpected error: %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if got := atomic.LoadInt32(&hits); got != tt.wantHits {
+				t.Errorf("hits = %d, want %d", got, tt.wantHits)
+			}
+		})
+	}
+}
+
+func TestCACertificates(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	configure(t, Options{})
+	_, err := get(t, http.MethodGet, srv.URL)
+	if err == nil {
+		t.Fatalf("Get() expected certificate error, got nil")
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	err = os.WriteFile(caFile, data, 0600)
+	if err != nil {
+		t.Fatalf("WriteFile() unexpected error: %v", err)
+	}
+	configure(t, Options{CACertificates: caFile})
+	_, err = get(t, http.MethodGet, srv.URL)
+	if err != nil {
+		t.Errorf("Get() unexpected error with CA certificates: %v", err)
+	}
+
+	err = os.WriteFile(caFile, []byte("not a certificate"), 0600)
+	if err != nil {
+		t.Fatalf("WriteFile() unexpected error: %v", err)
+	}
+	if err := Configure(Options{CACertificates: caFile}); err == nil {
+		t.Errorf("Configure() expected error for file without certificates, got nil")
+	}
+}
+
+func TestHostTLS(t *testing.T) {
+	tlsSrv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer tlsSrv.Close()
+	plainSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer plainSrv.Close()
+	tlsHost := strings.TrimPrefix(tlsSrv.URL, "https://")
+	plainHost := strings.TrimPrefix(plainSrv.URL, "http://")
+
+	configure(t, Options{Hosts: map[string]string{
+		tlsHost:   TLSInsecure,
+		plainHost: TLSDisabled,
+	}})
+
+	_, err := get(t, http.MethodGet, tlsSrv.URL)
+	if err != nil {
+		t.Errorf("Get() unexpected error for insecure host: %v", err)
+	}
+	_, err = get(t, http.MethodGet, "https://"+plainHost)
+	if err != nil {
+		t.Errorf("Get() unexpected error for host with TLS disabled: %v", err)
+	}
+}
+
+func TestProxy(t *testing.T) {
+	var proxied atomic.Value
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied.Store(r.URL.String())
+	}))
+	defer proxy.Close()
+
+	t.Setenv("HTTP_PROXY", proxy.URL)
+	t.Setenv("NO_PROXY", "")
+	configure(t, Options{})
+	_, err := get(t, http.MethodGet, "http://uniget.invalid/metadata")
+	if err != nil {
+		t.Fatalf("Get() unexpected error: %v", err)
+	}
+	if got := proxied.Load(); got != "http://uniget.invalid/metadata" {
+		t.Errorf("proxied URL = %v, want %q", got, "http://uniget.invalid/metadata")
+	}
+
+	proxied = atomic.Value{}
+	t.Setenv("NO_PROXY", "uniget.invalid")
+	configure(t, Options{})
+	_, err = get(t, http.MethodGet, "http://uniget.invalid/metadata")
+	if err == nil {
+		t.Errorf("Get() expected error for host excluded from proxy, got nil")
+	}
+	if got := proxied.Load(); got != nil {
+		t.Errorf("proxied URL = %v, want no request", got)
+	}
+}
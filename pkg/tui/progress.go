@@ -8,6 +8,7 @@ type ProgressReader struct {
 	total         int64
 	onTotalUpdate func(int64)
 	onProgress    func(int64)
+	onDigest      func(string)
 }
 
 func NewProgressReader(onTotalUpdate func(int64), onProgress func(int64)) ProgressReader {
@@ -32,6 +33,16 @@ func (pr *ProgressReader) SetTotal(n int64) {
 	}
 }
 
+func (pr *ProgressReader) OnDigest(onDigest func(string)) {
+	pr.onDigest = onDigest
+}
+
+func (pr *ProgressReader) SetDigest(digest string) {
+	if pr.onDigest != nil {
+		pr.onDigest(digest)
+	}
+}
+
 func (pr *ProgressReader) IsQuiet() bool {
 	return pr.onTotalUpdate == nil && pr.onProgress == nil
 }
@@ -0,0 +1,65 @@
+package uniget
+
+import (
+	"context"
+
+	"gitlab.com/uniget-org/cli/internal/client"
+)
+
+// Client installs, upgrades and uninstalls tools from the catalog. A Client
+// is not safe for concurrent use.
+type Client = client.Client
+
+// Options configure a Client. The zero value installs into the default
+// system locations without a cache and discards human readable output.
+// Network settings are read from the environment and the registries file
+// and apply to the whole process (see New).
+type Options = client.Options
+
+type (
+	InstallOptions   = client.InstallOptions
+	UninstallOptions = client.UninstallOptions
+	Plan             = client.Plan
+	Result           = client.Result
+	ToolResult       = client.ToolResult
+	FailedError      = client.FailedError
+	MetadataUpdate   = client.MetadataUpdate
+)
+
+const (
+	ResultSucceeded = client.ResultSucceeded
+	ResultSkipped   = client.ResultSkipped
+	ResultFailed    = client.ResultFailed
+
+	HookPreInstall    = client.HookPreInstall
+	HookPostInstall   = client.HookPostInstall
+	HookPreUninstall  = client.HookPreUninstall
+	HookPostUninstall = client.HookPostUninstall
+)
+
+var (
+	ErrConflicts        = client.ErrConflicts
+	ErrRenamedOrRemoved = client.ErrRenamedOrRemoved
+)
+
+// New creates a client and loads the metadata. Metadata is downloaded if
+// it is missing or if AutoUpdate is set.
+//
+// New replaces the process-wide HTTP transport, registry credentials,
+// mirrors and manifest timeout with the settings from UNIGET_* variables
+// and the registries file. This affects all clients and other users of
+// these packages in the same process.
+func New(ctx context.Context, opts Options) (*Client, error) {
+	return client.New(ctx, opts)
+}
+
+// ProcessHooks calls callback for every hook file in path. Hidden files
+// and directories are ignored and symlinks are rejected.
+func ProcessHooks(path string, callback func(file string) error) error {
+	return client.ProcessHooks(path, callback)
+}
+
+// RunHook executes a single hook file
+func RunHook(ctx context.Context, hookFile string, args ...string) error {
+	return client.RunHook(ctx, hookFile, args...)
+}